@@ -63,6 +63,9 @@ func main() {
 			cloudProvider,
 			op.SubnetProvider,
 			op.SecurityGroupProvider,
+			op.CapacityReservationProvider,
+			op.PlacementGroupProvider,
+			op.SnapshotProvider,
 			op.InstanceProfileProvider,
 			op.InstanceProvider,
 			op.PricingProvider,
@@ -285,6 +285,7 @@ func getIdentMapping(identName string) (string, error) {
 		"batcherSubsystem":        "cloudprovider_batcher",
 		"cloudProviderSubsystem":  "cloudprovider",
 		"stateSubsystem":          "cluster_state",
+		"nodeClassSubsystem":      "ec2nodeclass",
 	}
 	if v, ok := identMapping[identName]; ok {
 		return v, nil
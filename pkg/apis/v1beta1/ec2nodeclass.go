@@ -16,7 +16,10 @@ package v1beta1
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/imdario/mergo"
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,6 +37,15 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:validation:MaxItems:=30
 	// +required
 	SubnetSelectorTerms []SubnetSelectorTerm `json:"subnetSelectorTerms" hash:"ignore"`
+	// PodSubnetSelectorTerms is a list of pod subnet selector terms. The terms are ORed.
+	// When using VPC CNI custom networking, pods are assigned IP addresses from the subnets of the ENIConfigs rather
+	// than the subnet of the node. Selecting those subnets here lets Karpenter account for pod IP addresses against
+	// them, and zones whose pod subnets are exhausted aren't launched into.
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id']",rule="self.all(x, has(x.tags) || has(x.id))"
+	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in podSubnetSelectorTerms",rule="!self.all(x, has(x.id) && has(x.tags))"
+	// +kubebuilder:validation:MaxItems:=30
+	// +optional
+	PodSubnetSelectorTerms []SubnetSelectorTerm `json:"podSubnetSelectorTerms,omitempty" hash:"ignore"`
 	// SecurityGroupSelectorTerms is a list of or security group selector terms. The terms are ORed.
 	// +kubebuilder:validation:XValidation:message="securityGroupSelectorTerms cannot be empty",rule="self.size() != 0"
 	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name))"
@@ -42,15 +54,34 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:validation:MaxItems:=30
 	// +required
 	SecurityGroupSelectorTerms []SecurityGroupSelectorTerm `json:"securityGroupSelectorTerms" hash:"ignore"`
+	// CapacityReservationSelectorTerms is a list of capacity reservation selector terms. The terms are ORed.
+	// Capacity reservations that are selected are offered as the "reserved" capacity type.
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id']",rule="self.all(x, has(x.tags) || has(x.id))"
+	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in capacityReservationSelectorTerms",rule="!self.all(x, has(x.id) && (has(x.tags) || has(x.ownerID)))"
+	// +kubebuilder:validation:MaxItems:=30
+	// +optional
+	CapacityReservationSelectorTerms []CapacityReservationSelectorTerm `json:"capacityReservationSelectorTerms,omitempty" hash:"ignore"`
+	// PlacementGroupSelector selects the placement group that instances are launched into.
+	// +kubebuilder:validation:XValidation:message="expected exactly one of ['tags', 'id', 'name']",rule="[has(self.tags), has(self.id), has(self.name)].filter(x, x).size() == 1"
+	// +optional
+	PlacementGroupSelector *PlacementGroupSelectorTerm `json:"placementGroupSelector,omitempty" hash:"ignore"`
 	// AssociatePublicIPAddress controls if public IP addresses are assigned to instances that are launched with the nodeclass.
 	// +optional
 	AssociatePublicIPAddress *bool `json:"associatePublicIPAddress,omitempty"`
 	// AMISelectorTerms is a list of or ami selector terms. The terms are ORed.
-	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name))"
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'id', 'name', 'alias', 'ssmParameter']",rule="self.all(x, has(x.tags) || has(x.id) || has(x.name) || has(x.alias) || has(x.ssmParameter))"
 	// +kubebuilder:validation:XValidation:message="'id' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.all(x, has(x.id) && (has(x.tags) || has(x.name) || has(x.owner)))"
+	// +kubebuilder:validation:XValidation:message="'alias' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.exists(x, has(x.alias) && (has(x.id) || has(x.tags) || has(x.name) || has(x.owner) || has(x.ssmParameter)))"
+	// +kubebuilder:validation:XValidation:message="'alias' is mutually exclusive, cannot be set with a combination of other amiSelectorTerms",rule="!(self.exists(x, has(x.alias)) && self.size() != 1)"
+	// +kubebuilder:validation:XValidation:message="'ssmParameter' is mutually exclusive, cannot be set with a combination of other fields in amiSelectorTerms",rule="!self.exists(x, has(x.ssmParameter) && (has(x.id) || has(x.tags) || has(x.name) || has(x.owner)))"
 	// +kubebuilder:validation:MaxItems:=30
 	// +optional
 	AMISelectorTerms []AMISelectorTerm `json:"amiSelectorTerms,omitempty" hash:"ignore"`
+	// AMIRollout stages the rollout of newly resolved AMIs. When set, new AMIs are only used for a percentage of launches
+	// until enough nodes launched with them have become ready, and they're rolled back if those nodes fail. When unset,
+	// new AMIs are used for all launches as soon as they're resolved.
+	// +optional
+	AMIRollout *AMIRollout `json:"amiRollout,omitempty" hash:"ignore"`
 	// AMIFamily is the AMI family that instances use.
 	// +kubebuilder:validation:Enum:={AL2,AL2023,Bottlerocket,Ubuntu,Custom,Windows2019,Windows2022}
 	// +required
@@ -60,15 +91,17 @@ type EC2NodeClassSpec struct {
 	// this UserData to ensure nodes are being provisioned with the correct configuration.
 	// +optional
 	UserData *string `json:"userData,omitempty"`
-	// Role is the AWS identity that nodes use. This field is immutable.
+	// Kubelet defines args to be used when configuring kubelet on provisioned nodes. Fields that are set in a NodePool's
+	// kubelet configuration take precedence over the same fields in the EC2NodeClass, and map fields are merged key by key.
+	// +optional
+	Kubelet *corev1beta1.KubeletConfiguration `json:"kubelet,omitempty"`
+	// Role is the AWS identity that nodes use.
 	// This field is mutually exclusive from instanceProfile.
-	// Marking this field as immutable avoids concerns around terminating managed instance profiles from running instances.
-	// This field may be made mutable in the future, assuming the correct garbage collection and drift handling is implemented
-	// for the old instance profiles on an update.
+	// Changing the role creates a new instance profile for it and drifts the nodes that were launched with the
+	// previous instance profile. The previous instance profile is deleted once no instances are using it.
 	// +kubebuilder:validation:XValidation:rule="self != ''",message="role cannot be empty"
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="immutable field changed"
 	// +optional
-	Role string `json:"role,omitempty"`
+	Role string `json:"role,omitempty" hash:"ignore"`
 	// InstanceProfile is the AWS entity that instances use.
 	// This field is mutually exclusive from role.
 	// The instance profile should already have a role assigned to it that Karpenter
@@ -77,6 +110,8 @@ type EC2NodeClassSpec struct {
 	// +optional
 	InstanceProfile *string `json:"instanceProfile,omitempty"`
 	// Tags to be applied on ec2 resources like instances and launch templates.
+	// Tag changes are applied to the instances launched with the EC2NodeClass in place, rather than drifting them.
+	// Tag values may be templates that reference the NodeClaim, e.g. {{ .NodePool }} or {{ .Labels.team }}.
 	// +kubebuilder:validation:XValidation:message="empty tag keys aren't supported",rule="self.all(k, k != '')"
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching kubernetes.io/cluster/",rule="self.all(k, !k.startsWith('kubernetes.io/cluster') )"
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.sh/nodepool",rule="self.all(k, k != 'karpenter.sh/nodepool')"
@@ -84,7 +119,7 @@ type EC2NodeClassSpec struct {
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.sh/nodeclaim",rule="self.all(k, k !='karpenter.sh/nodeclaim')"
 	// +kubebuilder:validation:XValidation:message="tag contains a restricted tag matching karpenter.k8s.aws/ec2nodeclass",rule="self.all(k, k !='karpenter.k8s.aws/ec2nodeclass')"
 	// +optional
-	Tags map[string]string `json:"tags,omitempty"`
+	Tags map[string]string `json:"tags,omitempty" hash:"ignore"`
 	// BlockDeviceMappings to be applied to provisioned nodes.
 	// +kubebuilder:validation:XValidation:message="must have only one blockDeviceMappings with rootVolume",rule="self.filter(x, has(x.rootVolume)?x.rootVolume==true:false).size() <= 1"
 	// +kubebuilder:validation:MaxItems:=50
@@ -93,6 +128,26 @@ type EC2NodeClassSpec struct {
 	// InstanceStorePolicy specifies how to handle instance-store disks.
 	// +optional
 	InstanceStorePolicy *InstanceStorePolicy `json:"instanceStorePolicy,omitempty"`
+	// InstanceStoreMountPath is the directory that instance-store disks are mounted under when using the Mount
+	// instanceStorePolicy. Each disk is mounted in its own subdirectory. On Bottlerocket, the disks are instead combined
+	// into a single RAID0 volume that's mounted at this path, so only one volume is exposed. Defaults to /mnt/k8s-disks.
+	// Can't be set with the AL2023 amiFamily, since nodeadm always mounts disks under /mnt/k8s-disks.
+	// +kubebuilder:validation:Pattern:="^/[a-zA-Z0-9/._-]*$"
+	// +kubebuilder:validation:MaxLength:=255
+	// +optional
+	InstanceStoreMountPath *string `json:"instanceStoreMountPath,omitempty"`
+	// CPUOptions configures the number of CPU cores and threads per core of launched instances. Instance types that
+	// don't support the configured options aren't launched.
+	// +optional
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+	// CreditSpecification configures the CPU credit option of burstable performance instances
+	// +kubebuilder:validation:XValidation:message="estimatedSurplusUtilization can only be set with unlimited cpuCredits",rule="!has(self.estimatedSurplusUtilization) || self.cpuCredits == 'unlimited'"
+	// +optional
+	CreditSpecification *CreditSpecification `json:"creditSpecification,omitempty"`
+	// NetworkOptions configures the IPv6 addresses and prefixes that are assigned to the primary network interface of
+	// launched instances.
+	// +optional
+	NetworkOptions *NetworkOptions `json:"networkOptions,omitempty"`
 	// DetailedMonitoring controls if detailed monitoring is enabled for instances that are launched
 	// +optional
 	DetailedMonitoring *bool `json:"detailedMonitoring,omitempty"`
@@ -107,18 +162,120 @@ type EC2NodeClassSpec struct {
 	// Refer to recommended, security best practices
 	// (https://aws.github.io/aws-eks-best-practices/security/docs/iam/#restrict-access-to-the-instance-profile-assigned-to-the-worker-node)
 	// for limiting exposure of Instance Metadata and User Data to pods.
-	// If omitted, defaults to httpEndpoint enabled, with httpProtocolIPv6
-	// disabled, with httpPutResponseLimit of 2, and with httpTokens
-	// required.
-	// +kubebuilder:default={"httpEndpoint":"enabled","httpProtocolIPv6":"disabled","httpPutResponseHopLimit":2,"httpTokens":"required"}
+	// If omitted, defaults to httpEndpoint enabled, with httpPutResponseLimit
+	// of 2, and with httpTokens required. Unless it's set, httpProtocolIPv6 is
+	// enabled for ipv6Only node classes and IPv6 clusters, and disabled otherwise.
+	// +kubebuilder:default={"httpEndpoint":"enabled","httpPutResponseHopLimit":2,"httpTokens":"required"}
 	// +optional
 	MetadataOptions *MetadataOptions `json:"metadataOptions,omitempty"`
+	// AllocationStrategy configures how EC2 Fleet chooses between the instance types and zones that are compatible
+	// with a NodeClaim when launching its instance.
+	// +kubebuilder:validation:XValidation:message="instanceTypePriorities requires the capacity-optimized-prioritized spot or prioritized on-demand allocation strategy",rule="!has(self.instanceTypePriorities) || (has(self.spot) && self.spot == 'capacity-optimized-prioritized') || (has(self.onDemand) && self.onDemand == 'prioritized')"
+	// +kubebuilder:validation:XValidation:message="instanceTypePriorities can't be used with attribute-based instance type selection",rule="!has(self.instanceTypePriorities) || !has(self.instanceTypeSelection) || self.instanceTypeSelection != 'attribute-based'"
+	// +optional
+	AllocationStrategy *AllocationStrategy `json:"allocationStrategy,omitempty" hash:"ignore"`
+	// Tenancy configures the tenancy of launched instances. If omitted, instances run on shared hardware.
+	// +kubebuilder:validation:XValidation:message="hostResourceGroupArn and hostAffinity can only be set with host tenancy",rule="self.type == 'host' || (!has(self.hostResourceGroupArn) && !has(self.hostAffinity))"
+	// +optional
+	Tenancy *Tenancy `json:"tenancy,omitempty"`
 	// Context is a Reserved field in EC2 APIs
 	// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateFleet.html
 	// +optional
 	Context *string `json:"context,omitempty"`
 }
 
+// AllocationStrategy configures the allocation strategies that EC2 Fleet uses when launching instances
+type AllocationStrategy struct {
+	// Spot is the allocation strategy used when launching spot instances. Defaults to price-capacity-optimized.
+	// +kubebuilder:validation:Enum:={price-capacity-optimized,capacity-optimized,capacity-optimized-prioritized,lowest-price,diversified}
+	// +optional
+	Spot *string `json:"spot,omitempty"`
+	// OnDemand is the allocation strategy used when launching on-demand instances. Defaults to lowest-price.
+	// +kubebuilder:validation:Enum:={lowest-price,prioritized}
+	// +optional
+	OnDemand *string `json:"onDemand,omitempty"`
+	// InstanceTypePriorities is a map of instance type names to their launch priority, which is used by the
+	// capacity-optimized-prioritized spot and prioritized on-demand allocation strategies. Lower values have a higher
+	// priority. Instance types without a priority have the lowest priority.
+	// +kubebuilder:validation:XValidation:message="instance type priorities must be non-negative",rule="self.all(k, self[k] >= 0)"
+	// +kubebuilder:validation:MaxProperties:=100
+	// +optional
+	InstanceTypePriorities map[string]int32 `json:"instanceTypePriorities,omitempty"`
+	// InstanceTypeSelection controls how the instance types that are compatible with a NodeClaim are passed to EC2 Fleet.
+	// With explicit selection, the cheapest compatible instance types are each passed as a launch template override.
+	// With attribute-based selection, the compatible instance types are described as instance requirements, which
+	// allows EC2 Fleet to choose from a larger pool of instance types. Defaults to explicit.
+	// +kubebuilder:validation:Enum:={explicit,attribute-based}
+	// +optional
+	InstanceTypeSelection *string `json:"instanceTypeSelection,omitempty"`
+}
+
+// CPUOptions configures the processor of launched instances
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores to enable. Defaults to the instance type's default core count.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	CoreCount *int64 `json:"coreCount,omitempty"`
+	// ThreadsPerCore is the number of threads to enable per CPU core. Set to 1 to disable simultaneous multithreading.
+	// Defaults to the instance type's default threads per core.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=2
+	// +optional
+	ThreadsPerCore *int64 `json:"threadsPerCore,omitempty"`
+	// AMDSEVSNP controls whether AMD SEV-SNP is enabled. Only instance types that support AMD SEV-SNP are launched
+	// when it is enabled.
+	// +kubebuilder:validation:Enum:={enabled,disabled}
+	// +optional
+	AMDSEVSNP *string `json:"amdSevSnp,omitempty"`
+}
+
+// NetworkOptions configures the addresses of the primary network interface of launched instances
+type NetworkOptions struct {
+	// IPv6PrefixCount is the number of /80 IPv6 prefixes to assign to the primary network interface. In IPv6 clusters,
+	// the VPC CNI assigns pod IP addresses from prefixes on the network interface.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	IPv6PrefixCount *int64 `json:"ipv6PrefixCount,omitempty"`
+	// IPv6Only launches instances without an IPv4 address, using an IPv6 address as the node IP. Instances can only be
+	// launched into IPv6-only subnets, and the cluster must use the IPv6 IP family. The instance metadata service's
+	// IPv6 endpoint is enabled unless httpProtocolIPv6 is set in metadataOptions.
+	// +optional
+	IPv6Only *bool `json:"ipv6Only,omitempty"`
+}
+
+// CreditSpecification configures how burstable performance instances are billed for CPU usage above their baseline
+type CreditSpecification struct {
+	// CPUCredits is the credit option for CPU usage of burstable performance instances.
+	// +kubebuilder:validation:Enum:={standard,unlimited}
+	// +required
+	CPUCredits string `json:"cpuCredits"`
+	// EstimatedSurplusUtilization is the estimated percentage of vCPU time that instances in unlimited mode spend
+	// bursting above their baseline. When set, the cost of the surplus credits this accrues is added to the price of
+	// burstable instance types, so that they are compared fairly against fixed performance instance types.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	EstimatedSurplusUtilization *int32 `json:"estimatedSurplusUtilization,omitempty" hash:"ignore"`
+}
+
+// Tenancy configures whether instances run on shared hardware, on hardware dedicated to a single account, or on
+// Dedicated Hosts
+type Tenancy struct {
+	// Type is the tenancy of launched instances.
+	// +kubebuilder:validation:Enum:={default,dedicated,host}
+	// +required
+	Type string `json:"type"`
+	// HostResourceGroupARN is the ARN of the host resource group to launch instances into. Only valid with host tenancy.
+	// +kubebuilder:validation:Pattern:="^arn:[^:]+:resource-groups:[^:]*:[0-9]*:group/.+$"
+	// +optional
+	HostResourceGroupARN *string `json:"hostResourceGroupArn,omitempty"`
+	// HostAffinity controls whether an instance that is stopped and restarted relaunches onto the same Dedicated Host.
+	// Only valid with host tenancy.
+	// +kubebuilder:validation:Enum:={default,host}
+	// +optional
+	HostAffinity *string `json:"hostAffinity,omitempty"`
+}
+
 // SubnetSelectorTerm defines selection logic for a subnet used by Karpenter to launch nodes.
 // If multiple fields are used for selection, the requirements are ANDed.
 type SubnetSelectorTerm struct {
@@ -152,6 +309,44 @@ type SecurityGroupSelectorTerm struct {
 	Name string `json:"name,omitempty"`
 }
 
+// CapacityReservationSelectorTerm defines selection logic for a capacity reservation used by Karpenter to launch nodes.
+// If multiple fields are used for selection, the requirements are ANDed.
+type CapacityReservationSelectorTerm struct {
+	// Tags is a map of key/value tags used to select capacity reservations
+	// Specifying '*' for a value selects all values for a given tag key.
+	// +kubebuilder:validation:XValidation:message="empty tag keys or values aren't supported",rule="self.all(k, k != '' && self[k] != '')"
+	// +kubebuilder:validation:MaxProperties:=20
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+	// ID is the capacity reservation id in EC2
+	// +kubebuilder:validation:Pattern:="^cr-[0-9a-z]+$"
+	// +optional
+	ID string `json:"id,omitempty"`
+	// OwnerID is the id of the AWS account that owns the capacity reservation.
+	// This can be used to select reservations that are shared with this account.
+	// +kubebuilder:validation:Pattern:="^[0-9]{12}$"
+	// +optional
+	OwnerID string `json:"ownerID,omitempty"`
+}
+
+// PlacementGroupSelectorTerm defines selection logic for the placement group used by Karpenter to launch nodes.
+// The selector must resolve to exactly one placement group.
+type PlacementGroupSelectorTerm struct {
+	// Tags is a map of key/value tags used to select the placement group
+	// Specifying '*' for a value selects all values for a given tag key.
+	// +kubebuilder:validation:XValidation:message="empty tag keys or values aren't supported",rule="self.all(k, k != '' && self[k] != '')"
+	// +kubebuilder:validation:MaxProperties:=20
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+	// ID is the placement group id in EC2
+	// +kubebuilder:validation:Pattern:="^pg-[0-9a-z]+$"
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Name is the placement group name in EC2.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
 // AMISelectorTerm defines selection logic for an ami used by Karpenter to launch nodes.
 // If multiple fields are used for selection, the requirements are ANDed.
 type AMISelectorTerm struct {
@@ -165,6 +360,19 @@ type AMISelectorTerm struct {
 	// +kubebuilder:validation:Pattern:="ami-[0-9a-z]+"
 	// +optional
 	ID string `json:"id,omitempty"`
+	// Alias selects the EKS optimized AMIs of an AMI family, in the format "family@version". The family must match the
+	// amiFamily of the EC2NodeClass, e.g. "al2023@v20240807" or "bottlerocket@v1.20.5". The version is either pinned to a
+	// specific AMI release, in that family's version format, or "latest", which resolves the most recent release.
+	// Windows families only support "latest".
+	// +kubebuilder:validation:XValidation:message="'alias' is improperly formatted, must match the format 'family@version'",rule="self.matches('^[a-zA-Z0-9]+@.+$')"
+	// +kubebuilder:validation:XValidation:message="family is not supported, must be one of the following: 'al2', 'al2023', 'bottlerocket', 'windows2019', 'windows2022'",rule="self.split('@')[0] in ['al2','al2023','bottlerocket','windows2019','windows2022']"
+	// +kubebuilder:validation:XValidation:message="windows families may only specify version 'latest'",rule="self.split('@')[0] in ['windows2019','windows2022'] ? self.split('@')[1] == 'latest' : true"
+	// +kubebuilder:validation:MaxLength=30
+	// +optional
+	Alias string `json:"alias,omitempty"`
+	// SSMParameter is the name or ARN of an SSM parameter whose value is the ami id in EC2.
+	// +optional
+	SSMParameter string `json:"ssmParameter,omitempty"`
 	// Name is the ami name in EC2.
 	// This value is the name field, which is different from the name tag.
 	// +optional
@@ -175,6 +383,39 @@ type AMISelectorTerm struct {
 	Owner string `json:"owner,omitempty"`
 }
 
+// SnapshotSelectorTerm defines selection logic for the EBS snapshot that a volume is created from.
+// If multiple fields are used for selection, the requirements are ANDed.
+type SnapshotSelectorTerm struct {
+	// Tags is a map of key/value tags used to select snapshots
+	// Specifying '*' for a value selects all values for a given tag key.
+	// +kubebuilder:validation:XValidation:message="empty tag keys or values aren't supported",rule="self.all(k, k != '' && self[k] != '')"
+	// +kubebuilder:validation:MaxProperties:=20
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+	// Name is the value of the Name tag of the snapshot
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Owner is the owner for the snapshot.
+	// You can specify an AWS account ID, "self" or "amazon". Defaults to "self".
+	// +optional
+	Owner string `json:"owner,omitempty"`
+}
+
+// AMIRollout configures how newly resolved AMIs are rolled out
+type AMIRollout struct {
+	// CanaryPercentage is the percentage of launches, including the replacements of drifted nodes, that use newly
+	// resolved AMIs while they're being rolled out.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +required
+	CanaryPercentage int32 `json:"canaryPercentage"`
+	// PromotionThreshold is the number of nodes launched with newly resolved AMIs that must become ready before the
+	// AMIs are promoted and used for all launches.
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	PromotionThreshold int32 `json:"promotionThreshold"`
+}
+
 // MetadataOptions contains parameters for specifying the exposure of the
 // Instance Metadata Service to provisioned EC2 nodes.
 type MetadataOptions struct {
@@ -189,9 +430,9 @@ type MetadataOptions struct {
 	// +optional
 	HTTPEndpoint *string `json:"httpEndpoint,omitempty"`
 	// HTTPProtocolIPv6 enables or disables the IPv6 endpoint for the instance metadata
-	// service on provisioned nodes. If metadata options is non-nil, but this parameter
-	// is not specified, the default state is "disabled".
-	// +kubebuilder:default=disabled
+	// service on provisioned nodes. If this parameter is not specified, the default
+	// state is "enabled" for ipv6Only node classes and IPv6 clusters, and "disabled"
+	// otherwise.
 	// +kubebuilder:validation:Enum:={enabled,disabled}
 	// +optional
 	HTTPProtocolIPv6 *string `json:"httpProtocolIPv6,omitempty"`
@@ -230,7 +471,9 @@ type BlockDeviceMapping struct {
 	// +required
 	DeviceName *string `json:"deviceName,omitempty"`
 	// EBS contains parameters used to automatically set up EBS volumes when an instance is launched.
-	// +kubebuilder:validation:XValidation:message="snapshotID or volumeSize must be defined",rule="has(self.snapshotID) || has(self.volumeSize)"
+	// +kubebuilder:validation:XValidation:message="snapshotID, snapshotSelectorTerms, volumeSize or volumeSizePolicy must be defined",rule="has(self.snapshotID) || has(self.snapshotSelectorTerms) || has(self.volumeSize) || has(self.volumeSizePolicy)"
+	// +kubebuilder:validation:XValidation:message="volumeSize and volumeSizePolicy are mutually exclusive",rule="!(has(self.volumeSize) && has(self.volumeSizePolicy))"
+	// +kubebuilder:validation:XValidation:message="snapshotID and snapshotSelectorTerms are mutually exclusive",rule="!(has(self.snapshotID) && has(self.snapshotSelectorTerms))"
 	// +required
 	EBS *BlockDevice `json:"ebs,omitempty"`
 	// RootVolume is a flag indicating if this device is mounted as kubelet root dir. You can
@@ -274,6 +517,12 @@ type BlockDevice struct {
 	// SnapshotID is the ID of an EBS snapshot
 	// +optional
 	SnapshotID *string `json:"snapshotID,omitempty"`
+	// SnapshotSelectorTerms is a list of snapshot selector terms. The terms are ORed. The volume is created from the
+	// most recently created snapshot that matches any of the terms, and is drifted when a newer snapshot is selected.
+	// +kubebuilder:validation:XValidation:message="expected at least one, got none, ['tags', 'name']",rule="self.all(x, has(x.tags) || has(x.name))"
+	// +kubebuilder:validation:MaxItems:=30
+	// +optional
+	SnapshotSelectorTerms []SnapshotSelectorTerm `json:"snapshotSelectorTerms,omitempty" hash:"ignore"`
 	// Throughput to provision for a gp3 volume, with a maximum of 1,000 MiB/s.
 	// Valid Range: Minimum value of 125. Maximum value of 1000.
 	// +optional
@@ -296,6 +545,10 @@ type BlockDevice struct {
 	// +kubebuilder:validation:Type:=string
 	// +optional
 	VolumeSize *resource.Quantity `json:"volumeSize,omitempty" hash:"string"`
+	// VolumeSizePolicy sizes the volume for each instance type that it's launched with, rather than using a fixed
+	// VolumeSize. Instance types whose volumes resolve to different sizes are launched with different launch templates.
+	// +optional
+	VolumeSizePolicy *VolumeSizePolicy `json:"volumeSizePolicy,omitempty"`
 	// VolumeType of the block device.
 	// For more information, see Amazon EBS volume types (https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/EBSVolumeTypes.html)
 	// in the Amazon Elastic Compute Cloud User Guide.
@@ -304,8 +557,43 @@ type BlockDevice struct {
 	VolumeType *string `json:"volumeType,omitempty"`
 }
 
+// VolumeSizePolicy computes the size of a volume as Base + PerVCPU * vCPUs + PerPod * max pods of the instance type,
+// bounded by Min and Max. The result is rounded up to the nearest Gi.
+type VolumeSizePolicy struct {
+	// Base is the size of the volume before the vCPU and pod based sizes are added.
+	// +kubebuilder:validation:Pattern:="^((?:[1-9][0-9]{0,3}|[1-4][0-9]{4}|[5][0-8][0-9]{3}|59000)Gi|(?:[1-9][0-9]{0,3}|[1-5][0-9]{4}|[6][0-3][0-9]{3}|64000)G|([1-9]||[1-5][0-7]|58)Ti|([1-9]||[1-5][0-9]|6[0-3]|64)T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +required
+	Base *resource.Quantity `json:"base" hash:"string"`
+	// PerVCPU is the size added to the volume for each vCPU of the instance type.
+	// +kubebuilder:validation:Pattern:="^[0-9]+(Mi|Gi|M|G)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	PerVCPU *resource.Quantity `json:"perVCPU,omitempty" hash:"string"`
+	// PerPod is the size added to the volume for each pod that the instance type supports.
+	// +kubebuilder:validation:Pattern:="^[0-9]+(Mi|Gi|M|G)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	PerPod *resource.Quantity `json:"perPod,omitempty" hash:"string"`
+	// Min is the smallest size that the volume is created with.
+	// +kubebuilder:validation:Pattern:="^((?:[1-9][0-9]{0,3}|[1-4][0-9]{4}|[5][0-8][0-9]{3}|59000)Gi|(?:[1-9][0-9]{0,3}|[1-5][0-9]{4}|[6][0-3][0-9]{3}|64000)G|([1-9]||[1-5][0-7]|58)Ti|([1-9]||[1-5][0-9]|6[0-3]|64)T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Min *resource.Quantity `json:"min,omitempty" hash:"string"`
+	// Max is the largest size that the volume is created with.
+	// +kubebuilder:validation:Pattern:="^((?:[1-9][0-9]{0,3}|[1-4][0-9]{4}|[5][0-8][0-9]{3}|59000)Gi|(?:[1-9][0-9]{0,3}|[1-5][0-9]{4}|[6][0-3][0-9]{3}|64000)G|([1-9]||[1-5][0-7]|58)Ti|([1-9]||[1-5][0-9]|6[0-3]|64)T)$"
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty" hash:"string"`
+}
+
 // InstanceStorePolicy enumerates options for configuring instance store disks.
-// +kubebuilder:validation:Enum={RAID0}
+// +kubebuilder:validation:Enum={RAID0,RAID0Containerd,Mount,ExtendedResource}
 type InstanceStorePolicy string
 
 const (
@@ -314,8 +602,25 @@ const (
 	// ephemeral storage for more and faster node ephemeral-storage. The node's ephemeral storage can be shared among
 	// pods that request ephemeral storage and container images that are downloaded to the node.
 	InstanceStorePolicyRAID0 InstanceStorePolicy = "RAID0"
+	// InstanceStorePolicyRAID0Containerd configures a RAID-0 array that includes all ephemeral NVMe instance storage
+	// disks and uses it only for the containerd state directory (`/var/lib/containerd`). Container images and writable
+	// layers use the array, while kubelet and pod ephemeral-storage remain on the root volume.
+	InstanceStorePolicyRAID0Containerd InstanceStorePolicy = "RAID0Containerd"
+	// InstanceStorePolicyMount formats and mounts each ephemeral NVMe instance storage disk individually under the
+	// instanceStoreMountPath, for use by local persistent volume provisioners. Bottlerocket can't mount disks
+	// individually, so on Bottlerocket this yields a single volume: the disks are combined into one RAID0 array that's
+	// mounted at the instanceStoreMountPath.
+	InstanceStorePolicyMount InstanceStorePolicy = "Mount"
+	// InstanceStorePolicyExtendedResource leaves ephemeral NVMe instance storage disks untouched and advertises the
+	// number of disks as the karpenter.k8s.aws/instance-store-disks extended resource. A device plugin must register
+	// the resource on the node.
+	InstanceStorePolicyExtendedResource InstanceStorePolicy = "ExtendedResource"
 )
 
+// DefaultInstanceStoreMountPath is the directory that instance-store disks are mounted under with the Mount policy
+// when no instanceStoreMountPath is set
+const DefaultInstanceStoreMountPath = "/mnt/k8s-disks"
+
 // EC2NodeClass is the Schema for the EC2NodeClass API
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=ec2nodeclasses,scope=Cluster,categories=karpenter,shortName={ec2nc,ec2ncs}
@@ -325,8 +630,14 @@ type EC2NodeClass struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// +kubebuilder:validation:XValidation:message="amiSelectorTerms is required when amiFamily == 'Custom'",rule="self.amiFamily == 'Custom' ? self.amiSelectorTerms.size() != 0 : true"
+	// +kubebuilder:validation:XValidation:message="the family of the 'alias' amiSelectorTerm must match amiFamily",rule="has(self.amiSelectorTerms) ? self.amiSelectorTerms.all(x, !has(x.alias) || x.alias.split('@')[0] == self.amiFamily.lowerAscii()) : true"
 	// +kubebuilder:validation:XValidation:message="must specify exactly one of ['role', 'instanceProfile']",rule="(has(self.role) && !has(self.instanceProfile)) || (!has(self.role) && has(self.instanceProfile))"
 	// +kubebuilder:validation:XValidation:message="changing from 'instanceProfile' to 'role' is not supported. You must delete and recreate this node class if you want to change this.",rule="(has(oldSelf.role) && has(self.role)) || (has(oldSelf.instanceProfile) && has(self.instanceProfile))"
+	// +kubebuilder:validation:XValidation:message="instanceStoreMountPath can only be set with the Mount instanceStorePolicy",rule="!has(self.instanceStoreMountPath) || (has(self.instanceStorePolicy) && self.instanceStorePolicy == 'Mount')"
+	// +kubebuilder:validation:XValidation:message="instanceStoreMountPath isn't supported with the AL2023 amiFamily",rule="!has(self.instanceStoreMountPath) || self.amiFamily != 'AL2023'"
+	// +kubebuilder:validation:XValidation:message="ipv6Only can't be set with associatePublicIPAddress",rule="!has(self.networkOptions) || !has(self.networkOptions.ipv6Only) || !self.networkOptions.ipv6Only || !has(self.associatePublicIPAddress) || !self.associatePublicIPAddress"
+	// +kubebuilder:validation:XValidation:message="ipv6Only requires httpProtocolIPv6 to be enabled in metadataOptions",rule="!has(self.networkOptions) || !has(self.networkOptions.ipv6Only) || !self.networkOptions.ipv6Only || !has(self.metadataOptions) || !has(self.metadataOptions.httpProtocolIPv6) || self.metadataOptions.httpProtocolIPv6 == 'enabled'"
+	// +kubebuilder:validation:XValidation:message="ipv6Only isn't supported with Windows amiFamilies",rule="!has(self.networkOptions) || !has(self.networkOptions.ipv6Only) || !self.networkOptions.ipv6Only || !(self.amiFamily in ['Windows2019', 'Windows2022'])"
 	Spec   EC2NodeClassSpec   `json:"spec,omitempty"`
 	Status EC2NodeClassStatus `json:"status,omitempty"`
 }
@@ -335,7 +646,10 @@ type EC2NodeClass struct {
 // 1. A field changes its default value for an existing field that is already hashed
 // 2. A field is added to the hash calculation with an already-set value
 // 3. A field is removed from the hash calculations
-const EC2NodeClassHashVersion = "v2"
+const EC2NodeClassHashVersion = "v4"
+
+// AliasVersionLatest is the alias version that resolves the most recent release of an AMI family's default AMIs
+const AliasVersionLatest = "latest"
 
 func (in *EC2NodeClass) Hash() string {
 	return fmt.Sprint(lo.Must(hashstructure.Hash(in.Spec, hashstructure.FormatV2, &hashstructure.HashOptions{
@@ -345,10 +659,42 @@ func (in *EC2NodeClass) Hash() string {
 	})))
 }
 
+// FieldHashes returns the hash of each spec field that contributes to Hash, keyed by the field's JSON name. Fields that
+// aren't set are omitted. Comparing the field hashes that a NodeClaim was launched with against those of its
+// EC2NodeClass identifies the fields that it has drifted from.
+func (in *EC2NodeClass) FieldHashes() map[string]string {
+	hashes := map[string]string{}
+	spec := reflect.ValueOf(in.Spec)
+	for i := 0; i < spec.NumField(); i++ {
+		field := spec.Type().Field(i)
+		if field.Tag.Get("hash") == "ignore" || spec.Field(i).IsZero() {
+			continue
+		}
+		hashes[strings.Split(field.Tag.Get("json"), ",")[0]] = fmt.Sprint(lo.Must(hashstructure.Hash(spec.Field(i).Interface(), hashstructure.FormatV2, &hashstructure.HashOptions{
+			SlicesAsSets:    true,
+			IgnoreZeroValue: true,
+			ZeroNil:         true,
+		})))
+	}
+	return hashes
+}
+
 func (in *EC2NodeClass) InstanceProfileName(clusterName, region string) string {
 	return fmt.Sprintf("%s_%d", clusterName, lo.Must(hashstructure.Hash(fmt.Sprintf("%s%s", region, in.Name), hashstructure.FormatV2, nil)))
 }
 
+// RoleInstanceProfileName is the name of the instance profile that's created for the role once the role has been
+// changed from the one that the instance profile named by InstanceProfileName was created for
+func (in *EC2NodeClass) RoleInstanceProfileName(clusterName, region string) string {
+	return fmt.Sprintf("%s_%d", clusterName, lo.Must(hashstructure.Hash(fmt.Sprintf("%s%s%s", region, in.Name, in.Spec.Role), hashstructure.FormatV2, nil)))
+}
+
+// InstanceProfilePath is the IAM path of the instance profiles created for the EC2NodeClass. Instance profiles are
+// scoped to the EC2NodeClass by its UID so that the ones created for previous roles can be found and cleaned up.
+func (in *EC2NodeClass) InstanceProfilePath(clusterName string) string {
+	return fmt.Sprintf("/karpenter/%s/%s/", clusterName, in.UID)
+}
+
 func (in *EC2NodeClass) InstanceProfileRole() string {
 	return in.Spec.Role
 }
@@ -361,6 +707,66 @@ func (in *EC2NodeClass) InstanceProfileTags(clusterName string) map[string]strin
 	})
 }
 
+// Alias returns the alias amiSelectorTerm of the EC2NodeClass, or an empty string if the AMIs aren't selected by alias
+func (in *EC2NodeClass) Alias() string {
+	term, _ := lo.Find(in.Spec.AMISelectorTerms, func(term AMISelectorTerm) bool { return term.Alias != "" })
+	return term.Alias
+}
+
+// AMIVersion returns the release of the AMI family's default AMIs that instances are launched with. The release can
+// be pinned with an alias amiSelectorTerm, otherwise the latest release is used.
+func (in *EC2NodeClass) AMIVersion() string {
+	if _, version, ok := strings.Cut(in.Alias(), "@"); ok {
+		return version
+	}
+	return AliasVersionLatest
+}
+
+// TenancyType returns the tenancy that instances are launched with, defaulting to shared tenancy if unset
+func (in *EC2NodeClass) TenancyType() string {
+	if in.Spec.Tenancy == nil || in.Spec.Tenancy.Type == "" {
+		return TenancyDefault
+	}
+	return in.Spec.Tenancy.Type
+}
+
+// IPv6Only returns whether instances are launched without an IPv4 address
+func (in *EC2NodeClass) IPv6Only() bool {
+	return in.Spec.NetworkOptions != nil && lo.FromPtr(in.Spec.NetworkOptions.IPv6Only)
+}
+
+// LaunchSubnets returns the resolved subnets that instances can be launched into. Instances that are assigned IPv6
+// prefixes require a subnet with an IPv6 CIDR, and instances that are launched without an IPv4 address require an
+// IPv6-only subnet.
+func (in *EC2NodeClass) LaunchSubnets() []Subnet {
+	if in.IPv6Only() {
+		return lo.Filter(in.Status.Subnets, func(subnet Subnet, _ int) bool {
+			return subnet.IPv6Native
+		})
+	}
+	if in.Spec.NetworkOptions == nil || in.Spec.NetworkOptions.IPv6PrefixCount == nil {
+		return in.Status.Subnets
+	}
+	return lo.Filter(in.Status.Subnets, func(subnet Subnet, _ int) bool {
+		return len(subnet.IPv6CIDRs) > 0
+	})
+}
+
+// KubeletConfiguration returns the kubelet configuration that nodes are launched with, given the kubelet
+// configuration of their NodePool. Fields that are set on the NodePool take precedence over the EC2NodeClass.
+func (in *EC2NodeClass) KubeletConfiguration(kubelet *corev1beta1.KubeletConfiguration) *corev1beta1.KubeletConfiguration {
+	if in.Spec.Kubelet == nil {
+		return kubelet
+	}
+	if kubelet == nil {
+		return in.Spec.Kubelet.DeepCopy()
+	}
+	merged := kubelet.DeepCopy()
+	// mergo only sets fields and map keys that are unset in the destination, so the NodePool's values are preserved
+	lo.Must0(mergo.Merge(merged, in.Spec.Kubelet.DeepCopy()))
+	return merged
+}
+
 // EC2NodeClassList contains a list of EC2NodeClass
 // +kubebuilder:object:root=true
 type EC2NodeClassList struct {
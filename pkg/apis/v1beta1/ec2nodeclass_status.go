@@ -17,6 +17,7 @@ package v1beta1
 import (
 	"github.com/awslabs/operatorpkg/status"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Subnet contains resolved Subnet selector values utilized for node launch
@@ -27,6 +28,12 @@ type Subnet struct {
 	// The associated availability zone
 	// +required
 	Zone string `json:"zone"`
+	// IPv6CIDRs are the IPv6 CIDR blocks that are associated with the subnet
+	// +optional
+	IPv6CIDRs []string `json:"ipv6CIDRs,omitempty"`
+	// IPv6Native is whether the subnet is IPv6-only, without an IPv4 CIDR block
+	// +optional
+	IPv6Native bool `json:"ipv6Native,omitempty"`
 }
 
 // SecurityGroup contains resolved SecurityGroup selector values utilized for node launch
@@ -50,6 +57,138 @@ type AMI struct {
 	// Requirements of the AMI to be utilized on an instance type
 	// +required
 	Requirements []v1.NodeSelectorRequirement `json:"requirements"`
+	// CreationTime is the time at which the AMI was created
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+	// DeprecationTime is the time at which the AMI is deprecated. When unset, the AMI isn't scheduled for deprecation.
+	// +optional
+	DeprecationTime *metav1.Time `json:"deprecationTime,omitempty"`
+	// Deprecated is true when the deprecation time of the AMI has passed. Deprecated AMIs are only used for instance
+	// types that no other resolved AMI is compatible with.
+	// +optional
+	Deprecated bool `json:"deprecated,omitempty"`
+}
+
+const (
+	// AMIRolloutPhaseProgressing means that the AMIs are used for the canary percentage of launches
+	AMIRolloutPhaseProgressing = "Progressing"
+	// AMIRolloutPhasePromoted means that the AMIs have replaced the active AMIs
+	AMIRolloutPhasePromoted = "Promoted"
+	// AMIRolloutPhaseRolledBack means that nodes launched with the AMIs failed, so they're no longer used
+	AMIRolloutPhaseRolledBack = "RolledBack"
+)
+
+// AMIRolloutStatus contains the progress of rolling out newly resolved AMIs
+type AMIRolloutStatus struct {
+	// AMIs are the newly resolved AMIs that are being rolled out
+	// +required
+	AMIs []AMI `json:"amis"`
+	// Phase of the rollout
+	// +kubebuilder:validation:Enum:={Progressing,Promoted,RolledBack}
+	// +required
+	Phase string `json:"phase"`
+	// StartTime is the time at which the rollout started
+	// +required
+	StartTime metav1.Time `json:"startTime"`
+	// ReadyNodes is the number of nodes launched with the AMIs that have become ready
+	// +optional
+	ReadyNodes int32 `json:"readyNodes,omitempty"`
+	// Message describes why the rollout was rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CapacityReservation contains resolved CapacityReservation selector values utilized for node launch
+type CapacityReservation struct {
+	// ID of the capacity reservation
+	// +required
+	ID string `json:"id"`
+	// InstanceType is the instance type the capacity reservation is for
+	// +required
+	InstanceType string `json:"instanceType"`
+	// The availability zone the capacity reservation is in
+	// +required
+	Zone string `json:"zone"`
+	// OwnerID is the id of the AWS account that owns the capacity reservation
+	// +required
+	OwnerID string `json:"ownerID"`
+	// AvailableInstanceCount is the number of instances that can still be launched into the capacity reservation
+	// +optional
+	AvailableInstanceCount int64 `json:"availableInstanceCount,omitempty"`
+	// ReservationType is the type of the capacity reservation. Capacity blocks can only be launched into
+	// between their start and end times.
+	// +kubebuilder:validation:Enum:={default,capacity-block}
+	// +optional
+	ReservationType string `json:"reservationType,omitempty"`
+	// StartTime is the time at which the capacity reservation becomes usable. When unset, the reservation is usable immediately.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time at which the capacity reservation expires. When unset, the reservation doesn't expire.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+// PlacementGroup contains the resolved placement group that nodes are launched into
+type PlacementGroup struct {
+	// ID of the placement group
+	// +required
+	ID string `json:"id"`
+	// Name of the placement group
+	// +required
+	Name string `json:"name"`
+	// Strategy is the placement strategy of the placement group
+	// +kubebuilder:validation:Enum:={cluster,partition,spread}
+	// +required
+	Strategy string `json:"strategy"`
+	// PartitionCount is the number of partitions in a partition placement group
+	// +optional
+	PartitionCount int64 `json:"partitionCount,omitempty"`
+	// Zone is the availability zone that instances in a cluster placement group are launched into. It's
+	// resolved from the instances already running in the placement group.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+}
+
+// Snapshot contains the resolved snapshot that a block device mapping's volume is created from
+type Snapshot struct {
+	// DeviceName is the device name of the block device mapping that selected the snapshot
+	// +required
+	DeviceName string `json:"deviceName"`
+	// ID of the snapshot
+	// +required
+	ID string `json:"id"`
+	// CreationTime is the time at which the snapshot was started
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+}
+
+// ObservedGenerations contains the EC2NodeClass generation that each resolved status field was computed for. A
+// generation of zero means that the field hasn't been resolved since the generation was first recorded.
+type ObservedGenerations struct {
+	// Subnets is the generation that the subnets were resolved for
+	// +optional
+	Subnets int64 `json:"subnets,omitempty"`
+	// PodSubnets is the generation that the pod subnets were resolved for
+	// +optional
+	PodSubnets int64 `json:"podSubnets,omitempty"`
+	// SecurityGroups is the generation that the security groups were resolved for
+	// +optional
+	SecurityGroups int64 `json:"securityGroups,omitempty"`
+	// AMIs is the generation that the AMIs were resolved for
+	// +optional
+	AMIs int64 `json:"amis,omitempty"`
+	// CapacityReservations is the generation that the capacity reservations were resolved for
+	// +optional
+	CapacityReservations int64 `json:"capacityReservations,omitempty"`
+	// PlacementGroup is the generation that the placement group was resolved for
+	// +optional
+	PlacementGroup int64 `json:"placementGroup,omitempty"`
+	// Snapshots is the generation that the snapshots were resolved for
+	// +optional
+	Snapshots int64 `json:"snapshots,omitempty"`
+	// InstanceProfile is the generation that the instance profile was resolved for
+	// +optional
+	InstanceProfile int64 `json:"instanceProfile,omitempty"`
 }
 
 // EC2NodeClassStatus contains the resolved state of the EC2NodeClass
@@ -58,6 +197,9 @@ type EC2NodeClassStatus struct {
 	// cluster under the subnet selectors.
 	// +optional
 	Subnets []Subnet `json:"subnets,omitempty"`
+	// PodSubnets contains the current Subnet values that are selected by the pod subnet selectors
+	// +optional
+	PodSubnets []Subnet `json:"podSubnets,omitempty"`
 	// SecurityGroups contains the current Security Groups values that are available to the
 	// cluster under the SecurityGroups selectors.
 	// +optional
@@ -66,16 +208,89 @@ type EC2NodeClassStatus struct {
 	// cluster under the AMI selectors.
 	// +optional
 	AMIs []AMI `json:"amis,omitempty"`
+	// AMIRollout contains the progress of rolling out newly resolved AMIs when amiRollout is configured. The AMIs in
+	// the amis field remain the active AMIs until the rollout is promoted.
+	// +optional
+	AMIRollout *AMIRolloutStatus `json:"amiRollout,omitempty"`
+	// CapacityReservations contains the current Capacity Reservation values that are available to the
+	// cluster under the CapacityReservation selectors.
+	// +optional
+	CapacityReservations []CapacityReservation `json:"capacityReservations,omitempty"`
+	// PlacementGroup contains the current placement group that is selected by the PlacementGroup selector
+	// +optional
+	PlacementGroup *PlacementGroup `json:"placementGroup,omitempty"`
+	// Snapshots contains the snapshots that are selected by the snapshot selector terms of the block device mappings
+	// +optional
+	Snapshots []Snapshot `json:"snapshots,omitempty"`
 	// InstanceProfile contains the resolved instance profile for the role
 	// +optional
 	InstanceProfile string `json:"instanceProfile,omitempty"`
+	// ObservedGenerations contains the generation of the EC2NodeClass that each of the resolved fields was computed for
+	// +optional
+	ObservedGenerations ObservedGenerations `json:"observedGenerations,omitempty"`
 	// Conditions contains signals for health and readiness
 	// +optional
 	Conditions []status.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ConditionTypeAMIsReady is true when the AMI selector terms resolve at least one AMI
+	ConditionTypeAMIsReady = "AMIsReady"
+	// ConditionTypeSubnetsReady is true when the subnet selector terms resolve at least one subnet
+	ConditionTypeSubnetsReady = "SubnetsReady"
+	// ConditionTypePodSubnetsReady is true when the pod subnet selector terms resolve at least one subnet, or when no
+	// pod subnets are selected
+	ConditionTypePodSubnetsReady = "PodSubnetsReady"
+	// ConditionTypeSecurityGroupsReady is true when the security group selector terms resolve at least one security group
+	ConditionTypeSecurityGroupsReady = "SecurityGroupsReady"
+	// ConditionTypePlacementGroupReady is true when the placement group selector resolves a placement group, or when
+	// no placement group is selected
+	ConditionTypePlacementGroupReady = "PlacementGroupReady"
+	// ConditionTypeInstanceProfileReady is true when the instance profile has been resolved or created for the role
+	ConditionTypeInstanceProfileReady = "InstanceProfileReady"
+	// ConditionTypeSnapshotsReady is true when the snapshot selector terms of every block device mapping resolve a
+	// snapshot, or when no block device mapping selects snapshots
+	ConditionTypeSnapshotsReady = "SnapshotsReady"
+	// ConditionTypeClusterCIDRReady is true when the cluster CIDR has been detected, or when the AMI family doesn't
+	// require it
+	ConditionTypeClusterCIDRReady = "ClusterCIDRReady"
+	// ConditionTypeAMIsDeprecated is true when every AMI resolved by the AMI selector terms is deprecated. It doesn't
+	// affect readiness since deprecated AMIs can still be launched, and it's removed once a current AMI is resolved.
+	ConditionTypeAMIsDeprecated = "AMIsDeprecated"
+)
+
 func (in *EC2NodeClass) StatusConditions() status.ConditionSet {
-	return status.NewReadyConditions().For(in)
+	return status.NewReadyConditions(
+		ConditionTypeAMIsReady,
+		ConditionTypeSubnetsReady,
+		ConditionTypePodSubnetsReady,
+		ConditionTypeSecurityGroupsReady,
+		ConditionTypePlacementGroupReady,
+		ConditionTypeInstanceProfileReady,
+		ConditionTypeSnapshotsReady,
+		ConditionTypeClusterCIDRReady,
+	).For(in)
+}
+
+// StatusObserved returns whether each of the resolved status fields was computed for the current generation of the
+// EC2NodeClass. Fields without a recorded generation were resolved before generations were tracked and aren't
+// considered stale.
+func (in *EC2NodeClass) StatusObserved() bool {
+	for _, generation := range []int64{
+		in.Status.ObservedGenerations.Subnets,
+		in.Status.ObservedGenerations.PodSubnets,
+		in.Status.ObservedGenerations.SecurityGroups,
+		in.Status.ObservedGenerations.AMIs,
+		in.Status.ObservedGenerations.CapacityReservations,
+		in.Status.ObservedGenerations.PlacementGroup,
+		in.Status.ObservedGenerations.Snapshots,
+		in.Status.ObservedGenerations.InstanceProfile,
+	} {
+		if generation != 0 && generation != in.Generation {
+			return false
+		}
+	}
+	return true
 }
 
 func (in *EC2NodeClass) GetConditions() []status.Condition {
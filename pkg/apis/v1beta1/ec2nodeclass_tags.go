@@ -0,0 +1,144 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/samber/lo"
+)
+
+// TagTemplateData is the data that templated tag values are rendered with. Values that aren't known yet, such as the
+// zone of an instance that hasn't been launched, are left empty.
+// +kubebuilder:object:generate=false
+type TagTemplateData struct {
+	NodePool     string
+	Zone         string
+	CapacityType string
+	InstanceType string
+	Labels       map[string]string
+}
+
+// tagTemplateFields are the fields of TagTemplateData that tag values can reference, other than Labels
+var tagTemplateFields = []string{"NodePool", "Zone", "CapacityType", "InstanceType"}
+
+// IsTagTemplate returns whether the tag value is a template that needs to be rendered
+func IsTagTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// ParseTagTemplate parses a templated tag value. Templates may only substitute the fields of TagTemplateData, with
+// labels referenced as {{ .Labels.team }} or {{ index .Labels "example.com/team" }}. Pipelines, control structures
+// and all other functions are rejected.
+func ParseTagTemplate(value string) (*template.Template, error) {
+	t, err := template.New("tag").Option("missingkey=zero").Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if t.Tree == nil {
+		return t, nil
+	}
+	for _, node := range t.Tree.Root.Nodes {
+		if err := validateTagTemplateNode(node); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func validateTagTemplateNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.TextNode:
+		return nil
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) == 0 && len(n.Pipe.Cmds) == 1 && tagTemplateReference(n.Pipe.Cmds[0]) != "" {
+			return nil
+		}
+		return fmt.Errorf("%s must reference one of %s, or a label", n, strings.Join(lo.Map(tagTemplateFields, func(f string, _ int) string { return "." + f }), ", "))
+	default:
+		return fmt.Errorf("%s isn't supported in tag templates", node)
+	}
+}
+
+// tagTemplateReference returns the field of TagTemplateData that the command references, or an empty string if the
+// command isn't a supported reference
+func tagTemplateReference(cmd *parse.CommandNode) string {
+	switch len(cmd.Args) {
+	case 1:
+		field, ok := cmd.Args[0].(*parse.FieldNode)
+		if !ok {
+			return ""
+		}
+		if len(field.Ident) == 1 && lo.Contains(tagTemplateFields, field.Ident[0]) {
+			return field.Ident[0]
+		}
+		if len(field.Ident) == 2 && field.Ident[0] == "Labels" {
+			return "Labels"
+		}
+	case 3:
+		fn, ok := cmd.Args[0].(*parse.IdentifierNode)
+		if !ok || fn.Ident != "index" {
+			return ""
+		}
+		field, ok := cmd.Args[1].(*parse.FieldNode)
+		if !ok || len(field.Ident) != 1 || field.Ident[0] != "Labels" {
+			return ""
+		}
+		if _, ok := cmd.Args[2].(*parse.StringNode); ok {
+			return "Labels"
+		}
+	}
+	return ""
+}
+
+// ResolveTags renders the templated values of the tags with the data. Tags that reference a value that isn't known
+// yet are omitted so that they can be applied once it is, and values that fail to parse are returned as they are.
+func ResolveTags(tags map[string]string, data TagTemplateData) map[string]string {
+	known := map[string]bool{
+		"NodePool":     data.NodePool != "",
+		"Zone":         data.Zone != "",
+		"CapacityType": data.CapacityType != "",
+		"InstanceType": data.InstanceType != "",
+		"Labels":       true,
+	}
+	resolved := map[string]string{}
+	for k, v := range tags {
+		if !IsTagTemplate(v) {
+			resolved[k] = v
+			continue
+		}
+		t, err := ParseTagTemplate(v)
+		if err != nil {
+			resolved[k] = v
+			continue
+		}
+		if lo.SomeBy(t.Tree.Root.Nodes, func(node parse.Node) bool {
+			action, ok := node.(*parse.ActionNode)
+			return ok && !known[tagTemplateReference(action.Pipe.Cmds[0])]
+		}) {
+			continue
+		}
+		var value strings.Builder
+		if err := t.Execute(&value, data); err != nil {
+			resolved[k] = v
+			continue
+		}
+		resolved[k] = value.String()
+	}
+	return resolved
+}
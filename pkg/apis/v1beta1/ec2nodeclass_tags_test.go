@@ -0,0 +1,69 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1_test
+
+import (
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResolveTags", func() {
+	var data v1beta1.TagTemplateData
+	BeforeEach(func() {
+		data = v1beta1.TagTemplateData{
+			NodePool:     "default",
+			Zone:         "us-west-2a",
+			CapacityType: "spot",
+			InstanceType: "m5.large",
+			Labels:       map[string]string{"team": "a", "example.com/cost-center": "1234"},
+		}
+	})
+	It("should return tags that aren't templated as they are", func() {
+		Expect(v1beta1.ResolveTags(map[string]string{"team": "a"}, data)).To(Equal(map[string]string{"team": "a"}))
+	})
+	It("should render templated tags", func() {
+		Expect(v1beta1.ResolveTags(map[string]string{
+			"nodepool":    "{{ .NodePool }}",
+			"placement":   "{{ .Zone }}/{{ .CapacityType }}/{{ .InstanceType }}",
+			"team":        "team-{{ .Labels.team }}",
+			"cost-center": `{{ index .Labels "example.com/cost-center" }}`,
+		}, data)).To(Equal(map[string]string{
+			"nodepool":    "default",
+			"placement":   "us-west-2a/spot/m5.large",
+			"team":        "team-a",
+			"cost-center": "1234",
+		}))
+	})
+	It("should render missing labels as empty values", func() {
+		Expect(v1beta1.ResolveTags(map[string]string{
+			"owner":  "{{ .Labels.owner }}",
+			"domain": `{{ index .Labels "example.com/domain" }}`,
+		}, data)).To(Equal(map[string]string{"owner": "", "domain": ""}))
+	})
+	It("should omit tags that reference values that aren't known yet", func() {
+		data.Zone = ""
+		data.InstanceType = ""
+		Expect(v1beta1.ResolveTags(map[string]string{
+			"nodepool":  "{{ .NodePool }}",
+			"zone":      "{{ .Zone }}",
+			"placement": "{{ .CapacityType }}/{{ .InstanceType }}",
+		}, data)).To(Equal(map[string]string{"nodepool": "default"}))
+	})
+	It("should return values that fail to parse as they are", func() {
+		Expect(v1beta1.ResolveTags(map[string]string{"test": "{{ .Zone"}, data)).To(Equal(map[string]string{"test": "{{ .Zone"}))
+	})
+})
@@ -17,29 +17,47 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/apis"
 )
 
 const (
-	subnetSelectorTermsPath        = "subnetSelectorTerms"
-	securityGroupSelectorTermsPath = "securityGroupSelectorTerms"
-	amiSelectorTermsPath           = "amiSelectorTerms"
-	amiFamilyPath                  = "amiFamily"
-	tagsPath                       = "tags"
-	metadataOptionsPath            = "metadataOptions"
-	blockDeviceMappingsPath        = "blockDeviceMappings"
-	rolePath                       = "role"
-	instanceProfilePath            = "instanceProfile"
+	subnetSelectorTermsPath              = "subnetSelectorTerms"
+	podSubnetSelectorTermsPath           = "podSubnetSelectorTerms"
+	securityGroupSelectorTermsPath       = "securityGroupSelectorTerms"
+	capacityReservationSelectorTermsPath = "capacityReservationSelectorTerms"
+	placementGroupSelectorPath           = "placementGroupSelector"
+	allocationStrategyPath               = "allocationStrategy"
+	tenancyPath                          = "tenancy"
+	cpuOptionsPath                       = "cpuOptions"
+	creditSpecificationPath              = "creditSpecification"
+	networkOptionsPath                   = "networkOptions"
+	amiSelectorTermsPath                 = "amiSelectorTerms"
+	amiRolloutPath                       = "amiRollout"
+	amiFamilyPath                        = "amiFamily"
+	tagsPath                             = "tags"
+	metadataOptionsPath                  = "metadataOptions"
+	blockDeviceMappingsPath              = "blockDeviceMappings"
+	rolePath                             = "role"
+	instanceProfilePath                  = "instanceProfile"
+	instanceStoreMountPathPath           = "instanceStoreMountPath"
 )
 
 var (
 	minVolumeSize = *resource.NewScaledQuantity(1, resource.Giga)
 	maxVolumeSize = *resource.NewScaledQuantity(64, resource.Tera)
+	// aliasFamilies are the AMI families, lowercased, whose default AMIs can be selected with an alias
+	aliasFamilies = lo.Map([]string{AMIFamilyAL2, AMIFamilyAL2023, AMIFamilyBottlerocket, AMIFamilyWindows2019, AMIFamilyWindows2022}, func(family string, _ int) string {
+		return strings.ToLower(family)
+	})
+	// instanceStoreMountPathRegex matches the characters that can be safely rendered into bootstrap scripts
+	instanceStoreMountPathRegex = regexp.MustCompile(`^/[a-zA-Z0-9/._-]*$`)
 )
 
 func (in *EC2NodeClass) SupportedVerbs() []admissionregistrationv1.OperationType {
@@ -62,7 +80,7 @@ func (in *EC2NodeClass) Validate(ctx context.Context) (errs *apis.FieldError) {
 
 func (in *EC2NodeClass) validateImmutableFields(original *EC2NodeClass) (errs *apis.FieldError) {
 	return errs.Also(
-		in.Spec.validateRoleImmutability(&original.Spec).ViaField("spec"),
+		in.Spec.validateInstanceProfileSourceImmutability(&original.Spec).ViaField("spec"),
 	)
 }
 
@@ -75,11 +93,21 @@ func (in *EC2NodeClassSpec) validate(_ context.Context) (errs *apis.FieldError)
 	}
 	return errs.Also(
 		in.validateSubnetSelectorTerms().ViaField(subnetSelectorTermsPath),
+		in.validatePodSubnetSelectorTerms().ViaField(podSubnetSelectorTermsPath),
 		in.validateSecurityGroupSelectorTerms().ViaField(securityGroupSelectorTermsPath),
+		in.validateCapacityReservationSelectorTerms().ViaField(capacityReservationSelectorTermsPath),
+		in.validatePlacementGroupSelector().ViaField(placementGroupSelectorPath),
+		in.validateAllocationStrategy().ViaField(allocationStrategyPath),
+		in.validateTenancy().ViaField(tenancyPath),
+		in.validateCPUOptions().ViaField(cpuOptionsPath),
+		in.validateCreditSpecification().ViaField(creditSpecificationPath),
+		in.validateNetworkOptions().ViaField(networkOptionsPath),
 		in.validateAMISelectorTerms().ViaField(amiSelectorTermsPath),
+		in.validateAMIRollout().ViaField(amiRolloutPath),
 		in.validateMetadataOptions().ViaField(metadataOptionsPath),
 		in.validateAMIFamily().ViaField(amiFamilyPath),
 		in.validateBlockDeviceMappings().ViaField(blockDeviceMappingsPath),
+		in.validateInstanceStorePolicy(),
 		in.validateTags().ViaField(tagsPath),
 	)
 }
@@ -94,6 +122,13 @@ func (in *EC2NodeClassSpec) validateSubnetSelectorTerms() (errs *apis.FieldError
 	return errs
 }
 
+func (in *EC2NodeClassSpec) validatePodSubnetSelectorTerms() (errs *apis.FieldError) {
+	for i, term := range in.PodSubnetSelectorTerms {
+		errs = errs.Also(term.validate()).ViaIndex(i)
+	}
+	return errs
+}
+
 func (in *SubnetSelectorTerm) validate() (errs *apis.FieldError) {
 	errs = errs.Also(validateTags(in.Tags).ViaField("tags"))
 	if len(in.Tags) == 0 && in.ID == "" {
@@ -127,9 +162,138 @@ func (in *SecurityGroupSelectorTerm) validate() (errs *apis.FieldError) {
 	return errs
 }
 
+func (in *EC2NodeClassSpec) validateCapacityReservationSelectorTerms() (errs *apis.FieldError) {
+	for i, term := range in.CapacityReservationSelectorTerms {
+		errs = errs.Also(term.validate()).ViaIndex(i)
+	}
+	return errs
+}
+
+func (in *CapacityReservationSelectorTerm) validate() (errs *apis.FieldError) {
+	errs = errs.Also(validateTags(in.Tags).ViaField("tags"))
+	if len(in.Tags) == 0 && in.ID == "" {
+		errs = errs.Also(apis.ErrGeneric("expected at least one, got none", "tags", "id"))
+	} else if in.ID != "" && (len(in.Tags) > 0 || in.OwnerID != "") {
+		errs = errs.Also(apis.ErrGeneric(`"id" is mutually exclusive, cannot be set with a combination of other fields in`))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validatePlacementGroupSelector() (errs *apis.FieldError) {
+	if in.PlacementGroupSelector == nil {
+		return nil
+	}
+	return in.PlacementGroupSelector.validate()
+}
+
+func (in *PlacementGroupSelectorTerm) validate() (errs *apis.FieldError) {
+	errs = errs.Also(validateTags(in.Tags).ViaField("tags"))
+	if lo.Count([]bool{len(in.Tags) > 0, in.ID != "", in.Name != ""}, true) != 1 {
+		errs = errs.Also(apis.ErrGeneric("expected exactly one", "tags", "id", "name"))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateAllocationStrategy() (errs *apis.FieldError) {
+	if in.AllocationStrategy == nil {
+		return nil
+	}
+	if in.AllocationStrategy.Spot != nil {
+		errs = errs.Also(in.validateStringEnum(*in.AllocationStrategy.Spot, "spot", ec2.SpotAllocationStrategy_Values()))
+	}
+	if in.AllocationStrategy.OnDemand != nil {
+		errs = errs.Also(in.validateStringEnum(*in.AllocationStrategy.OnDemand, "onDemand", ec2.FleetOnDemandAllocationStrategy_Values()))
+	}
+	if len(in.AllocationStrategy.InstanceTypePriorities) > 0 &&
+		lo.FromPtr(in.AllocationStrategy.Spot) != ec2.SpotAllocationStrategyCapacityOptimizedPrioritized &&
+		lo.FromPtr(in.AllocationStrategy.OnDemand) != ec2.FleetOnDemandAllocationStrategyPrioritized {
+		errs = errs.Also(apis.ErrGeneric("requires the capacity-optimized-prioritized spot or prioritized on-demand allocation strategy", "instanceTypePriorities"))
+	}
+	if in.AllocationStrategy.InstanceTypeSelection != nil {
+		errs = errs.Also(in.validateStringEnum(*in.AllocationStrategy.InstanceTypeSelection, "instanceTypeSelection", []string{InstanceTypeSelectionExplicit, InstanceTypeSelectionAttributeBased}))
+		if *in.AllocationStrategy.InstanceTypeSelection == InstanceTypeSelectionAttributeBased && len(in.AllocationStrategy.InstanceTypePriorities) > 0 {
+			errs = errs.Also(apis.ErrGeneric("can't be used with attribute-based instance type selection", "instanceTypePriorities"))
+		}
+	}
+	for instanceType, priority := range in.AllocationStrategy.InstanceTypePriorities {
+		if priority < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(priority, instanceType).ViaField("instanceTypePriorities"))
+		}
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateTenancy() (errs *apis.FieldError) {
+	if in.Tenancy == nil {
+		return nil
+	}
+	errs = errs.Also(in.validateStringEnum(in.Tenancy.Type, "type", ec2.Tenancy_Values()))
+	if in.Tenancy.HostAffinity != nil {
+		errs = errs.Also(in.validateStringEnum(*in.Tenancy.HostAffinity, "hostAffinity", ec2.Affinity_Values()))
+	}
+	if in.Tenancy.Type != TenancyHost && (in.Tenancy.HostResourceGroupARN != nil || in.Tenancy.HostAffinity != nil) {
+		errs = errs.Also(apis.ErrGeneric("can only be set with host tenancy", "hostResourceGroupArn", "hostAffinity"))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateCPUOptions() (errs *apis.FieldError) {
+	if in.CPUOptions == nil {
+		return nil
+	}
+	if in.CPUOptions.CoreCount != nil && *in.CPUOptions.CoreCount < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*in.CPUOptions.CoreCount, "coreCount"))
+	}
+	if in.CPUOptions.ThreadsPerCore != nil && (*in.CPUOptions.ThreadsPerCore < 1 || *in.CPUOptions.ThreadsPerCore > 2) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*in.CPUOptions.ThreadsPerCore, 1, 2, "threadsPerCore"))
+	}
+	if in.CPUOptions.AMDSEVSNP != nil {
+		errs = errs.Also(in.validateStringEnum(*in.CPUOptions.AMDSEVSNP, "amdSevSnp", ec2.AmdSevSnpSpecification_Values()))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateCreditSpecification() (errs *apis.FieldError) {
+	if in.CreditSpecification == nil {
+		return nil
+	}
+	errs = errs.Also(in.validateStringEnum(in.CreditSpecification.CPUCredits, "cpuCredits", []string{CPUCreditsStandard, CPUCreditsUnlimited}))
+	if utilization := in.CreditSpecification.EstimatedSurplusUtilization; utilization != nil {
+		if *utilization < 0 || *utilization > 100 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*utilization, 0, 100, "estimatedSurplusUtilization"))
+		}
+		if in.CreditSpecification.CPUCredits != CPUCreditsUnlimited {
+			errs = errs.Also(apis.ErrGeneric("can only be set with unlimited cpuCredits", "estimatedSurplusUtilization"))
+		}
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateAMIRollout() (errs *apis.FieldError) {
+	if in.AMIRollout == nil {
+		return nil
+	}
+	if in.AMIRollout.CanaryPercentage < 1 || in.AMIRollout.CanaryPercentage > 100 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(in.AMIRollout.CanaryPercentage, 1, 100, "canaryPercentage"))
+	}
+	if in.AMIRollout.PromotionThreshold < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(in.AMIRollout.PromotionThreshold, "promotionThreshold"))
+	}
+	return errs
+}
+
 func (in *EC2NodeClassSpec) validateAMISelectorTerms() (errs *apis.FieldError) {
 	for _, term := range in.AMISelectorTerms {
 		errs = errs.Also(term.validate())
+		if term.Alias == "" {
+			continue
+		}
+		if len(in.AMISelectorTerms) != 1 {
+			errs = errs.Also(apis.ErrGeneric(`"alias" is mutually exclusive, cannot be set with a combination of other amiSelectorTerms`))
+		}
+		if family, _, _ := strings.Cut(term.Alias, "@"); in.AMIFamily != nil && family != strings.ToLower(*in.AMIFamily) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s, family must match amiFamily %q", term.Alias, *in.AMIFamily), "alias"))
+		}
 	}
 	return errs
 }
@@ -137,14 +301,35 @@ func (in *EC2NodeClassSpec) validateAMISelectorTerms() (errs *apis.FieldError) {
 //nolint:gocyclo
 func (in *AMISelectorTerm) validate() (errs *apis.FieldError) {
 	errs = errs.Also(validateTags(in.Tags).ViaField("tags"))
-	if len(in.Tags) == 0 && in.ID == "" && in.Name == "" {
-		errs = errs.Also(apis.ErrGeneric("expect at least one, got none", "tags", "id", "name"))
+	if len(in.Tags) == 0 && in.ID == "" && in.Name == "" && in.Alias == "" && in.SSMParameter == "" {
+		errs = errs.Also(apis.ErrGeneric("expect at least one, got none", "tags", "id", "name", "alias", "ssmParameter"))
 	} else if in.ID != "" && (len(in.Tags) > 0 || in.Name != "" || in.Owner != "") {
 		errs = errs.Also(apis.ErrGeneric(`"id" is mutually exclusive, cannot be set with a combination of other fields in`))
+	} else if in.Alias != "" && (in.ID != "" || len(in.Tags) > 0 || in.Name != "" || in.Owner != "" || in.SSMParameter != "") {
+		errs = errs.Also(apis.ErrGeneric(`"alias" is mutually exclusive, cannot be set with a combination of other fields in`))
+	} else if in.SSMParameter != "" && (in.ID != "" || len(in.Tags) > 0 || in.Name != "" || in.Owner != "") {
+		errs = errs.Also(apis.ErrGeneric(`"ssmParameter" is mutually exclusive, cannot be set with a combination of other fields in`))
+	}
+	if in.Alias != "" {
+		errs = errs.Also(in.validateAlias().ViaField("alias"))
 	}
 	return errs
 }
 
+func (in *AMISelectorTerm) validateAlias() *apis.FieldError {
+	family, version, ok := strings.Cut(in.Alias, "@")
+	if !ok || family == "" || version == "" {
+		return apis.ErrInvalidValue(fmt.Sprintf("%s, must match the format 'family@version'", in.Alias), "")
+	}
+	if !lo.Contains(aliasFamilies, family) {
+		return apis.ErrInvalidValue(fmt.Sprintf("%s, family must be one of %v", in.Alias, aliasFamilies), "")
+	}
+	if (family == strings.ToLower(AMIFamilyWindows2019) || family == strings.ToLower(AMIFamilyWindows2022)) && version != AliasVersionLatest {
+		return apis.ErrInvalidValue(fmt.Sprintf("%s, windows families may only specify version %q", in.Alias, AliasVersionLatest), "")
+	}
+	return nil
+}
+
 func validateTags(m map[string]string) (errs *apis.FieldError) {
 	for k, v := range m {
 		if k == "" {
@@ -244,6 +429,7 @@ func (in *EC2NodeClassSpec) validateEBS(blockDeviceMapping *BlockDeviceMapping)
 	for _, err := range []*apis.FieldError{
 		in.validateVolumeType(blockDeviceMapping),
 		in.validateVolumeSize(blockDeviceMapping),
+		in.validateSnapshotSelectorTerms(blockDeviceMapping),
 	} {
 		if err != nil {
 			errs = errs.Also(err.ViaField("ebs"))
@@ -260,8 +446,14 @@ func (in *EC2NodeClassSpec) validateVolumeType(blockDeviceMapping *BlockDeviceMa
 }
 
 func (in *EC2NodeClassSpec) validateVolumeSize(blockDeviceMapping *BlockDeviceMapping) *apis.FieldError {
-	// If an EBS mapping is present, one of volumeSize or snapshotID must be present
-	if blockDeviceMapping.EBS.SnapshotID != nil && blockDeviceMapping.EBS.VolumeSize == nil {
+	if blockDeviceMapping.EBS.VolumeSizePolicy != nil {
+		if blockDeviceMapping.EBS.VolumeSize != nil {
+			return apis.ErrMultipleOneOf("volumeSize", "volumeSizePolicy")
+		}
+		return in.validateVolumeSizePolicy(blockDeviceMapping.EBS.VolumeSizePolicy).ViaField("volumeSizePolicy")
+	}
+	// If an EBS mapping is present, one of volumeSize, snapshotID or snapshotSelectorTerms must be present
+	if (blockDeviceMapping.EBS.SnapshotID != nil || len(blockDeviceMapping.EBS.SnapshotSelectorTerms) > 0) && blockDeviceMapping.EBS.VolumeSize == nil {
 		return nil
 	} else if blockDeviceMapping.EBS.VolumeSize == nil {
 		return apis.ErrMissingField("volumeSize")
@@ -271,6 +463,79 @@ func (in *EC2NodeClassSpec) validateVolumeSize(blockDeviceMapping *BlockDeviceMa
 	return nil
 }
 
+func (in *EC2NodeClassSpec) validateVolumeSizePolicy(policy *VolumeSizePolicy) (errs *apis.FieldError) {
+	if policy.Base == nil {
+		return apis.ErrMissingField("base")
+	}
+	for _, bound := range []lo.Tuple2[string, *resource.Quantity]{{A: "base", B: policy.Base}, {A: "min", B: policy.Min}, {A: "max", B: policy.Max}} {
+		if bound.B != nil && (bound.B.Cmp(minVolumeSize) == -1 || bound.B.Cmp(maxVolumeSize) == 1) {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(bound.B.String(), minVolumeSize.String(), maxVolumeSize.String(), bound.A))
+		}
+	}
+	if policy.Min != nil && policy.Max != nil && policy.Min.Cmp(*policy.Max) == 1 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s, must not be greater than max", policy.Min), "min"))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateSnapshotSelectorTerms(blockDeviceMapping *BlockDeviceMapping) (errs *apis.FieldError) {
+	if len(blockDeviceMapping.EBS.SnapshotSelectorTerms) == 0 {
+		return nil
+	}
+	if blockDeviceMapping.EBS.SnapshotID != nil {
+		return apis.ErrMultipleOneOf("snapshotID", "snapshotSelectorTerms")
+	}
+	for i, term := range blockDeviceMapping.EBS.SnapshotSelectorTerms {
+		errs = errs.Also(term.validate().ViaFieldIndex("snapshotSelectorTerms", i))
+	}
+	return errs
+}
+
+func (in *SnapshotSelectorTerm) validate() (errs *apis.FieldError) {
+	errs = errs.Also(validateTags(in.Tags).ViaField("tags"))
+	if len(in.Tags) == 0 && in.Name == "" {
+		errs = errs.Also(apis.ErrGeneric("expected at least one, got none", "tags", "name"))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateNetworkOptions() (errs *apis.FieldError) {
+	if in.NetworkOptions == nil {
+		return nil
+	}
+	if count := in.NetworkOptions.IPv6PrefixCount; count != nil && *count < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%d, must be at least 1", *count), "ipv6PrefixCount"))
+	}
+	if !lo.FromPtr(in.NetworkOptions.IPv6Only) {
+		return errs
+	}
+	if lo.FromPtr(in.AssociatePublicIPAddress) {
+		errs = errs.Also(apis.ErrGeneric("can't be set with associatePublicIPAddress", "ipv6Only"))
+	}
+	if in.MetadataOptions != nil && in.MetadataOptions.HTTPProtocolIPv6 != nil && *in.MetadataOptions.HTTPProtocolIPv6 != "enabled" {
+		errs = errs.Also(apis.ErrGeneric("requires httpProtocolIPv6 to be enabled in metadataOptions", "ipv6Only"))
+	}
+	if lo.Contains([]string{AMIFamilyWindows2019, AMIFamilyWindows2022}, lo.FromPtr(in.AMIFamily)) {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("isn't supported with the %s amiFamily", lo.FromPtr(in.AMIFamily)), "ipv6Only"))
+	}
+	return errs
+}
+
+func (in *EC2NodeClassSpec) validateInstanceStorePolicy() (errs *apis.FieldError) {
+	if in.InstanceStoreMountPath != nil {
+		if lo.FromPtr(in.InstanceStorePolicy) != InstanceStorePolicyMount {
+			errs = errs.Also(apis.ErrGeneric("can only be set with the Mount instanceStorePolicy", instanceStoreMountPathPath))
+		}
+		if !instanceStoreMountPathRegex.MatchString(*in.InstanceStoreMountPath) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s, must be an absolute path containing only alphanumeric characters, '/', '.', '_' and '-'", *in.InstanceStoreMountPath), instanceStoreMountPathPath))
+		}
+		if lo.FromPtr(in.AMIFamily) == AMIFamilyAL2023 {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("isn't supported with the %s amiFamily", AMIFamilyAL2023), instanceStoreMountPathPath))
+		}
+	}
+	return errs
+}
+
 func (in *EC2NodeClassSpec) validateAMIFamily() (errs *apis.FieldError) {
 	if in.AMIFamily == nil {
 		return nil
@@ -292,15 +557,22 @@ func (in *EC2NodeClassSpec) validateTags() (errs *apis.FieldError) {
 				errs = errs.Also(apis.ErrInvalidKeyName(k, "tags", fmt.Sprintf("tag contains in restricted tag matching %q", pattern.String())))
 			}
 		}
+		if IsTagTemplate(v) {
+			if _, err := ParseTagTemplate(v); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s, %s", v, err), fmt.Sprintf("tags[%s]", k)))
+			}
+		}
 	}
 	return errs
 }
 
-func (in *EC2NodeClassSpec) validateRoleImmutability(originalSpec *EC2NodeClassSpec) *apis.FieldError {
-	if in.Role != originalSpec.Role {
+// validateInstanceProfileSourceImmutability ensures that the node class doesn't switch between a role, for which Karpenter
+// manages the instance profile, and an instance profile that is managed by the user
+func (in *EC2NodeClassSpec) validateInstanceProfileSourceImmutability(originalSpec *EC2NodeClassSpec) *apis.FieldError {
+	if (in.Role != "") != (originalSpec.Role != "") {
 		return &apis.FieldError{
-			Message: "Immutable field changed",
-			Paths:   []string{"role"},
+			Message: "Switching between role and instanceProfile is not supported",
+			Paths:   []string{rolePath, instanceProfilePath},
 		}
 	}
 	return nil
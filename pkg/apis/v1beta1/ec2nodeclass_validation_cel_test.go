@@ -191,6 +191,47 @@ var _ = Describe("CEL/Validation", func() {
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
 	})
+	Context("PodSubnetSelectorTerms", func() {
+		It("should succeed when pod subnet selector terms aren't set", func() {
+			nc.Spec.PodSubnetSelectorTerms = nil
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a valid pod subnet selector on tags", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a valid pod subnet selector on id", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{
+					ID: "subnet-12345749",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when a pod subnet selector term has no values", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when specifying id with tags", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{
+					ID: "subnet-12345749",
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
 	Context("SecurityGroupSelectorTerms", func() {
 		It("should succeed with a valid security group selector on tags", func() {
 			nc.Spec.SecurityGroupSelectorTerms = []v1beta1.SecurityGroupSelectorTerm{
@@ -317,6 +358,422 @@ var _ = Describe("CEL/Validation", func() {
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
 	})
+	Context("CapacityReservationSelectorTerms", func() {
+		It("should succeed with a valid capacity reservation selector on tags", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a valid capacity reservation selector on tags and owner id", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+					OwnerID: "012345678901",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a valid capacity reservation selector on id", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					ID: "cr-12345749",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed when capacity reservation selector terms is set to nil", func() {
+			nc.Spec.CapacityReservationSelectorTerms = nil
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when a capacity reservation selector term has no values", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when a capacity reservation selector term has a tag map key that is empty", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					Tags: map[string]string{
+						"": "testvalue",
+					},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when a capacity reservation selector term has an invalid id", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					ID: "subnet-12345749",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when a capacity reservation selector term has an invalid owner id", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+					OwnerID: "01234",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when specifying id with tags", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					ID: "cr-12345749",
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when specifying id with owner id", func() {
+			nc.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+				{
+					ID:      "cr-12345749",
+					OwnerID: "012345678901",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("AllocationStrategy", func() {
+		It("should succeed with valid spot and on-demand allocation strategies", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				Spot:     aws.String("capacity-optimized"),
+				OnDemand: aws.String("lowest-price"),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with an invalid spot allocation strategy", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{Spot: aws.String("cheapest")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an invalid on-demand allocation strategy", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{OnDemand: aws.String("capacity-optimized")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed with instance type priorities and a prioritized spot allocation strategy", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				Spot:                   aws.String("capacity-optimized-prioritized"),
+				InstanceTypePriorities: map[string]int32{"m5.large": 0, "m6g.large": 1},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with instance type priorities and a prioritized on-demand allocation strategy", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				OnDemand:               aws.String("prioritized"),
+				InstanceTypePriorities: map[string]int32{"m5.large": 0},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with instance type priorities without a prioritized allocation strategy", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				Spot:                   aws.String("price-capacity-optimized"),
+				InstanceTypePriorities: map[string]int32{"m5.large": 0},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a negative instance type priority", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				OnDemand:               aws.String("prioritized"),
+				InstanceTypePriorities: map[string]int32{"m5.large": -1},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed with attribute-based instance type selection", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				InstanceTypeSelection: aws.String("attribute-based"),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with an invalid instance type selection", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				InstanceTypeSelection: aws.String("invalid"),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with instance type priorities and attribute-based instance type selection", func() {
+			nc.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				OnDemand:               aws.String("prioritized"),
+				InstanceTypePriorities: map[string]int32{"m5.large": 0},
+				InstanceTypeSelection:  aws.String("attribute-based"),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("CPUOptions", func() {
+		It("should succeed with valid cpu options", func() {
+			nc.Spec.CPUOptions = &v1beta1.CPUOptions{
+				CoreCount:      aws.Int64(4),
+				ThreadsPerCore: aws.Int64(1),
+				AMDSEVSNP:      aws.String("enabled"),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with a core count of zero", func() {
+			nc.Spec.CPUOptions = &v1beta1.CPUOptions{CoreCount: aws.Int64(0)}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with more than two threads per core", func() {
+			nc.Spec.CPUOptions = &v1beta1.CPUOptions{ThreadsPerCore: aws.Int64(3)}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an invalid amd sev-snp value", func() {
+			nc.Spec.CPUOptions = &v1beta1.CPUOptions{AMDSEVSNP: aws.String("on")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("Kubelet", func() {
+		It("should succeed with a valid kubelet configuration", func() {
+			nc.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+				MaxPods:        lo.ToPtr[int32](110),
+				SystemReserved: map[string]string{"cpu": "500m"},
+				EvictionHard:   map[string]string{"memory.available": "5%"},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with an invalid system reserved key", func() {
+			nc.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+				SystemReserved: map[string]string{"invalid": "500m"},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when imageGCHighThresholdPercent is less than imageGCLowThresholdPercent", func() {
+			nc.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+				ImageGCHighThresholdPercent: lo.ToPtr[int32](50),
+				ImageGCLowThresholdPercent:  lo.ToPtr[int32](60),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when evictionSoft doesn't have a matching evictionSoftGracePeriod", func() {
+			nc.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+				EvictionSoft: map[string]string{"memory.available": "5%"},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("CreditSpecification", func() {
+		It("should succeed with unlimited cpu credits and an estimated surplus utilization", func() {
+			nc.Spec.CreditSpecification = &v1beta1.CreditSpecification{
+				CPUCredits:                  "unlimited",
+				EstimatedSurplusUtilization: lo.ToPtr(int32(20)),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with standard cpu credits", func() {
+			nc.Spec.CreditSpecification = &v1beta1.CreditSpecification{CPUCredits: "standard"}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with invalid cpu credits", func() {
+			nc.Spec.CreditSpecification = &v1beta1.CreditSpecification{CPUCredits: "burst"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an estimated surplus utilization above 100", func() {
+			nc.Spec.CreditSpecification = &v1beta1.CreditSpecification{
+				CPUCredits:                  "unlimited",
+				EstimatedSurplusUtilization: lo.ToPtr(int32(101)),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an estimated surplus utilization and standard cpu credits", func() {
+			nc.Spec.CreditSpecification = &v1beta1.CreditSpecification{
+				CPUCredits:                  "standard",
+				EstimatedSurplusUtilization: lo.ToPtr(int32(20)),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("NetworkOptions", func() {
+		It("should succeed with an ipv6 prefix count", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6PrefixCount: lo.ToPtr(int64(1))}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with an ipv6 prefix count of zero", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6PrefixCount: lo.ToPtr(int64(0))}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed with ipv6Only and default metadata options", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+			Expect(nc.Spec.MetadataOptions).ToNot(BeNil())
+			Expect(nc.Spec.MetadataOptions.HTTPProtocolIPv6).To(BeNil())
+		})
+		It("should succeed with ipv6Only and httpProtocolIPv6 enabled", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.MetadataOptions = &v1beta1.MetadataOptions{HTTPProtocolIPv6: lo.ToPtr("enabled")}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with ipv6Only and httpProtocolIPv6 disabled", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.MetadataOptions = &v1beta1.MetadataOptions{HTTPProtocolIPv6: lo.ToPtr("disabled")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with ipv6Only and associatePublicIPAddress", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.MetadataOptions = &v1beta1.MetadataOptions{HTTPProtocolIPv6: lo.ToPtr("enabled")}
+			nc.Spec.AssociatePublicIPAddress = lo.ToPtr(true)
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with ipv6Only and a Windows amiFamily", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.MetadataOptions = &v1beta1.MetadataOptions{HTTPProtocolIPv6: lo.ToPtr("enabled")}
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyWindows2022
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "windows2022@latest"}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("AMIRollout", func() {
+		It("should succeed with a valid ami rollout", func() {
+			nc.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 10, PromotionThreshold: 3}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a canary percentage of 100", func() {
+			nc.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 100, PromotionThreshold: 1}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with a canary percentage of zero", func() {
+			nc.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 0, PromotionThreshold: 1}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a canary percentage above 100", func() {
+			nc.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 101, PromotionThreshold: 1}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a promotion threshold of zero", func() {
+			nc.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 10, PromotionThreshold: 0}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("Tenancy", func() {
+		It("should succeed with dedicated tenancy", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{Type: "dedicated"}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with host tenancy, a host resource group, and host affinity", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{
+				Type:                 "host",
+				HostResourceGroupARN: aws.String("arn:aws:resource-groups:us-west-2:123456789012:group/test-hrg"),
+				HostAffinity:         aws.String("host"),
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with an invalid tenancy type", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{Type: "shared"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an invalid host resource group arn", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{Type: "host", HostResourceGroupARN: aws.String("test-hrg")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with an invalid host affinity", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{Type: "host", HostAffinity: aws.String("dedicated")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a host resource group without host tenancy", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{
+				Type:                 "dedicated",
+				HostResourceGroupARN: aws.String("arn:aws:resource-groups:us-west-2:123456789012:group/test-hrg"),
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with host affinity without host tenancy", func() {
+			nc.Spec.Tenancy = &v1beta1.Tenancy{Type: "default", HostAffinity: aws.String("default")}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("InstanceStorePolicy", func() {
+		DescribeTable("should succeed with each instance store policy", func(policy v1beta1.InstanceStorePolicy) {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		},
+			Entry("RAID0", v1beta1.InstanceStorePolicyRAID0),
+			Entry("RAID0Containerd", v1beta1.InstanceStorePolicyRAID0Containerd),
+			Entry("Mount", v1beta1.InstanceStorePolicyMount),
+			Entry("ExtendedResource", v1beta1.InstanceStorePolicyExtendedResource),
+		)
+		It("should fail with an unknown instance store policy", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicy("RAID1"))
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed with a mount path and the Mount policy", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with a relative mount path", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("mnt/local-disks")
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a mount path and a policy other than Mount", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail with a mount path containing shell metacharacters", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks'; reboot; '")
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should succeed with the Mount policy and the Bottlerocket amiFamily", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail with a mount path and the AL2023 amiFamily", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyAL2023
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
+	Context("PlacementGroupSelector", func() {
+		It("should succeed with a valid placement group selector on name", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg"}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a valid placement group selector on id", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{ID: "pg-0123456789abcdef0"}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should succeed with a valid placement group selector on tags", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Tags: map[string]string{"test": "testvalue"}}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when the placement group selector has no values", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the placement group selector specifies both name and id", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg", ID: "pg-0123456789abcdef0"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the placement group selector specifies both tags and name", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg", Tags: map[string]string{"test": "testvalue"}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the placement group selector has an invalid id", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{ID: "sg-12345"}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when the placement group selector has a tag value that is empty", func() {
+			nc.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Tags: map[string]string{"test": ""}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+	})
 	Context("AMISelectorTerms", func() {
 		It("should succeed with a valid ami selector on tags", func() {
 			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
@@ -452,6 +909,67 @@ var _ = Describe("CEL/Validation", func() {
 			}
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
+		It("should succeed with a valid ami selector on ssmParameter", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-amis/al2023",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		})
+		It("should fail when specifying ssmParameter with tags", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-amis/al2023",
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		DescribeTable("should succeed with a valid alias", func(amiFamily string, alias string) {
+			nc.Spec.AMIFamily = lo.ToPtr(amiFamily)
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: alias}}
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+		},
+			Entry("AL2", v1beta1.AMIFamilyAL2, "al2@v20240807"),
+			Entry("AL2023", v1beta1.AMIFamilyAL2023, "al2023@latest"),
+			Entry("Bottlerocket", v1beta1.AMIFamilyBottlerocket, "bottlerocket@v1.20.5"),
+			Entry("Windows2019", v1beta1.AMIFamilyWindows2019, "windows2019@latest"),
+			Entry("Windows2022", v1beta1.AMIFamilyWindows2022, "windows2022@latest"),
+		)
+		DescribeTable("should fail with an invalid alias", func(amiFamily string, alias string) {
+			nc.Spec.AMIFamily = lo.ToPtr(amiFamily)
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: alias}}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		},
+			Entry("missing version", v1beta1.AMIFamilyAL2023, "al2023"),
+			Entry("empty version", v1beta1.AMIFamilyAL2023, "al2023@"),
+			Entry("unsupported family", v1beta1.AMIFamilyUbuntu, "ubuntu@latest"),
+			Entry("pinned windows version", v1beta1.AMIFamilyWindows2022, "windows2022@v20240807"),
+			Entry("family that doesn't match amiFamily", v1beta1.AMIFamilyAL2, "al2023@latest"),
+		)
+		It("should fail when specifying alias with other fields", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					Alias: "al2@latest",
+					Name:  "my-custom-ami",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
+		It("should fail when specifying alias with other ami selector terms", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					Alias: "al2@latest",
+				},
+				{
+					ID: "ami-12345749",
+				},
+			}
+			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+		})
 		It("should fail when AMIFamily is Custom and not AMISelectorTerms", func() {
 			nc.Spec.AMIFamily = &v1beta1.AMIFamilyCustom
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
@@ -621,18 +1139,132 @@ var _ = Describe("CEL/Validation", func() {
 			})
 			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
 		})
+		It("should succeed with a volume size policy", func() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("map-device-1"),
+							EBS: &v1beta1.BlockDevice{
+								VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+									Base:    lo.ToPtr(resource.MustParse("20Gi")),
+									PerVCPU: lo.ToPtr(resource.MustParse("2Gi")),
+									PerPod:  lo.ToPtr(resource.MustParse("100Mi")),
+									Min:     lo.ToPtr(resource.MustParse("30Gi")),
+									Max:     lo.ToPtr(resource.MustParse("500Gi")),
+								},
+							},
+							RootVolume: true,
+						},
+					},
+				},
+			})
+			Expect(env.Client.Create(ctx, nodeClass)).To(Succeed())
+		})
+		It("should fail when both a volume size and a volume size policy are specified", func() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("map-device-1"),
+							EBS: &v1beta1.BlockDevice{
+								VolumeSize: resource.NewScaledQuantity(50, resource.Giga),
+								VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+									Base: lo.ToPtr(resource.MustParse("20Gi")),
+								},
+							},
+						},
+					},
+				},
+			})
+			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
+		})
+		It("should fail for a volume size policy with a per vCPU size that isn't in Mi or Gi", func() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("map-device-1"),
+							EBS: &v1beta1.BlockDevice{
+								VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+									Base:    lo.ToPtr(resource.MustParse("20Gi")),
+									PerVCPU: lo.ToPtr(resource.MustParse("2Ki")),
+								},
+							},
+						},
+					},
+				},
+			})
+			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
+		})
+		It("should succeed with snapshot selector terms", func() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("map-device-1"),
+							EBS: &v1beta1.BlockDevice{
+								SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{
+									{Tags: map[string]string{"purpose": "image-cache"}},
+									{Name: "image-cache", Owner: "self"},
+								},
+							},
+						},
+					},
+				},
+			})
+			Expect(env.Client.Create(ctx, nodeClass)).To(Succeed())
+		})
+		It("should fail when both a snapshot id and snapshot selector terms are specified", func() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("map-device-1"),
+							EBS: &v1beta1.BlockDevice{
+								SnapshotID:            aws.String("snap-0123456789"),
+								SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Name: "image-cache"}},
+							},
+						},
+					},
+				},
+			})
+			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
+		})
+		It("should fail for a snapshot selector term with only an owner", func() {
+			nodeClass := test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+						{
+							DeviceName: aws.String("map-device-1"),
+							EBS: &v1beta1.BlockDevice{
+								SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Owner: "self"}},
+							},
+						},
+					},
+				},
+			})
+			Expect(env.Client.Create(ctx, nodeClass)).To(Not(Succeed()))
+		})
 	})
-	Context("Role Immutability", func() {
+	Context("Role", func() {
 		It("should fail if role is not defined", func() {
 			nc.Spec.Role = ""
 			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
 		})
-		It("should fail when updating the role", func() {
+		It("should succeed when updating the role", func() {
 			nc.Spec.Role = "test-role"
 			Expect(env.Client.Create(ctx, nc)).To(Succeed())
 
 			nc.Spec.Role = "test-role2"
-			Expect(env.Client.Create(ctx, nc)).ToNot(Succeed())
+			Expect(env.Client.Update(ctx, nc)).To(Succeed())
+		})
+		It("should fail when updating the role to be empty", func() {
+			nc.Spec.Role = "test-role"
+			Expect(env.Client.Create(ctx, nc)).To(Succeed())
+
+			nc.Spec.Role = ""
+			Expect(env.Client.Update(ctx, nc)).ToNot(Succeed())
 		})
 		It("should fail to switch between an unmanaged and managed instance profile", func() {
 			nc.Spec.Role = ""
@@ -104,6 +104,38 @@ var _ = Describe("Webhook/Validation", func() {
 			}
 			Expect(nc.Validate(ctx)).To(Not(Succeed()))
 		})
+		It("should succeed with templated tag values", func() {
+			nc.Spec.Tags = map[string]string{
+				"nodepool":      "{{ .NodePool }}",
+				"placement":     "{{ .Zone }}/{{ .CapacityType }}/{{ .InstanceType }}",
+				"team":          "team-{{ .Labels.team }}",
+				"cost-center":   `{{ index .Labels "example.com/cost-center" }}`,
+				"not-templated": "value",
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail with tag templates that reference unsupported fields", func() {
+			nc.Spec.Tags = map[string]string{"test": "{{ .Region }}"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+			nc.Spec.Tags = map[string]string{"test": "{{ .Labels }}"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+			nc.Spec.Tags = map[string]string{"test": "{{ .Zone.Name }}"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should fail with tag templates that use functions or control structures", func() {
+			nc.Spec.Tags = map[string]string{"test": `{{ printf "%s" .Zone }}`}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+			nc.Spec.Tags = map[string]string{"test": "{{ .Zone | len }}"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+			nc.Spec.Tags = map[string]string{"test": "{{ if .Zone }}zonal{{ end }}"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+			nc.Spec.Tags = map[string]string{"test": "{{ $zone := .Zone }}"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should fail with malformed tag templates", func() {
+			nc.Spec.Tags = map[string]string{"test": "{{ .Zone"}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
 	})
 	Context("SubnetSelectorTerms", func() {
 		It("should succeed with a valid subnet selector on tags", func() {
@@ -203,6 +235,47 @@ var _ = Describe("Webhook/Validation", func() {
 			Expect(nc.Validate(ctx)).ToNot(Succeed())
 		})
 	})
+	Context("PodSubnetSelectorTerms", func() {
+		It("should succeed when pod subnet selector terms aren't set", func() {
+			nc.Spec.PodSubnetSelectorTerms = nil
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed with a valid pod subnet selector on tags", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed with a valid pod subnet selector on id", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{
+					ID: "subnet-12345749",
+				},
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when a pod subnet selector term has no values", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when specifying id with tags", func() {
+			nc.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
+				{
+					ID: "subnet-12345749",
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
 	Context("SecurityGroupSelectorTerms", func() {
 		It("should succeed with a valid security group selector on tags", func() {
 			nc.Spec.SecurityGroupSelectorTerms = []v1beta1.SecurityGroupSelectorTerm{
@@ -464,6 +537,67 @@ var _ = Describe("Webhook/Validation", func() {
 			}
 			Expect(nc.Validate(ctx)).ToNot(Succeed())
 		})
+		It("should succeed with a valid ami selector on ssmParameter", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-amis/al2023",
+				},
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when specifying ssmParameter with tags", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					SSMParameter: "/golden-amis/al2023",
+					Tags: map[string]string{
+						"test": "testvalue",
+					},
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		DescribeTable("should succeed with a valid alias", func(amiFamily string, alias string) {
+			nc.Spec.AMIFamily = lo.ToPtr(amiFamily)
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: alias}}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		},
+			Entry("AL2", v1beta1.AMIFamilyAL2, "al2@v20240807"),
+			Entry("AL2023", v1beta1.AMIFamilyAL2023, "al2023@latest"),
+			Entry("Bottlerocket", v1beta1.AMIFamilyBottlerocket, "bottlerocket@v1.20.5"),
+			Entry("Windows2019", v1beta1.AMIFamilyWindows2019, "windows2019@latest"),
+			Entry("Windows2022", v1beta1.AMIFamilyWindows2022, "windows2022@latest"),
+		)
+		DescribeTable("should fail with an invalid alias", func(amiFamily string, alias string) {
+			nc.Spec.AMIFamily = lo.ToPtr(amiFamily)
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: alias}}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		},
+			Entry("missing version", v1beta1.AMIFamilyAL2023, "al2023"),
+			Entry("empty version", v1beta1.AMIFamilyAL2023, "al2023@"),
+			Entry("unsupported family", v1beta1.AMIFamilyUbuntu, "ubuntu@latest"),
+			Entry("pinned windows version", v1beta1.AMIFamilyWindows2022, "windows2022@v20240807"),
+			Entry("family that doesn't match amiFamily", v1beta1.AMIFamilyAL2, "al2023@latest"),
+		)
+		It("should fail when specifying alias with other fields", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					Alias: "al2@latest",
+					Name:  "my-custom-ami",
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail when specifying alias with other ami selector terms", func() {
+			nc.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{
+					Alias: "al2@latest",
+				},
+				{
+					ID: "ami-12345749",
+				},
+			}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
 	})
 	Context("BlockDeviceMappings", func() {
 		It("should fail if more than one root volume is specified", func() {
@@ -491,15 +625,172 @@ var _ = Describe("Webhook/Validation", func() {
 			})
 			Expect(nodeClass.Validate(ctx)).To(Not(Succeed()))
 		})
+		It("should succeed with a volume size policy", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+						Base:    lo.ToPtr(resource.MustParse("20Gi")),
+						PerVCPU: lo.ToPtr(resource.MustParse("2Gi")),
+						Min:     lo.ToPtr(resource.MustParse("30Gi")),
+						Max:     lo.ToPtr(resource.MustParse("500Gi")),
+					},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when both a volume size and a volume size policy are specified", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					VolumeSize:       resource.NewScaledQuantity(50, resource.Giga),
+					VolumeSizePolicy: &v1beta1.VolumeSizePolicy{Base: lo.ToPtr(resource.MustParse("20Gi"))},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should fail when the volume size policy's min is greater than its max", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+						Base: lo.ToPtr(resource.MustParse("20Gi")),
+						Min:  lo.ToPtr(resource.MustParse("100Gi")),
+						Max:  lo.ToPtr(resource.MustParse("50Gi")),
+					},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should fail when the volume size policy's max is out of bounds", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+						Base: lo.ToPtr(resource.MustParse("20Gi")),
+						Max:  lo.ToPtr(resource.MustParse("100Ti")),
+					},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should succeed with snapshot selector terms", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{
+						{Tags: map[string]string{"purpose": "image-cache"}},
+						{Name: "image-cache", Owner: "self"},
+					},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail when both a snapshot id and snapshot selector terms are specified", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					SnapshotID:            aws.String("snap-0123456789"),
+					SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Name: "image-cache"}},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should fail for a snapshot selector term with only an owner", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Owner: "self"}},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
+		It("should fail for a snapshot selector term with an empty tag value", func() {
+			nc.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+				DeviceName: aws.String("map-device-1"),
+				EBS: &v1beta1.BlockDevice{
+					SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Tags: map[string]string{"purpose": ""}}},
+				},
+			}}
+			Expect(nc.Validate(ctx)).To(Not(Succeed()))
+		})
 	})
-	Context("Role Immutability", func() {
-		It("should fail when updating the role", func() {
+	Context("InstanceStorePolicy", func() {
+		It("should succeed with a mount path and the Mount policy", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail with a relative mount path", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("mnt/local-disks")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail with a mount path and a policy other than Mount", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0Containerd)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail with a mount path containing shell metacharacters", func() {
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks'; reboot; '")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should succeed with the Mount policy and the Bottlerocket amiFamily", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail with a mount path and the AL2023 amiFamily", func() {
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyAL2023
+			nc.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nc.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
+	Context("NetworkOptions", func() {
+		It("should succeed with ipv6Only and default metadata options", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true), IPv6PrefixCount: lo.ToPtr(int64(1))}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should succeed with ipv6Only and metadata options that don't set httpProtocolIPv6", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.MetadataOptions = &v1beta1.MetadataOptions{
+				HTTPEndpoint:            lo.ToPtr("enabled"),
+				HTTPPutResponseHopLimit: lo.ToPtr(int64(2)),
+				HTTPTokens:              lo.ToPtr("required"),
+			}
+			Expect(nc.Validate(ctx)).To(Succeed())
+		})
+		It("should fail with an ipv6 prefix count of zero", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6PrefixCount: lo.ToPtr(int64(0))}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail with ipv6Only and httpProtocolIPv6 disabled", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.MetadataOptions = &v1beta1.MetadataOptions{HTTPProtocolIPv6: lo.ToPtr("disabled")}
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail with ipv6Only and associatePublicIPAddress", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.AssociatePublicIPAddress = lo.ToPtr(true)
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+		It("should fail with ipv6Only and a Windows amiFamily", func() {
+			nc.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: lo.ToPtr(true)}
+			nc.Spec.AMIFamily = &v1beta1.AMIFamilyWindows2022
+			Expect(nc.Validate(ctx)).ToNot(Succeed())
+		})
+	})
+	Context("Role", func() {
+		It("should succeed when updating the role", func() {
 			nc.Spec.Role = "test-role"
 			Expect(nc.Validate(ctx)).To(Succeed())
 
 			updateCtx := apis.WithinUpdate(ctx, nc.DeepCopy())
 			nc.Spec.Role = "test-role2"
-			Expect(nc.Validate(updateCtx)).ToNot(Succeed())
+			Expect(nc.Validate(updateCtx)).To(Succeed())
 		})
 		It("should fail to switch between an unmanaged and managed instance profile", func() {
 			nc.Spec.Role = ""
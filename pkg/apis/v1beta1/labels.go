@@ -46,6 +46,13 @@ func init() {
 		LabelInstanceAcceleratorName,
 		LabelInstanceAcceleratorManufacturer,
 		LabelInstanceAcceleratorCount,
+		LabelInstanceBurstable,
+		LabelInstanceUEFISupported,
+		LabelInstanceENASupport,
+		LabelInstanceEBSNVMeSupport,
+		LabelCapacityReservationID,
+		LabelPlacementGroupPartition,
+		LabelTenancy,
 		v1.LabelWindowsBuild,
 	)
 }
@@ -91,9 +98,26 @@ var (
 	ResourceAWSPodENI          v1.ResourceName = "vpc.amazonaws.com/pod-eni"
 	ResourcePrivateIPv4Address v1.ResourceName = "vpc.amazonaws.com/PrivateIPv4Address"
 	ResourceEFA                v1.ResourceName = "vpc.amazonaws.com/efa"
+	ResourceInstanceStoreDisks v1.ResourceName = "karpenter.k8s.aws/instance-store-disks"
 
 	LabelNodeClass = Group + "/ec2nodeclass"
 
+	// CapacityTypeReserved is the capacity type for instances launched into a capacity reservation
+	CapacityTypeReserved = "reserved"
+
+	CapacityReservationTypeDefault       = "default"
+	CapacityReservationTypeCapacityBlock = "capacity-block"
+
+	InstanceTypeSelectionExplicit       = "explicit"
+	InstanceTypeSelectionAttributeBased = "attribute-based"
+
+	CPUCreditsStandard  = "standard"
+	CPUCreditsUnlimited = "unlimited"
+
+	TenancyDefault   = "default"
+	TenancyDedicated = "dedicated"
+	TenancyHost      = "host"
+
 	LabelInstanceHypervisor                   = Group + "/instance-hypervisor"
 	LabelInstanceEncryptionInTransitSupported = Group + "/instance-encryption-in-transit-supported"
 	LabelInstanceCategory                     = Group + "/instance-category"
@@ -113,11 +137,24 @@ var (
 	LabelInstanceAcceleratorName              = Group + "/instance-accelerator-name"
 	LabelInstanceAcceleratorManufacturer      = Group + "/instance-accelerator-manufacturer"
 	LabelInstanceAcceleratorCount             = Group + "/instance-accelerator-count"
+	LabelInstanceBurstable                    = Group + "/instance-burstable"
+	LabelInstanceUEFISupported                = Group + "/instance-uefi-supported"
+	LabelInstanceENASupport                   = Group + "/instance-ena-support"
+	LabelInstanceEBSNVMeSupport               = Group + "/instance-ebs-nvme-support"
+	LabelCapacityReservationID                = Group + "/capacity-reservation-id"
+	LabelPlacementGroupPartition              = Group + "/placement-group-partition"
+	LabelTenancy                              = Group + "/tenancy"
 	AnnotationEC2NodeClassHash                = Group + "/ec2nodeclass-hash"
 	AnnotationEC2NodeClassHashVersion         = Group + "/ec2nodeclass-hash-version"
+	AnnotationEC2NodeClassFieldHashes         = Group + "/ec2nodeclass-field-hashes"
 	AnnotationInstanceTagged                  = Group + "/tagged"
+	AnnotationEC2NodeClassTags                = Group + "/ec2nodeclass-tags"
+	AnnotationEC2NodeClassSnapshots           = Group + "/ec2nodeclass-snapshots"
 
 	TagNodeClaim             = v1beta1.Group + "/nodeclaim"
 	TagManagedLaunchTemplate = Group + "/cluster"
 	TagName                  = "Name"
+	// TagAMIRequirementPrefix prefixes AMI tags that declare a requirement on a well known instance type label, e.g.
+	// "karpenter.k8s.aws/requirement/instance-gpu-manufacturer: nvidia"
+	TagAMIRequirementPrefix = Group + "/requirement/"
 )
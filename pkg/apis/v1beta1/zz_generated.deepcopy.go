@@ -22,6 +22,7 @@ import (
 	"github.com/awslabs/operatorpkg/status"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	apisv1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -34,6 +35,14 @@ func (in *AMI) DeepCopyInto(out *AMI) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DeprecationTime != nil {
+		in, out := &in.DeprecationTime, &out.DeprecationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMI.
@@ -46,6 +55,44 @@ func (in *AMI) DeepCopy() *AMI {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMIRollout) DeepCopyInto(out *AMIRollout) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMIRollout.
+func (in *AMIRollout) DeepCopy() *AMIRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(AMIRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMIRolloutStatus) DeepCopyInto(out *AMIRolloutStatus) {
+	*out = *in
+	if in.AMIs != nil {
+		in, out := &in.AMIs, &out.AMIs
+		*out = make([]AMI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMIRolloutStatus.
+func (in *AMIRolloutStatus) DeepCopy() *AMIRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AMIRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AMISelectorTerm) DeepCopyInto(out *AMISelectorTerm) {
 	*out = *in
@@ -68,6 +115,43 @@ func (in *AMISelectorTerm) DeepCopy() *AMISelectorTerm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllocationStrategy) DeepCopyInto(out *AllocationStrategy) {
+	*out = *in
+	if in.Spot != nil {
+		in, out := &in.Spot, &out.Spot
+		*out = new(string)
+		**out = **in
+	}
+	if in.OnDemand != nil {
+		in, out := &in.OnDemand, &out.OnDemand
+		*out = new(string)
+		**out = **in
+	}
+	if in.InstanceTypePriorities != nil {
+		in, out := &in.InstanceTypePriorities, &out.InstanceTypePriorities
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.InstanceTypeSelection != nil {
+		in, out := &in.InstanceTypeSelection, &out.InstanceTypeSelection
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllocationStrategy.
+func (in *AllocationStrategy) DeepCopy() *AllocationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AllocationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlockDevice) DeepCopyInto(out *BlockDevice) {
 	*out = *in
@@ -96,6 +180,13 @@ func (in *BlockDevice) DeepCopyInto(out *BlockDevice) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SnapshotSelectorTerms != nil {
+		in, out := &in.SnapshotSelectorTerms, &out.SnapshotSelectorTerms
+		*out = make([]SnapshotSelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Throughput != nil {
 		in, out := &in.Throughput, &out.Throughput
 		*out = new(int64)
@@ -106,6 +197,11 @@ func (in *BlockDevice) DeepCopyInto(out *BlockDevice) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.VolumeSizePolicy != nil {
+		in, out := &in.VolumeSizePolicy, &out.VolumeSizePolicy
+		*out = new(VolumeSizePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.VolumeType != nil {
 		in, out := &in.VolumeType, &out.VolumeType
 		*out = new(string)
@@ -148,6 +244,101 @@ func (in *BlockDeviceMapping) DeepCopy() *BlockDeviceMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUOptions) DeepCopyInto(out *CPUOptions) {
+	*out = *in
+	if in.CoreCount != nil {
+		in, out := &in.CoreCount, &out.CoreCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ThreadsPerCore != nil {
+		in, out := &in.ThreadsPerCore, &out.ThreadsPerCore
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AMDSEVSNP != nil {
+		in, out := &in.AMDSEVSNP, &out.AMDSEVSNP
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUOptions.
+func (in *CPUOptions) DeepCopy() *CPUOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservation) DeepCopyInto(out *CapacityReservation) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservation.
+func (in *CapacityReservation) DeepCopy() *CapacityReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityReservationSelectorTerm) DeepCopyInto(out *CapacityReservationSelectorTerm) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityReservationSelectorTerm.
+func (in *CapacityReservationSelectorTerm) DeepCopy() *CapacityReservationSelectorTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityReservationSelectorTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CreditSpecification) DeepCopyInto(out *CreditSpecification) {
+	*out = *in
+	if in.EstimatedSurplusUtilization != nil {
+		in, out := &in.EstimatedSurplusUtilization, &out.EstimatedSurplusUtilization
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreditSpecification.
+func (in *CreditSpecification) DeepCopy() *CreditSpecification {
+	if in == nil {
+		return nil
+	}
+	out := new(CreditSpecification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EC2NodeClass) DeepCopyInto(out *EC2NodeClass) {
 	*out = *in
@@ -217,6 +408,13 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodSubnetSelectorTerms != nil {
+		in, out := &in.PodSubnetSelectorTerms, &out.PodSubnetSelectorTerms
+		*out = make([]SubnetSelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.SecurityGroupSelectorTerms != nil {
 		in, out := &in.SecurityGroupSelectorTerms, &out.SecurityGroupSelectorTerms
 		*out = make([]SecurityGroupSelectorTerm, len(*in))
@@ -224,6 +422,18 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CapacityReservationSelectorTerms != nil {
+		in, out := &in.CapacityReservationSelectorTerms, &out.CapacityReservationSelectorTerms
+		*out = make([]CapacityReservationSelectorTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementGroupSelector != nil {
+		in, out := &in.PlacementGroupSelector, &out.PlacementGroupSelector
+		*out = new(PlacementGroupSelectorTerm)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AssociatePublicIPAddress != nil {
 		in, out := &in.AssociatePublicIPAddress, &out.AssociatePublicIPAddress
 		*out = new(bool)
@@ -236,6 +446,11 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AMIRollout != nil {
+		in, out := &in.AMIRollout, &out.AMIRollout
+		*out = new(AMIRollout)
+		**out = **in
+	}
 	if in.AMIFamily != nil {
 		in, out := &in.AMIFamily, &out.AMIFamily
 		*out = new(string)
@@ -246,6 +461,11 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(apisv1beta1.KubeletConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstanceProfile != nil {
 		in, out := &in.InstanceProfile, &out.InstanceProfile
 		*out = new(string)
@@ -274,6 +494,26 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 		*out = new(InstanceStorePolicy)
 		**out = **in
 	}
+	if in.InstanceStoreMountPath != nil {
+		in, out := &in.InstanceStoreMountPath, &out.InstanceStoreMountPath
+		*out = new(string)
+		**out = **in
+	}
+	if in.CPUOptions != nil {
+		in, out := &in.CPUOptions, &out.CPUOptions
+		*out = new(CPUOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CreditSpecification != nil {
+		in, out := &in.CreditSpecification, &out.CreditSpecification
+		*out = new(CreditSpecification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkOptions != nil {
+		in, out := &in.NetworkOptions, &out.NetworkOptions
+		*out = new(NetworkOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DetailedMonitoring != nil {
 		in, out := &in.DetailedMonitoring, &out.DetailedMonitoring
 		*out = new(bool)
@@ -284,6 +524,16 @@ func (in *EC2NodeClassSpec) DeepCopyInto(out *EC2NodeClassSpec) {
 		*out = new(MetadataOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllocationStrategy != nil {
+		in, out := &in.AllocationStrategy, &out.AllocationStrategy
+		*out = new(AllocationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tenancy != nil {
+		in, out := &in.Tenancy, &out.Tenancy
+		*out = new(Tenancy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Context != nil {
 		in, out := &in.Context, &out.Context
 		*out = new(string)
@@ -307,7 +557,16 @@ func (in *EC2NodeClassStatus) DeepCopyInto(out *EC2NodeClassStatus) {
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = make([]Subnet, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodSubnets != nil {
+		in, out := &in.PodSubnets, &out.PodSubnets
+		*out = make([]Subnet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.SecurityGroups != nil {
 		in, out := &in.SecurityGroups, &out.SecurityGroups
@@ -321,6 +580,31 @@ func (in *EC2NodeClassStatus) DeepCopyInto(out *EC2NodeClassStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AMIRollout != nil {
+		in, out := &in.AMIRollout, &out.AMIRollout
+		*out = new(AMIRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CapacityReservations != nil {
+		in, out := &in.CapacityReservations, &out.CapacityReservations
+		*out = make([]CapacityReservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PlacementGroup != nil {
+		in, out := &in.PlacementGroup, &out.PlacementGroup
+		*out = new(PlacementGroup)
+		**out = **in
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]Snapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.ObservedGenerations = in.ObservedGenerations
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]status.Condition, len(*in))
@@ -375,6 +659,83 @@ func (in *MetadataOptions) DeepCopy() *MetadataOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkOptions) DeepCopyInto(out *NetworkOptions) {
+	*out = *in
+	if in.IPv6PrefixCount != nil {
+		in, out := &in.IPv6PrefixCount, &out.IPv6PrefixCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.IPv6Only != nil {
+		in, out := &in.IPv6Only, &out.IPv6Only
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkOptions.
+func (in *NetworkOptions) DeepCopy() *NetworkOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedGenerations) DeepCopyInto(out *ObservedGenerations) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedGenerations.
+func (in *ObservedGenerations) DeepCopy() *ObservedGenerations {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedGenerations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementGroup) DeepCopyInto(out *PlacementGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementGroup.
+func (in *PlacementGroup) DeepCopy() *PlacementGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementGroupSelectorTerm) DeepCopyInto(out *PlacementGroupSelectorTerm) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementGroupSelectorTerm.
+func (in *PlacementGroupSelectorTerm) DeepCopy() *PlacementGroupSelectorTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementGroupSelectorTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityGroup) DeepCopyInto(out *SecurityGroup) {
 	*out = *in
@@ -412,9 +773,55 @@ func (in *SecurityGroupSelectorTerm) DeepCopy() *SecurityGroupSelectorTerm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Snapshot) DeepCopyInto(out *Snapshot) {
+	*out = *in
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Snapshot.
+func (in *Snapshot) DeepCopy() *Snapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(Snapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSelectorTerm) DeepCopyInto(out *SnapshotSelectorTerm) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSelectorTerm.
+func (in *SnapshotSelectorTerm) DeepCopy() *SnapshotSelectorTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSelectorTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subnet) DeepCopyInto(out *Subnet) {
 	*out = *in
+	if in.IPv6CIDRs != nil {
+		in, out := &in.IPv6CIDRs, &out.IPv6CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subnet.
@@ -448,3 +855,68 @@ func (in *SubnetSelectorTerm) DeepCopy() *SubnetSelectorTerm {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenancy) DeepCopyInto(out *Tenancy) {
+	*out = *in
+	if in.HostResourceGroupARN != nil {
+		in, out := &in.HostResourceGroupARN, &out.HostResourceGroupARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.HostAffinity != nil {
+		in, out := &in.HostAffinity, &out.HostAffinity
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenancy.
+func (in *Tenancy) DeepCopy() *Tenancy {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenancy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSizePolicy) DeepCopyInto(out *VolumeSizePolicy) {
+	*out = *in
+	if in.Base != nil {
+		in, out := &in.Base, &out.Base
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PerVCPU != nil {
+		in, out := &in.PerVCPU, &out.PerVCPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PerPod != nil {
+		in, out := &in.PerPod, &out.PerPod
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSizePolicy.
+func (in *VolumeSizePolicy) DeepCopy() *VolumeSizePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSizePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,91 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type CreateTagsBatcher struct {
+	batcher *Batcher[ec2.CreateTagsInput, ec2.CreateTagsOutput]
+}
+
+// NewCreateTagsBatcher batches requests that apply the same set of tags into a single call across all of their
+// resources. Changing the tags of an EC2NodeClass retags every instance launched with it, so this keeps the number of
+// mutating calls low enough to avoid throttling.
+func NewCreateTagsBatcher(ctx context.Context, ec2api ec2iface.EC2API) *CreateTagsBatcher {
+	options := Options[ec2.CreateTagsInput, ec2.CreateTagsOutput]{
+		Name:        "create_tags",
+		IdleTimeout: 100 * time.Millisecond,
+		MaxTimeout:  1 * time.Second,
+		// CreateTags accepts up to 1000 resources, and each request tags an instance along with its volumes and interfaces
+		MaxItems:      100,
+		RequestHasher: CreateTagsHasher,
+		BatchExecutor: execCreateTagsBatch(ec2api),
+	}
+	return &CreateTagsBatcher{batcher: NewBatcher(ctx, options)}
+}
+
+func (b *CreateTagsBatcher) CreateTags(ctx context.Context, createTagsInput *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	result := b.batcher.Add(ctx, createTagsInput)
+	return result.Output, result.Err
+}
+
+func CreateTagsHasher(ctx context.Context, input *ec2.CreateTagsInput) uint64 {
+	hash, err := hashstructure.Hash(input.Tags, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed hashing input tags")
+	}
+	return hash
+}
+
+func execCreateTagsBatch(ec2api ec2iface.EC2API) BatchExecutor[ec2.CreateTagsInput, ec2.CreateTagsOutput] {
+	return func(ctx context.Context, inputs []*ec2.CreateTagsInput) []Result[ec2.CreateTagsOutput] {
+		results := make([]Result[ec2.CreateTagsOutput], len(inputs))
+		// aggregate resources into 1 input
+		input := &ec2.CreateTagsInput{
+			Resources: lo.UniqBy(lo.FlatMap(inputs, func(i *ec2.CreateTagsInput, _ int) []*string { return i.Resources }), func(id *string) string { return *id }),
+			Tags:      inputs[0].Tags,
+		}
+		output, err := ec2api.CreateTagsWithContext(ctx, input)
+		if err == nil {
+			for i := range results {
+				results[i] = Result[ec2.CreateTagsOutput]{Output: output}
+			}
+			return results
+		}
+		// A single resource that no longer exists fails the whole call, so we fall back to tagging the resources of
+		// each request separately. This only happens when an instance is terminated while its tags are updated.
+		var wg sync.WaitGroup
+		for i := range inputs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				out, err := ec2api.CreateTagsWithContext(ctx, inputs[i])
+				results[i] = Result[ec2.CreateTagsOutput]{Output: out, Err: err}
+			}(i)
+		}
+		wg.Wait()
+		return results
+	}
+}
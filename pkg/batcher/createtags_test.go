@@ -0,0 +1,86 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/aws/karpenter-provider-aws/pkg/batcher"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateTags Batcher", func() {
+	var ctb *batcher.CreateTagsBatcher
+
+	BeforeEach(func() {
+		fakeEC2API.Reset()
+		ctb = batcher.NewCreateTagsBatcher(ctx, fakeEC2API)
+	})
+
+	It("should batch inputs with the same tags into a single call", func() {
+		instanceIDs := []string{"i-1", "i-2", "i-3", "i-4", "i-5"}
+		for _, id := range instanceIDs {
+			fakeEC2API.Instances.Store(id, &ec2.Instance{})
+		}
+
+		var wg sync.WaitGroup
+		for _, instanceID := range instanceIDs {
+			wg.Add(1)
+			go func(instanceID string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := ctb.CreateTags(ctx, &ec2.CreateTagsInput{
+					Resources: []*string{aws.String(instanceID), aws.String(fmt.Sprintf("vol-%s", instanceID))},
+					Tags:      []*ec2.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+				})
+				Expect(err).To(BeNil())
+			}(instanceID)
+		}
+		wg.Wait()
+
+		Expect(fakeEC2API.CreateTagsBehavior.CalledWithInput.Len()).To(BeNumerically("==", 1))
+		call := fakeEC2API.CreateTagsBehavior.CalledWithInput.Pop()
+		Expect(call.Resources).To(HaveLen(len(instanceIDs) * 2))
+	})
+	It("should not batch inputs with different tags", func() {
+		instanceIDs := []string{"i-1", "i-2", "i-3"}
+		for _, id := range instanceIDs {
+			fakeEC2API.Instances.Store(id, &ec2.Instance{})
+		}
+
+		var wg sync.WaitGroup
+		for _, instanceID := range instanceIDs {
+			wg.Add(1)
+			go func(instanceID string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := ctb.CreateTags(ctx, &ec2.CreateTagsInput{
+					Resources: []*string{aws.String(instanceID)},
+					Tags:      []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String(instanceID)}},
+				})
+				Expect(err).To(BeNil())
+			}(instanceID)
+		}
+		wg.Wait()
+
+		Expect(fakeEC2API.CreateTagsBehavior.CalledWithInput.Len()).To(BeNumerically("==", len(instanceIDs)))
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type DeleteTagsBatcher struct {
+	batcher *Batcher[ec2.DeleteTagsInput, ec2.DeleteTagsOutput]
+}
+
+// NewDeleteTagsBatcher batches requests that remove the same set of tags into a single call across all of their resources
+func NewDeleteTagsBatcher(ctx context.Context, ec2api ec2iface.EC2API) *DeleteTagsBatcher {
+	options := Options[ec2.DeleteTagsInput, ec2.DeleteTagsOutput]{
+		Name:          "delete_tags",
+		IdleTimeout:   100 * time.Millisecond,
+		MaxTimeout:    1 * time.Second,
+		MaxItems:      100,
+		RequestHasher: DeleteTagsHasher,
+		BatchExecutor: execDeleteTagsBatch(ec2api),
+	}
+	return &DeleteTagsBatcher{batcher: NewBatcher(ctx, options)}
+}
+
+func (b *DeleteTagsBatcher) DeleteTags(ctx context.Context, deleteTagsInput *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error) {
+	result := b.batcher.Add(ctx, deleteTagsInput)
+	return result.Output, result.Err
+}
+
+func DeleteTagsHasher(ctx context.Context, input *ec2.DeleteTagsInput) uint64 {
+	hash, err := hashstructure.Hash(input.Tags, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed hashing input tags")
+	}
+	return hash
+}
+
+func execDeleteTagsBatch(ec2api ec2iface.EC2API) BatchExecutor[ec2.DeleteTagsInput, ec2.DeleteTagsOutput] {
+	return func(ctx context.Context, inputs []*ec2.DeleteTagsInput) []Result[ec2.DeleteTagsOutput] {
+		results := make([]Result[ec2.DeleteTagsOutput], len(inputs))
+		// aggregate resources into 1 input
+		input := &ec2.DeleteTagsInput{
+			Resources: lo.UniqBy(lo.FlatMap(inputs, func(i *ec2.DeleteTagsInput, _ int) []*string { return i.Resources }), func(id *string) string { return *id }),
+			Tags:      inputs[0].Tags,
+		}
+		output, err := ec2api.DeleteTagsWithContext(ctx, input)
+		if err == nil {
+			for i := range results {
+				results[i] = Result[ec2.DeleteTagsOutput]{Output: output}
+			}
+			return results
+		}
+		// Fall back to removing the tags from the resources of each request separately, so that a resource that no
+		// longer exists doesn't fail the other requests in the batch
+		var wg sync.WaitGroup
+		for i := range inputs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				out, err := ec2api.DeleteTagsWithContext(ctx, inputs[i])
+				results[i] = Result[ec2.DeleteTagsOutput]{Output: out, Err: err}
+			}(i)
+		}
+		wg.Wait()
+		return results
+	}
+}
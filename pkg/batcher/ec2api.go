@@ -24,6 +24,8 @@ type EC2API struct {
 	*CreateFleetBatcher
 	*DescribeInstancesBatcher
 	*TerminateInstancesBatcher
+	*CreateTagsBatcher
+	*DeleteTagsBatcher
 }
 
 func EC2(ctx context.Context, ec2api ec2iface.EC2API) *EC2API {
@@ -31,5 +33,7 @@ func EC2(ctx context.Context, ec2api ec2iface.EC2API) *EC2API {
 		CreateFleetBatcher:        NewCreateFleetBatcher(ctx, ec2api),
 		DescribeInstancesBatcher:  NewDescribeInstancesBatcher(ctx, ec2api),
 		TerminateInstancesBatcher: NewTerminateInstancesBatcher(ctx, ec2api),
+		CreateTagsBatcher:         NewCreateTagsBatcher(ctx, ec2api),
+		DeleteTagsBatcher:         NewDeleteTagsBatcher(ctx, ec2api),
 	}
 }
@@ -30,6 +30,7 @@ import (
 // GetInstanceTypes responses
 type UnavailableOfferings struct {
 	// key: <capacityType>:<instanceType>:<zone>, value: struct{}{}
+	// placement group scoped key: <placementGroupID>:<capacityType>:<instanceType>:<zone>, value: struct{}{}
 	cache  *cache.Cache
 	SeqNum uint64
 }
@@ -51,6 +52,16 @@ func (u *UnavailableOfferings) IsUnavailable(instanceType, zone, capacityType st
 	return found
 }
 
+// IsUnavailableForPlacementGroup returns true if the offering appears in the cache, either globally or for the
+// provided placement group
+func (u *UnavailableOfferings) IsUnavailableForPlacementGroup(placementGroupID, instanceType, zone, capacityType string) bool {
+	if u.IsUnavailable(instanceType, zone, capacityType) {
+		return true
+	}
+	_, found := u.cache.Get(u.placementGroupKey(placementGroupID, instanceType, zone, capacityType))
+	return found
+}
+
 // MarkUnavailable communicates recently observed temporary capacity shortages in the provided offerings
 func (u *UnavailableOfferings) MarkUnavailable(ctx context.Context, unavailableReason, instanceType, zone, capacityType string) {
 	// even if the key is already in the cache, we still need to call Set to extend the cached entry's TTL
@@ -70,6 +81,23 @@ func (u *UnavailableOfferings) MarkUnavailableForFleetErr(ctx context.Context, f
 	u.MarkUnavailable(ctx, aws.StringValue(fleetErr.ErrorCode), instanceType, zone, capacityType)
 }
 
+// MarkUnavailableForPlacementGroupFleetErr communicates a capacity shortage observed while launching into a placement
+// group. Placement groups constrain where instances can be placed, so capacity shortages within a group don't imply
+// that the offering is unavailable outside of it.
+func (u *UnavailableOfferings) MarkUnavailableForPlacementGroupFleetErr(ctx context.Context, fleetErr *ec2.CreateFleetError, capacityType, placementGroupID string) {
+	instanceType := aws.StringValue(fleetErr.LaunchTemplateAndOverrides.Overrides.InstanceType)
+	zone := aws.StringValue(fleetErr.LaunchTemplateAndOverrides.Overrides.AvailabilityZone)
+	log.FromContext(ctx).WithValues(
+		"reason", aws.StringValue(fleetErr.ErrorCode),
+		"instance-type", instanceType,
+		"zone", zone,
+		"capacity-type", capacityType,
+		"placement-group", placementGroupID,
+		"ttl", UnavailableOfferingsTTL).V(1).Info("removing offering from offerings for placement group")
+	u.cache.SetDefault(u.placementGroupKey(placementGroupID, instanceType, zone, capacityType), struct{}{})
+	atomic.AddUint64(&u.SeqNum, 1)
+}
+
 func (u *UnavailableOfferings) Delete(instanceType string, zone string, capacityType string) {
 	u.cache.Delete(u.key(instanceType, zone, capacityType))
 }
@@ -82,3 +110,8 @@ func (u *UnavailableOfferings) Flush() {
 func (u *UnavailableOfferings) key(instanceType string, zone string, capacityType string) string {
 	return fmt.Sprintf("%s:%s:%s", capacityType, instanceType, zone)
 }
+
+// placementGroupKey returns the cache key for offerings that are only unavailable within a placement group
+func (u *UnavailableOfferings) placementGroupKey(placementGroupID string, instanceType string, zone string, capacityType string) string {
+	return fmt.Sprintf("%s:%s", placementGroupID, u.key(instanceType, zone, capacityType))
+}
@@ -16,8 +16,10 @@ package cloudprovider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -88,9 +90,12 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *corev1beta1.NodeC
 		// We treat a failure to resolve the NodeClass as an ICE since this means there is no capacity possibilities for this NodeClaim
 		return nil, cloudprovider.NewInsufficientCapacityError(fmt.Errorf("resolving node class, %w", err))
 	}
-	nodeClassReady := nodeClass.StatusConditions().Get(status.ConditionReady)
-	if !nodeClassReady.IsTrue() {
-		return nil, fmt.Errorf("resolving ec2nodeclass, %s", nodeClassReady.Message)
+	if nodeClassReady := nodeClass.StatusConditions().Get(status.ConditionReady); !nodeClassReady.IsTrue() {
+		return nil, fmt.Errorf("resolving ec2nodeclass, %s", notReadyMessage(nodeClass, nodeClassReady))
+	}
+	// Launching from a status that hasn't caught up with a spec change would create a node that's immediately drifted
+	if !nodeClass.StatusObserved() {
+		return nil, cloudprovider.NewNodeClassNotReadyError(fmt.Errorf("ec2nodeclass status hasn't been resolved for generation %d", nodeClass.Generation))
 	}
 	instanceTypes, err := c.resolveInstanceTypes(ctx, nodeClaim, nodeClass)
 	if err != nil {
@@ -110,6 +115,9 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *corev1beta1.NodeC
 	nc.Annotations = lo.Assign(nodeClass.Annotations, map[string]string{
 		v1beta1.AnnotationEC2NodeClassHash:        nodeClass.Hash(),
 		v1beta1.AnnotationEC2NodeClassHashVersion: v1beta1.EC2NodeClassHashVersion,
+		v1beta1.AnnotationEC2NodeClassFieldHashes: string(lo.Must(json.Marshal(nodeClass.FieldHashes()))),
+		v1beta1.AnnotationEC2NodeClassTags:        string(lo.Must(json.Marshal(lo.PickByKeys(instance.Tags, lo.Keys(nodeClass.Spec.Tags))))),
+		v1beta1.AnnotationEC2NodeClassSnapshots:   string(lo.Must(json.Marshal(snapshotIDs(nodeClass)))),
 	})
 	return nc, nil
 }
@@ -222,6 +230,20 @@ func (c *CloudProvider) GetSupportedNodeClasses() []schema.GroupVersionKind {
 	}
 }
 
+// notReadyMessage describes why the EC2NodeClass isn't ready using the messages of its sub-conditions that aren't true,
+// so that launch failures identify the dependency that couldn't be resolved
+func notReadyMessage(nodeClass *v1beta1.EC2NodeClass, ready *status.Condition) string {
+	conditions := lo.Filter(nodeClass.GetConditions(), func(c status.Condition, _ int) bool {
+		return c.Type != status.ConditionReady && !c.IsTrue()
+	})
+	if len(conditions) == 0 {
+		return ready.Message
+	}
+	return strings.Join(lo.Map(conditions, func(c status.Condition, _ int) string {
+		return fmt.Sprintf("%s=%s (%s)", c.Type, c.Status, lo.Ternary(c.Message != "", c.Message, c.Reason))
+	}), ", ")
+}
+
 func (c *CloudProvider) resolveNodeClassFromNodeClaim(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (*v1beta1.EC2NodeClass, error) {
 	nodeClass := &v1beta1.EC2NodeClass{}
 	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Spec.NodeClassRef.Name}, nodeClass); err != nil {
@@ -317,6 +339,15 @@ func (c *CloudProvider) instanceToNodeClaim(i *instance.Instance, instanceType *
 	}
 	labels[v1.LabelTopologyZone] = i.Zone
 	labels[corev1beta1.CapacityTypeLabelKey] = i.CapacityType
+	if i.CapacityReservationID != "" {
+		labels[v1beta1.LabelCapacityReservationID] = i.CapacityReservationID
+	}
+	if i.PlacementGroupPartition != 0 {
+		labels[v1beta1.LabelPlacementGroupPartition] = fmt.Sprint(i.PlacementGroupPartition)
+	}
+	if i.Tenancy != "" {
+		labels[v1beta1.LabelTenancy] = i.Tenancy
+	}
 	if v, ok := i.Tags[corev1beta1.NodePoolLabelKey]; ok {
 		labels[corev1beta1.NodePoolLabelKey] = v
 	}
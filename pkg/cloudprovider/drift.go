@@ -16,7 +16,9 @@ package cloudprovider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
@@ -26,16 +28,20 @@ import (
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	cloudproviderevents "github.com/aws/karpenter-provider-aws/pkg/cloudprovider/events"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
 )
 
 const (
-	AMIDrift           cloudprovider.DriftReason = "AMIDrift"
-	SubnetDrift        cloudprovider.DriftReason = "SubnetDrift"
-	SecurityGroupDrift cloudprovider.DriftReason = "SecurityGroupDrift"
-	NodeClassDrift     cloudprovider.DriftReason = "NodeClassDrift"
+	AMIDrift             cloudprovider.DriftReason = "AMIDrift"
+	SubnetDrift          cloudprovider.DriftReason = "SubnetDrift"
+	SecurityGroupDrift   cloudprovider.DriftReason = "SecurityGroupDrift"
+	PlacementGroupDrift  cloudprovider.DriftReason = "PlacementGroupDrift"
+	SnapshotDrift        cloudprovider.DriftReason = "SnapshotDrift"
+	InstanceProfileDrift cloudprovider.DriftReason = "InstanceProfileDrift"
+	NodeClassDrift       cloudprovider.DriftReason = "NodeClassDrift"
 )
 
 func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *corev1beta1.NodeClaim, nodePool *corev1beta1.NodePool, nodeClass *v1beta1.EC2NodeClass) (cloudprovider.DriftReason, error) {
@@ -59,7 +65,8 @@ func (c *CloudProvider) isNodeClassDrifted(ctx context.Context, nodeClaim *corev
 	if err != nil {
 		return "", fmt.Errorf("calculating subnet drift, %w", err)
 	}
-	drifted := lo.FindOrElse([]cloudprovider.DriftReason{amiDrifted, securitygroupDrifted, subnetDrifted}, "", func(i cloudprovider.DriftReason) bool {
+	drifted := lo.FindOrElse([]cloudprovider.DriftReason{amiDrifted, securitygroupDrifted, subnetDrifted, c.isPlacementGroupDrifted(instance, nodeClass),
+		c.isInstanceProfileDrifted(instance, nodeClass), c.isSnapshotDrifted(nodeClaim, nodeClass)}, "", func(i cloudprovider.DriftReason) bool {
 		return string(i) != ""
 	})
 	return drifted, nil
@@ -81,6 +88,10 @@ func (c *CloudProvider) isAMIDrifted(ctx context.Context, nodeClaim *corev1beta1
 		return "", fmt.Errorf("no amis exist given constraints")
 	}
 	mappedAMIs := amifamily.MapToInstanceTypes([]*cloudprovider.InstanceType{nodeInstanceType}, nodeClass.Status.AMIs)
+	// Nodes launched with the AMIs of a progressing rollout aren't drifted, but they are once it's rolled back
+	if canaryAMIs := amifamily.CanaryAMIs(nodeClass); len(canaryAMIs) > 0 {
+		mappedAMIs = lo.Assign(mappedAMIs, amifamily.MapToInstanceTypes([]*cloudprovider.InstanceType{nodeInstanceType}, append(append([]v1beta1.AMI{}, canaryAMIs...), nodeClass.Status.AMIs...)))
+	}
 	if !lo.Contains(lo.Keys(mappedAMIs), instance.ImageID) {
 		return AMIDrift, nil
 	}
@@ -119,6 +130,58 @@ func (c *CloudProvider) areSecurityGroupsDrifted(ec2Instance *instance.Instance,
 	return "", nil
 }
 
+// Checks if the placement group is drifted, by comparing the placement group resolved for the EC2NodeClass to the
+// placement group of the ec2 instance
+func (c *CloudProvider) isPlacementGroupDrifted(ec2Instance *instance.Instance, nodeClass *v1beta1.EC2NodeClass) cloudprovider.DriftReason {
+	if nodeClass.Spec.PlacementGroupSelector == nil {
+		return lo.Ternary(ec2Instance.PlacementGroupID != "", PlacementGroupDrift, "")
+	}
+	// the placement group hasn't been resolved yet, so we can't determine whether the instance is drifted
+	if nodeClass.Status.PlacementGroup == nil {
+		return ""
+	}
+	return lo.Ternary(nodeClass.Status.PlacementGroup.ID != ec2Instance.PlacementGroupID, PlacementGroupDrift, "")
+}
+
+// Checks if the instance profile is drifted, by comparing the instance profile resolved for the EC2NodeClass to the
+// instance profile of the ec2 instance. The resolved instance profile changes when the role of the EC2NodeClass changes.
+func (c *CloudProvider) isInstanceProfileDrifted(ec2Instance *instance.Instance, nodeClass *v1beta1.EC2NodeClass) cloudprovider.DriftReason {
+	// the instance profile hasn't been resolved yet, so we can't determine whether the instance is drifted
+	if nodeClass.Status.InstanceProfile == "" || ec2Instance.InstanceProfile == "" {
+		return ""
+	}
+	return lo.Ternary(nodeClass.Status.InstanceProfile != ec2Instance.InstanceProfile, InstanceProfileDrift, "")
+}
+
+// Checks if the snapshots are drifted, by comparing the snapshots resolved for the EC2NodeClass to the snapshots that
+// the NodeClaim's volumes were created from. NodeClaims that were launched before snapshots were recorded aren't drifted.
+func (c *CloudProvider) isSnapshotDrifted(nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) cloudprovider.DriftReason {
+	annotation, ok := nodeClaim.Annotations[v1beta1.AnnotationEC2NodeClassSnapshots]
+	if !ok {
+		return ""
+	}
+	launched := map[string]string{}
+	if err := json.Unmarshal([]byte(annotation), &launched); err != nil {
+		return ""
+	}
+	// Volumes that were created from a snapshot are drifted once their block device mapping stops selecting snapshots
+	selecting := sets.New(lo.FilterMap(nodeClass.Spec.BlockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping, _ int) (string, bool) {
+		return lo.FromPtr(bdm.DeviceName), bdm.EBS != nil && len(bdm.EBS.SnapshotSelectorTerms) > 0
+	})...)
+	if !selecting.IsSuperset(sets.New(lo.Keys(launched)...)) {
+		return SnapshotDrift
+	}
+	// Block device mappings whose snapshot isn't resolved aren't compared, since we can't determine whether they're drifted
+	return lo.Ternary(lo.SomeBy(nodeClass.Status.Snapshots, func(s v1beta1.Snapshot) bool {
+		return launched[s.DeviceName] != s.ID
+	}), SnapshotDrift, "")
+}
+
+// snapshotIDs returns the ids of the snapshots resolved for the EC2NodeClass, keyed by device name
+func snapshotIDs(nodeClass *v1beta1.EC2NodeClass) map[string]string {
+	return lo.SliceToMap(nodeClass.Status.Snapshots, func(s v1beta1.Snapshot) (string, string) { return s.DeviceName, s.ID })
+}
+
 func (c *CloudProvider) areStaticFieldsDrifted(nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) cloudprovider.DriftReason {
 	nodeClassHash, foundNodeClassHash := nodeClass.Annotations[v1beta1.AnnotationEC2NodeClassHash]
 	nodeClassHashVersion, foundNodeClassHashVersion := nodeClass.Annotations[v1beta1.AnnotationEC2NodeClassHashVersion]
@@ -132,7 +195,34 @@ func (c *CloudProvider) areStaticFieldsDrifted(nodeClaim *corev1beta1.NodeClaim,
 	if nodeClassHashVersion != nodeClaimHashVersion {
 		return ""
 	}
-	return lo.Ternary(nodeClassHash != nodeClaimHash, NodeClassDrift, "")
+	if nodeClassHash == nodeClaimHash {
+		return ""
+	}
+	// The drift reason is kept stable since it's matched on and used as a metric label, so the fields that changed are
+	// reported through an event instead
+	if fields := driftedFields(nodeClaim, nodeClass); len(fields) > 0 {
+		c.recorder.Publish(cloudproviderevents.NodeClaimDriftedFields(nodeClaim, fields))
+	}
+	return NodeClassDrift
+}
+
+// driftedFields returns the EC2NodeClass spec fields that have changed since the NodeClaim was launched. Nothing is
+// returned for NodeClaims that were launched before field hashes were recorded.
+func driftedFields(nodeClaim *corev1beta1.NodeClaim, nodeClass *v1beta1.EC2NodeClass) []string {
+	annotation, ok := nodeClaim.Annotations[v1beta1.AnnotationEC2NodeClassFieldHashes]
+	if !ok {
+		return nil
+	}
+	nodeClaimHashes := map[string]string{}
+	if err := json.Unmarshal([]byte(annotation), &nodeClaimHashes); err != nil {
+		return nil
+	}
+	nodeClassHashes := nodeClass.FieldHashes()
+	fields := lo.Filter(lo.Union(lo.Keys(nodeClaimHashes), lo.Keys(nodeClassHashes)), func(field string, _ int) bool {
+		return nodeClaimHashes[field] != nodeClassHashes[field]
+	})
+	sort.Strings(fields)
+	return fields
 }
 
 func (c *CloudProvider) getInstance(ctx context.Context, providerID string) (*instance.Instance, error) {
@@ -15,6 +15,9 @@ limitations under the License.
 package events
 
 import (
+	"fmt"
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
 
 	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
@@ -30,6 +33,16 @@ func NodePoolFailedToResolveNodeClass(nodePool *v1beta1.NodePool) events.Event {
 	}
 }
 
+func NodeClaimDriftedFields(nodeClaim *v1beta1.NodeClaim, fields []string) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           v1.EventTypeNormal,
+		Reason:         "NodeClassDrifted",
+		Message:        fmt.Sprintf("EC2NodeClass fields changed since launch: %s", strings.Join(fields, ", ")),
+		DedupeValues:   []string{string(nodeClaim.UID), strings.Join(fields, ",")},
+	}
+}
+
 func NodeClaimFailedToResolveNodeClass(nodeClaim *v1beta1.NodeClaim) events.Event {
 	return events.Event{
 		InvolvedObject: nodeClaim,
@@ -16,6 +16,7 @@ package cloudprovider_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -26,7 +27,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/client-go/tools/record"
 	clock "k8s.io/utils/clock/testing"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -48,7 +48,6 @@ import (
 	corecloudproivder "sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/controllers/provisioning"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
-	"sigs.k8s.io/karpenter/pkg/events"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
 	coretest "sigs.k8s.io/karpenter/pkg/test"
@@ -67,7 +66,7 @@ var prov *provisioning.Provisioner
 var cloudProvider *cloudprovider.CloudProvider
 var cluster *state.Cluster
 var fakeClock *clock.FakeClock
-var recorder events.Recorder
+var recorder *coretest.EventRecorder
 
 func TestAWS(t *testing.T) {
 	ctx = TestContextWithLogger(t)
@@ -82,7 +81,7 @@ var _ = BeforeSuite(func() {
 	ctx, stop = context.WithCancel(ctx)
 	awsEnv = test.NewEnvironment(ctx, env)
 	fakeClock = clock.NewFakeClock(time.Now())
-	recorder = events.NewRecorder(&record.FakeRecorder{})
+	recorder = coretest.NewEventRecorder()
 	cloudProvider = cloudprovider.New(awsEnv.InstanceTypesProvider, awsEnv.InstanceProvider, recorder,
 		env.Client, awsEnv.AMIProvider, awsEnv.SecurityGroupProvider, awsEnv.SubnetProvider)
 	cluster = state.NewCluster(fakeClock, env.Client, cloudProvider)
@@ -100,6 +99,7 @@ var _ = BeforeEach(func() {
 
 	cluster.Reset()
 	awsEnv.Reset()
+	recorder.Reset()
 
 	awsEnv.LaunchTemplateProvider.KubeDNSIP = net.ParseIP("10.0.100.10")
 	awsEnv.LaunchTemplateProvider.ClusterEndpoint = "https://test-cluster"
@@ -185,6 +185,51 @@ var _ = Describe("CloudProvider", func() {
 		_, err := cloudProvider.Create(ctx, nodeClaim)
 		Expect(err).To(HaveOccurred())
 	})
+	It("should surface the sub-conditions that aren't ready when the nodeClass isn't ready", func() {
+		nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeSubnetsReady, "SubnetsNotFound", "Failed to resolve subnets")
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+		_, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("SubnetsReady=False (Failed to resolve subnets)"))
+	})
+	It("should return a NodeClassNotReady error until the nodeClass status has observed the latest generation", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		observedGeneration := nodeClass.Generation
+		nodeClass.Status.ObservedGenerations = v1beta1.ObservedGenerations{
+			Subnets:              observedGeneration,
+			SecurityGroups:       observedGeneration,
+			AMIs:                 observedGeneration,
+			CapacityReservations: observedGeneration,
+			PlacementGroup:       observedGeneration,
+			InstanceProfile:      observedGeneration,
+		}
+		nodeClass.Spec.Tags = map[string]string{"test-key": "test-value"}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Generation).To(BeNumerically(">", observedGeneration))
+
+		_, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(corecloudproivder.IsNodeClassNotReadyError(err)).To(BeTrue())
+
+		nodeClass.Status.ObservedGenerations.SecurityGroups = nodeClass.Generation
+		ExpectApplied(ctx, env.Client, nodeClass)
+		_, err = cloudProvider.Create(ctx, nodeClaim)
+		Expect(corecloudproivder.IsNodeClassNotReadyError(err)).To(BeTrue())
+
+		nodeClass.Status.ObservedGenerations = v1beta1.ObservedGenerations{
+			Subnets:              nodeClass.Generation,
+			SecurityGroups:       nodeClass.Generation,
+			AMIs:                 nodeClass.Generation,
+			CapacityReservations: nodeClass.Generation,
+			PlacementGroup:       nodeClass.Generation,
+			InstanceProfile:      nodeClass.Generation,
+		}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloudProviderNodeClaim).ToNot(BeNil())
+	})
 	It("should return an ICE error when there are no instance types to launch", func() {
 		// Specify no instance types and expect to receive a capacity error
 		nodeClaim.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
@@ -225,6 +270,29 @@ var _ = Describe("CloudProvider", func() {
 		Expect(ok).To(BeTrue())
 		Expect(v).To(Equal(v1beta1.EC2NodeClassHashVersion))
 	})
+	It("should return the EC2NodeClass tags that were applied at launch on the nodeClaim", func() {
+		nodeClass.Spec.Tags = map[string]string{"team": "a", "zone": "{{ .Zone }}"}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+		cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(err).To(BeNil())
+		Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		// The zone isn't known until the instance has launched, so the templated tag is applied afterwards
+		Expect(cloudProviderNodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEC2NodeClassTags, `{"team":"a"}`))
+	})
+	It("should return the snapshots that the volumes were created from on the nodeClaim", func() {
+		nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+			DeviceName: aws.String("/dev/xvdb"),
+			EBS: &v1beta1.BlockDevice{
+				SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Tags: map[string]string{"purpose": "image-cache"}}},
+			},
+		}}
+		nodeClass.Status.Snapshots = []v1beta1.Snapshot{{DeviceName: "/dev/xvdb", ID: "snap-test1"}}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass, nodeClaim)
+		cloudProviderNodeClaim, err := cloudProvider.Create(ctx, nodeClaim)
+		Expect(err).To(BeNil())
+		Expect(cloudProviderNodeClaim).ToNot(BeNil())
+		Expect(cloudProviderNodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEC2NodeClassSnapshots, `{"/dev/xvdb":"snap-test1"}`))
+	})
 	Context("EC2 Context", func() {
 		contextID := "context-1234"
 		It("should set context on the CreateFleet request if specified on the NodePool", func() {
@@ -715,6 +783,26 @@ var _ = Describe("CloudProvider", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(isDrifted).To(Equal(cloudprovider.AMIDrift))
 		})
+		It("should only return drifted for AMIs of a progressing rollout once it's rolled back", func() {
+			canaryAMIID := fake.ImageID()
+			instance.ImageId = aws.String(canaryAMIID)
+			nodeClass.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 10, PromotionThreshold: 1}
+			nodeClass.Status.AMIRollout = &v1beta1.AMIRolloutStatus{
+				AMIs:      []v1beta1.AMI{{ID: canaryAMIID, Requirements: nodeClass.Status.AMIs[0].Requirements}},
+				Phase:     v1beta1.AMIRolloutPhaseProgressing,
+				StartTime: metav1.Now(),
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(BeEmpty())
+
+			nodeClass.Status.AMIRollout.Phase = v1beta1.AMIRolloutPhaseRolledBack
+			ExpectApplied(ctx, env.Client, nodeClass)
+			isDrifted, err = cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(Equal(cloudprovider.AMIDrift))
+		})
 		It("should return drifted if there are multiple drift reasons", func() {
 			// Instance is a reference to what we return in the GetInstances call
 			instance.ImageId = aws.String(fake.ImageID())
@@ -790,6 +878,95 @@ var _ = Describe("CloudProvider", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(isDrifted).To(BeEmpty())
 		})
+		It("should return drifted if the instance is in a placement group and the NodeClass doesn't select one", func() {
+			// Instance is a reference to what we return in the GetInstances call
+			instance.Placement.GroupId = aws.String("pg-test1")
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(Equal(cloudprovider.PlacementGroupDrift))
+		})
+		It("should return drifted if the instance placement group doesn't match the discovered value", func() {
+			nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg"}
+			nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{ID: "pg-test2", Name: "test-pg", Strategy: ec2.PlacementStrategySpread}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			// Instance is a reference to what we return in the GetInstances call
+			instance.Placement.GroupId = aws.String("pg-test1")
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(Equal(cloudprovider.PlacementGroupDrift))
+		})
+		It("should not return drifted if the instance placement group matches the discovered value", func() {
+			nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg"}
+			nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{ID: "pg-test1", Name: "test-pg", Strategy: ec2.PlacementStrategySpread}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			// Instance is a reference to what we return in the GetInstances call
+			instance.Placement.GroupId = aws.String("pg-test1")
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(BeEmpty())
+		})
+		It("should return drifted if the instance profile doesn't match the resolved instance profile", func() {
+			nodeClass.Status.InstanceProfile = "test-profile-2"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			// Instance is a reference to what we return in the GetInstances call
+			instance.IamInstanceProfile = &ec2.IamInstanceProfile{Arn: aws.String("arn:aws:iam::123456789012:instance-profile/karpenter/test-profile-1")}
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(Equal(cloudprovider.InstanceProfileDrift))
+		})
+		It("should not return drifted if the instance profile matches the resolved instance profile", func() {
+			nodeClass.Status.InstanceProfile = "test-profile-1"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			// Instance is a reference to what we return in the GetInstances call
+			instance.IamInstanceProfile = &ec2.IamInstanceProfile{Arn: aws.String("arn:aws:iam::123456789012:instance-profile/karpenter/test-profile-1")}
+			isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isDrifted).To(BeEmpty())
+		})
+		Context("Snapshots", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{{
+					DeviceName: aws.String("/dev/xvdb"),
+					EBS: &v1beta1.BlockDevice{
+						SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Tags: map[string]string{"purpose": "image-cache"}}},
+					},
+				}}
+				nodeClass.Status.Snapshots = []v1beta1.Snapshot{{DeviceName: "/dev/xvdb", ID: "snap-test2"}}
+				ExpectApplied(ctx, env.Client, nodeClass)
+			})
+			It("should return drifted if a newer snapshot is resolved than the one the NodeClaim was launched with", func() {
+				nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassSnapshots: `{"/dev/xvdb":"snap-test1"}`})
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.SnapshotDrift))
+			})
+			It("should return drifted if the NodeClaim was launched before the block device mapping selected snapshots", func() {
+				nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassSnapshots: `{}`})
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.SnapshotDrift))
+			})
+			It("should return drifted if the block device mapping no longer selects snapshots", func() {
+				nodeClass.Spec.BlockDeviceMappings[0].EBS = &v1beta1.BlockDevice{VolumeSize: resource.NewScaledQuantity(20, resource.Giga)}
+				nodeClass.Status.Snapshots = nil
+				ExpectApplied(ctx, env.Client, nodeClass)
+				nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassSnapshots: `{"/dev/xvdb":"snap-test2"}`})
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.SnapshotDrift))
+			})
+			It("should not return drifted if the NodeClaim was launched with the resolved snapshot", func() {
+				nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassSnapshots: `{"/dev/xvdb":"snap-test2"}`})
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+			It("should not return drifted if the NodeClaim doesn't record the snapshots it was launched with", func() {
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(isDrifted).To(BeEmpty())
+			})
+		})
 		It("should error if the NodeClaim doesn't have the instance-type label", func() {
 			delete(nodeClaim.Labels, v1.LabelInstanceTypeStable)
 			_, err := cloudProvider.IsDrifted(ctx, nodeClaim)
@@ -916,7 +1093,6 @@ var _ = Describe("CloudProvider", func() {
 					Expect(isDrifted).To(Equal(cloudprovider.NodeClassDrift))
 				},
 				Entry("UserData", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{UserData: lo.ToPtr("userdata-test-2")}}),
-				Entry("Tags", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Tags: map[string]string{"keyTag-test-3": "valueTag-test-3"}}}),
 				Entry("Context", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Context: lo.ToPtr("context-2")}}),
 				Entry("DetailedMonitoring", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{DetailedMonitoring: aws.Bool(true)}}),
 				Entry("AMIFamily", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{AMIFamily: lo.ToPtr(v1beta1.AMIFamilyBottlerocket)}}),
@@ -965,7 +1141,34 @@ var _ = Describe("CloudProvider", func() {
 				Entry("AMI Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{AMISelectorTerms: []v1beta1.AMISelectorTerm{{Tags: map[string]string{"ami-key-1": "ami-value-1"}}}}}),
 				Entry("Subnet Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{{Tags: map[string]string{"sn-key-1": "sn-value-1"}}}}}),
 				Entry("SecurityGroup Drift", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{{Tags: map[string]string{"sg-key": "sg-value"}}}}}),
+				Entry("Role", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Role: "test-role-2"}}),
+				Entry("Tags", v1beta1.EC2NodeClass{Spec: v1beta1.EC2NodeClassSpec{Tags: map[string]string{"keyTag-test-3": "valueTag-test-3"}}}),
 			)
+			It("should return the drifted fields when the NodeClaim has field hashes", func() {
+				nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
+					v1beta1.AnnotationEC2NodeClassFieldHashes: string(lo.Must(json.Marshal(nodeClass.FieldHashes()))),
+				})
+				nodeClass.Spec.UserData = lo.ToPtr("userdata-test-2")
+				nodeClass.Spec.DetailedMonitoring = lo.ToPtr(true)
+				nodeClass.Annotations = lo.Assign(nodeClass.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassHash: nodeClass.Hash()})
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.NodeClassDrift))
+				Expect(recorder.DetectedEvent("EC2NodeClass fields changed since launch: detailedMonitoring, userData")).To(BeTrue())
+			})
+			It("should return drifted for fields that have been unset", func() {
+				nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
+					v1beta1.AnnotationEC2NodeClassFieldHashes: string(lo.Must(json.Marshal(nodeClass.FieldHashes()))),
+				})
+				nodeClass.Spec.Context = nil
+				nodeClass.Annotations = lo.Assign(nodeClass.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassHash: nodeClass.Hash()})
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				isDrifted, err := cloudProvider.IsDrifted(ctx, nodeClaim)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(isDrifted).To(Equal(cloudprovider.NodeClassDrift))
+				Expect(recorder.DetectedEvent("EC2NodeClass fields changed since launch: context")).To(BeTrue())
+			})
 			It("should not return drifted if karpenter.k8s.aws/ec2nodeclass-hash annotation is not present on the NodeClaim", func() {
 				nodeClaim.Annotations = map[string]string{
 					v1beta1.AnnotationEC2NodeClassHashVersion: v1beta1.EC2NodeClassHashVersion,
@@ -1064,7 +1267,7 @@ var _ = Describe("CloudProvider", func() {
 				{SubnetId: aws.String("test-subnet-2"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(100),
 					Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-2")}}},
 			}})
-			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+			controller := status.NewController(env.Client, awsEnv.Clock, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider, awsEnv.PlacementGroupProvider, awsEnv.SnapshotProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceProvider, awsEnv.LaunchTemplateProvider)
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1.LabelTopologyZone: "test-zone-1a"}})
@@ -1081,7 +1284,7 @@ var _ = Describe("CloudProvider", func() {
 				{SubnetId: aws.String("test-subnet-2"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(11),
 					Tags: []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-2")}}},
 			}})
-			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+			controller := status.NewController(env.Client, awsEnv.Clock, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider, awsEnv.PlacementGroupProvider, awsEnv.SnapshotProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceProvider, awsEnv.LaunchTemplateProvider)
 			nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{MaxPods: aws.Int32(1)}
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
@@ -1119,7 +1322,7 @@ var _ = Describe("CloudProvider", func() {
 			}})
 			nodeClass.Spec.SubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{{Tags: map[string]string{"Name": "test-subnet-1"}}}
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
-			controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+			controller := status.NewController(env.Client, awsEnv.Clock, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider, awsEnv.PlacementGroupProvider, awsEnv.SnapshotProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceProvider, awsEnv.LaunchTemplateProvider)
 			ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 			podSubnet1 := coretest.UnschedulablePod()
 			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, podSubnet1)
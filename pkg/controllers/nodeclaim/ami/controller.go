@@ -0,0 +1,107 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ami
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+// Controller records the age of the AMI that each NodeClaim was launched with
+type Controller struct {
+	kubeClient  client.Client
+	amiProvider amifamily.Provider
+	clk         clock.Clock
+}
+
+func NewController(kubeClient client.Client, amiProvider amifamily.Provider, clk clock.Clock) *Controller {
+	return &Controller{
+		kubeClient:  kubeClient,
+		amiProvider: amiProvider,
+		clk:         clk,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodeclaim.ami")
+
+	if !nodeClaim.DeletionTimestamp.IsZero() {
+		amiAge.DeletePartialMatch(prometheus.Labels{nodeClaimLabel: nodeClaim.Name})
+		return reconcile.Result{}, nil
+	}
+	if nodeClaim.Status.ImageID == "" {
+		return reconcile.Result{}, nil
+	}
+	creationTime, err := c.creationTime(ctx, nodeClaim)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	amiAge.With(prometheus.Labels{
+		nodeClaimLabel: nodeClaim.Name,
+		nodePoolLabel:  nodeClaim.Labels[corev1beta1.NodePoolLabelKey],
+		imageIDLabel:   nodeClaim.Status.ImageID,
+	}).Set(c.clk.Since(creationTime).Seconds())
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// creationTime returns the creation time of the AMI that the NodeClaim was launched with. AMIs that are still selected
+// by the EC2NodeClass are resolved from its status, while AMIs that have since been replaced are described.
+func (c *Controller) creationTime(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (time.Time, error) {
+	if nodeClaim.Spec.NodeClassRef != nil {
+		nodeClass := &v1beta1.EC2NodeClass{}
+		if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Spec.NodeClassRef.Name}, nodeClass); client.IgnoreNotFound(err) != nil {
+			return time.Time{}, err
+		}
+		if ami, ok := lo.Find(nodeClass.Status.AMIs, func(a v1beta1.AMI) bool { return a.ID == nodeClaim.Status.ImageID }); ok && ami.CreationTime != nil {
+			return ami.CreationTime.Time, nil
+		}
+	}
+	ami, err := c.amiProvider.Get(ctx, nodeClaim.Status.ImageID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting ami, %w", err)
+	}
+	creationTime, err := time.Parse(time.RFC3339, ami.CreationDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing creation date of ami %q, %w", ami.AmiID, err)
+	}
+	return creationTime, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodeclaim.ami").
+		For(&corev1beta1.NodeClaim{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.(*corev1beta1.NodeClaim).Status.ImageID != ""
+		})).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
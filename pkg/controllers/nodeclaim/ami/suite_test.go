@@ -0,0 +1,178 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ami_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clock "k8s.io/utils/clock/testing"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclaim/ami"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var awsEnv *test.Environment
+var env *coretest.Environment
+var fakeClock *clock.FakeClock
+var amiController *ami.Controller
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AMIController")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	ctx = coreoptions.ToContext(ctx, coretest.Options())
+	ctx = options.ToContext(ctx, test.Options())
+	awsEnv = test.NewEnvironment(ctx, env)
+	fakeClock = clock.NewFakeClock(time.Now().Truncate(time.Second))
+	amiController = ami.NewController(env.Client, awsEnv.AMIProvider, fakeClock)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	awsEnv.Reset()
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("AMIController", func() {
+	var nodeClass *v1beta1.EC2NodeClass
+	var nodeClaim *corev1beta1.NodeClaim
+
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Status: v1beta1.EC2NodeClassStatus{
+				AMIs: []v1beta1.AMI{
+					{
+						ID:           "ami-current",
+						CreationTime: &metav1.Time{Time: fakeClock.Now().Add(-time.Hour)},
+						Requirements: []v1.NodeSelectorRequirement{
+							{
+								Key:      v1.LabelArchStable,
+								Operator: v1.NodeSelectorOpIn,
+								Values:   []string{corev1beta1.ArchitectureAmd64},
+							},
+						},
+					},
+				},
+			},
+		})
+		nodeClaim = coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					corev1beta1.NodePoolLabelKey: "default",
+				},
+			},
+			Spec: corev1beta1.NodeClaimSpec{
+				NodeClassRef: &corev1beta1.NodeClassReference{
+					Name: nodeClass.Name,
+				},
+			},
+			Status: corev1beta1.NodeClaimStatus{
+				ImageID: "ami-current",
+			},
+		})
+	})
+	It("should record the age of an AMI that's resolved in the EC2NodeClass status", func() {
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		result := ExpectObjectReconciled(ctx, env.Client, amiController, nodeClaim)
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		metric, ok := FindMetricWithLabelValues("karpenter_nodeclaims_ami_age_seconds", map[string]string{
+			"nodeclaim": nodeClaim.Name,
+			"nodepool":  "default",
+			"image_id":  "ami-current",
+		})
+		Expect(ok).To(BeTrue())
+		Expect(metric.GetGauge().GetValue()).To(BeNumerically("==", time.Hour.Seconds()))
+	})
+	It("should record the age of an AMI that's no longer selected by the EC2NodeClass", func() {
+		nodeClaim.Status.ImageID = "ami-previous"
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					Name:            aws.String("ami-previous"),
+					ImageId:         aws.String("ami-previous"),
+					CreationDate:    aws.String(fakeClock.Now().Add(-48 * time.Hour).Format(time.RFC3339)),
+					DeprecationTime: aws.String(fakeClock.Now().Add(-time.Hour).Format(time.RFC3339)),
+					Architecture:    aws.String("x86_64"),
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, amiController, nodeClaim)
+
+		metric, ok := FindMetricWithLabelValues("karpenter_nodeclaims_ami_age_seconds", map[string]string{
+			"nodeclaim": nodeClaim.Name,
+			"image_id":  "ami-previous",
+		})
+		Expect(ok).To(BeTrue())
+		Expect(metric.GetGauge().GetValue()).To(BeNumerically("==", (48 * time.Hour).Seconds()))
+	})
+	It("should not record a metric for a NodeClaim that hasn't launched", func() {
+		nodeClaim.Status.ImageID = ""
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, amiController, nodeClaim)
+
+		_, ok := FindMetricWithLabelValues("karpenter_nodeclaims_ami_age_seconds", map[string]string{
+			"nodeclaim": nodeClaim.Name,
+		})
+		Expect(ok).To(BeFalse())
+	})
+	It("should remove the metric when the NodeClaim is deleted", func() {
+		nodeClaim.Finalizers = []string{corev1beta1.TerminationFinalizer}
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, amiController, nodeClaim)
+		_, ok := FindMetricWithLabelValues("karpenter_nodeclaims_ami_age_seconds", map[string]string{
+			"nodeclaim": nodeClaim.Name,
+		})
+		Expect(ok).To(BeTrue())
+
+		Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, amiController, nodeClaim)
+		_, ok = FindMetricWithLabelValues("karpenter_nodeclaims_ami_age_seconds", map[string]string{
+			"nodeclaim": nodeClaim.Name,
+		})
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityblock
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+// Controller drains NodeClaims that were launched into a capacity block before EC2 terminates their instances at
+// the end of the block
+type Controller struct {
+	kubeClient client.Client
+	clk        clock.Clock
+	recorder   events.Recorder
+}
+
+func NewController(kubeClient client.Client, clk clock.Clock, recorder events.Recorder) *Controller {
+	return &Controller{
+		kubeClient: kubeClient,
+		clk:        clk,
+		recorder:   recorder,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodeClaim *corev1beta1.NodeClaim) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodeclaim.capacityblock")
+
+	if !isDrainable(nodeClaim) {
+		return reconcile.Result{}, nil
+	}
+	nodeClass := &v1beta1.EC2NodeClass{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Spec.NodeClassRef.Name}, nodeClass); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	id := nodeClaim.Labels[v1beta1.LabelCapacityReservationID]
+	capacityReservation, ok := lo.Find(nodeClass.Status.CapacityReservations, func(cr v1beta1.CapacityReservation) bool { return cr.ID == id })
+	// The capacity reservation may not have been resolved into the EC2NodeClass's status yet
+	if !ok {
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	drainTime, ok := capacityreservation.DrainTime(capacityReservation)
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+	// The end time of a capacity block can be extended, so we re-resolve it from the status when the drain time is reached
+	if now := c.clk.Now(); now.Before(drainTime) {
+		return reconcile.Result{RequeueAfter: drainTime.Sub(now)}, nil
+	}
+	if err := c.kubeClient.Delete(ctx, nodeClaim); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	c.recorder.Publish(CapacityBlockExpiringEvent(nodeClaim, id, capacityReservation.EndTime.Time))
+	log.FromContext(ctx).WithValues("capacity-reservation-id", id, "end-time", capacityReservation.EndTime.Time).Info("deleted nodeclaim ahead of capacity block expiration")
+	return reconcile.Result{}, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodeclaim.capacityblock").
+		For(&corev1beta1.NodeClaim{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return isDrainable(o.(*corev1beta1.NodeClaim))
+		})).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
+
+func isDrainable(nc *corev1beta1.NodeClaim) bool {
+	// NodeClaim wasn't launched into a capacity reservation
+	if _, ok := nc.Labels[v1beta1.LabelCapacityReservationID]; !ok {
+		return false
+	}
+	if nc.Spec.NodeClassRef == nil {
+		return false
+	}
+	// NodeClaim is currently terminating
+	if !nc.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityblock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clock "k8s.io/utils/clock/testing"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis"
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/controllers/nodeclaim/capacityblock"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	"sigs.k8s.io/karpenter/pkg/operator/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var env *coretest.Environment
+var fakeClock *clock.FakeClock
+var recorder *coretest.EventRecorder
+var capacityBlockController *capacityblock.Controller
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CapacityBlockController")
+}
+
+var _ = BeforeSuite(func() {
+	env = coretest.NewEnvironment(scheme.Scheme, coretest.WithCRDs(apis.CRDs...))
+	fakeClock = clock.NewFakeClock(time.Now())
+	recorder = coretest.NewEventRecorder()
+	capacityBlockController = capacityblock.NewController(env.Client, fakeClock, recorder)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	recorder.Reset()
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("CapacityBlockController", func() {
+	var nodeClass *v1beta1.EC2NodeClass
+	var nodeClaim *corev1beta1.NodeClaim
+	var endTime time.Time
+
+	BeforeEach(func() {
+		endTime = fakeClock.Now().Add(time.Hour)
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Status: v1beta1.EC2NodeClassStatus{
+				CapacityReservations: []v1beta1.CapacityReservation{
+					{
+						ID:                     "cr-block",
+						InstanceType:           "p5.48xlarge",
+						Zone:                   "test-zone-1a",
+						OwnerID:                "012345678901",
+						AvailableInstanceCount: 1,
+						ReservationType:        v1beta1.CapacityReservationTypeCapacityBlock,
+						EndTime:                &metav1.Time{Time: endTime},
+					},
+					{
+						ID:                     "cr-default",
+						InstanceType:           "m5.large",
+						Zone:                   "test-zone-1a",
+						OwnerID:                "012345678901",
+						AvailableInstanceCount: 1,
+						ReservationType:        v1beta1.CapacityReservationTypeDefault,
+						EndTime:                &metav1.Time{Time: endTime},
+					},
+				},
+			},
+		})
+		nodeClaim = coretest.NodeClaim(corev1beta1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1beta1.LabelCapacityReservationID: "cr-block",
+				},
+				Finalizers: []string{corev1beta1.TerminationFinalizer},
+			},
+			Spec: corev1beta1.NodeClaimSpec{
+				NodeClassRef: &corev1beta1.NodeClassReference{
+					Name: nodeClass.Name,
+				},
+			},
+		})
+	})
+	It("should requeue until the drain time of the capacity block", func() {
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		result := ExpectObjectReconciled(ctx, env.Client, capacityBlockController, nodeClaim)
+		Expect(result.RequeueAfter).To(Equal(endTime.Add(-capacityreservation.CapacityBlockDrainLeadTime).Sub(fakeClock.Now())))
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+	})
+	It("should delete the nodeclaim once the drain time of the capacity block is reached", func() {
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.SetTime(endTime.Add(-capacityreservation.CapacityBlockDrainLeadTime))
+		DeferCleanup(func() { fakeClock.SetTime(time.Now()) })
+		ExpectObjectReconciled(ctx, env.Client, capacityBlockController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeFalse())
+		Expect(recorder.Calls("CapacityBlockExpiring")).To(Equal(1))
+	})
+	It("should requeue when the capacity reservation hasn't been resolved into the EC2NodeClass status", func() {
+		nodeClaim.Labels[v1beta1.LabelCapacityReservationID] = "cr-unresolved"
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.SetTime(endTime)
+		DeferCleanup(func() { fakeClock.SetTime(time.Now()) })
+		result := ExpectObjectReconciled(ctx, env.Client, capacityBlockController, nodeClaim)
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+	})
+	It("should not delete nodeclaims launched into a default capacity reservation", func() {
+		nodeClaim.Labels[v1beta1.LabelCapacityReservationID] = "cr-default"
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.SetTime(endTime)
+		DeferCleanup(func() { fakeClock.SetTime(time.Now()) })
+		result := ExpectObjectReconciled(ctx, env.Client, capacityBlockController, nodeClaim)
+		Expect(result.RequeueAfter).To(BeZero())
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+	})
+	It("should not delete nodeclaims that weren't launched into a capacity reservation", func() {
+		delete(nodeClaim.Labels, v1beta1.LabelCapacityReservationID)
+		ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+		fakeClock.SetTime(endTime)
+		DeferCleanup(func() { fakeClock.SetTime(time.Now()) })
+		ExpectObjectReconciled(ctx, env.Client, capacityBlockController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+	})
+})
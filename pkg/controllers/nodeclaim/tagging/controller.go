@@ -16,13 +16,18 @@ package tagging
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -44,6 +49,8 @@ import (
 type Controller struct {
 	kubeClient       client.Client
 	instanceProvider instance.Provider
+	// mu serializes the per-instance Name tagging calls, which can't be batched since their tags are unique
+	mu sync.Mutex
 }
 
 func NewController(kubeClient client.Client, instanceProvider instance.Provider) *Controller {
@@ -67,15 +74,38 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClaim *corev1beta1.NodeC
 		log.FromContext(ctx).Error(err, "failed parsing instance id")
 		return reconcile.Result{}, nil
 	}
-	if err = c.tagInstance(ctx, nodeClaim, id); err != nil {
-		return reconcile.Result{}, cloudprovider.IgnoreNodeClaimNotFoundError(err)
+	desiredTags, err := c.desiredNodeClassTags(ctx, nodeClaim)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !needsNameTags(nodeClaim) && desiredTags == nil {
+		return reconcile.Result{}, nil
+	}
+	instance, err := c.instanceProvider.Get(ctx, id)
+	if err != nil {
+		return reconcile.Result{}, cloudprovider.IgnoreNodeClaimNotFoundError(fmt.Errorf("tagging nodeclaim, %w", err))
+	}
+	if needsNameTags(nodeClaim) {
+		if err = c.tagInstance(ctx, nodeClaim, instance); err != nil {
+			return reconcile.Result{}, cloudprovider.IgnoreNodeClaimNotFoundError(err)
+		}
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationInstanceTagged: "true"})
+	}
+	if desiredTags != nil {
+		if err = c.convergeNodeClassTags(ctx, nodeClaim, instance, desiredTags); err != nil {
+			return reconcile.Result{}, cloudprovider.IgnoreNodeClaimNotFoundError(err)
+		}
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationEC2NodeClassTags: string(lo.Must(json.Marshal(desiredTags)))})
 	}
-	nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1beta1.AnnotationInstanceTagged: "true"})
 	if !equality.Semantic.DeepEqual(nodeClaim, stored) {
 		if err := c.kubeClient.Patch(ctx, nodeClaim, client.MergeFrom(stored)); err != nil {
 			return reconcile.Result{}, client.IgnoreNotFound(err)
 		}
 	}
+	if desiredTags != nil {
+		// Tags can be modified out-of-band, so they're periodically re-converged
+		return reconcile.Result{RequeueAfter: 10 * time.Minute}, nil
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -84,26 +114,39 @@ func (c *Controller) Register(_ context.Context, m manager.Manager) error {
 		Named("nodeclaim.tagging").
 		For(&corev1beta1.NodeClaim{}).
 		WithEventFilter(predicate.NewPredicateFuncs(func(o client.Object) bool {
-			return isTaggable(o.(*corev1beta1.NodeClaim))
+			if nodeClaim, ok := o.(*corev1beta1.NodeClaim); ok {
+				return isTaggable(nodeClaim)
+			}
+			return true
 		})).
-		// Ok with using the default MaxConcurrentReconciles of 1 to avoid throttling from CreateTag write API
+		// Tag changes on the EC2NodeClass are converged onto all of the instances that were launched with it
+		Watches(
+			&v1beta1.EC2NodeClass{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+				nodeClaimList := &corev1beta1.NodeClaimList{}
+				if err := c.kubeClient.List(ctx, nodeClaimList, client.MatchingFields{"spec.nodeClassRef.name": o.GetName()}); err != nil {
+					return nil
+				}
+				return lo.Map(nodeClaimList.Items, func(nc corev1beta1.NodeClaim, _ int) reconcile.Request {
+					return reconcile.Request{NamespacedName: types.NamespacedName{Name: nc.Name}}
+				})
+			}),
+		).
+		// NodeClaims that apply the same EC2NodeClass tags are reconciled concurrently so that their calls are batched
 		WithOptions(controller.Options{
-			RateLimiter: reasonable.RateLimiter(),
+			RateLimiter:             reasonable.RateLimiter(),
+			MaxConcurrentReconciles: 10,
 		}).
 		Complete(reconcile.AsReconciler(m.GetClient(), c))
 }
 
-func (c *Controller) tagInstance(ctx context.Context, nc *corev1beta1.NodeClaim, id string) error {
+func (c *Controller) tagInstance(ctx context.Context, nc *corev1beta1.NodeClaim, instance *instance.Instance) error {
 	tags := map[string]string{
 		v1beta1.TagName:      nc.Status.NodeName,
 		v1beta1.TagNodeClaim: nc.Name,
 	}
 
 	// Remove tags which have been already populated
-	instance, err := c.instanceProvider.Get(ctx, id)
-	if err != nil {
-		return fmt.Errorf("tagging nodeclaim, %w", err)
-	}
 	tags = lo.OmitByKeys(tags, lo.Keys(instance.Tags))
 	if len(tags) == 0 {
 		return nil
@@ -111,20 +154,101 @@ func (c *Controller) tagInstance(ctx context.Context, nc *corev1beta1.NodeClaim,
 
 	// Ensures that no more than 1 CreateTags call is made per second. Rate limiting is required since CreateTags
 	// shares a pool with other mutating calls (e.g. CreateFleet).
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	defer time.Sleep(time.Second)
-	if err := c.instanceProvider.CreateTags(ctx, id, tags); err != nil {
+	if err := c.instanceProvider.CreateTags(ctx, instance.ID, tags); err != nil {
 		return fmt.Errorf("tagging nodeclaim, %w", err)
 	}
 	return nil
 }
 
-func isTaggable(nc *corev1beta1.NodeClaim) bool {
-	// Instance has already been tagged
-	if val := nc.Annotations[v1beta1.AnnotationInstanceTagged]; val == "true" {
-		return false
+// convergeNodeClassTags updates the tags of the instance, its root volume, its network interfaces and its spot request
+// to match the tags of the EC2NodeClass. The tags of each resource are compared individually, so that out-of-band changes
+// are corrected. Tags that were previously applied from the EC2NodeClass but have since been removed from it are
+// deleted, while tags from any other source are left untouched.
+func (c *Controller) convergeNodeClassTags(ctx context.Context, nc *corev1beta1.NodeClaim, instance *instance.Instance, desired map[string]string) error {
+	resourceIDs := lo.Compact(append([]string{instance.RootVolumeID, instance.SpotInstanceRequestID}, instance.NetworkInterfaceIDs...))
+	current, err := c.instanceProvider.GetTags(ctx, instance.ID, resourceIDs...)
+	if err != nil {
+		return fmt.Errorf("converging ec2nodeclass tags, %w", err)
+	}
+	applied := map[string]string{}
+	if raw, ok := nc.Annotations[v1beta1.AnnotationEC2NodeClassTags]; ok {
+		if err := json.Unmarshal([]byte(raw), &applied); err != nil {
+			log.FromContext(ctx).Error(err, "failed parsing applied ec2nodeclass tags")
+		}
+	} else {
+		// NodeClaims that predate the annotation only own the EC2NodeClass tags that are already on the instance
+		applied = lo.PickByKeys(current[instance.ID], lo.Keys(desired))
+	}
+	create := map[string]string{}
+	var remove []string
+	var createIDs, removeIDs []string
+	for _, id := range append([]string{instance.ID}, resourceIDs...) {
+		missing := lo.PickBy(desired, func(k, v string) bool {
+			value, ok := current[id][k]
+			return !ok || value != v
+		})
+		if len(missing) > 0 {
+			create = lo.Assign(create, missing)
+			createIDs = append(createIDs, id)
+		}
+		stale := lo.Filter(lo.Keys(applied), func(k string, _ int) bool {
+			_, isDesired := desired[k]
+			_, isPresent := current[id][k]
+			return !isDesired && isPresent
+		})
+		if len(stale) > 0 {
+			remove = lo.Union(remove, stale)
+			removeIDs = append(removeIDs, id)
+		}
+	}
+	if len(create) > 0 {
+		if err := c.instanceProvider.CreateTags(ctx, createIDs[0], create, createIDs[1:]...); err != nil {
+			return fmt.Errorf("converging ec2nodeclass tags, %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := c.instanceProvider.DeleteTags(ctx, removeIDs[0], remove, removeIDs[1:]...); err != nil {
+			return fmt.Errorf("converging ec2nodeclass tags, %w", err)
+		}
 	}
-	// Node name is not yet known
-	if nc.Status.NodeName == "" {
+	if len(create) > 0 || len(remove) > 0 {
+		log.FromContext(ctx).WithValues("created", lo.Keys(create), "deleted", remove).V(1).Info("converged ec2nodeclass tags")
+	}
+	return nil
+}
+
+// desiredNodeClassTags returns the tags of the NodeClaim's EC2NodeClass. Templated tags are rendered with the NodeClaim's
+// labels, which include the zone and instance type that weren't known at launch. Nil is returned when the EC2NodeClass
+// isn't found.
+func (c *Controller) desiredNodeClassTags(ctx context.Context, nc *corev1beta1.NodeClaim) (map[string]string, error) {
+	if nc.Spec.NodeClassRef == nil {
+		return nil, nil
+	}
+	nodeClass := &v1beta1.EC2NodeClass{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nc.Spec.NodeClassRef.Name}, nodeClass); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	desired := v1beta1.ResolveTags(nodeClass.Spec.Tags, v1beta1.TagTemplateData{
+		NodePool:     nc.Labels[corev1beta1.NodePoolLabelKey],
+		Zone:         nc.Labels[v1.LabelTopologyZone],
+		CapacityType: nc.Labels[corev1beta1.CapacityTypeLabelKey],
+		InstanceType: nc.Labels[v1.LabelInstanceTypeStable],
+		Labels:       nc.Labels,
+	})
+	return desired, nil
+}
+
+func needsNameTags(nc *corev1beta1.NodeClaim) bool {
+	// Node name is not yet known, or the instance has already been tagged
+	return nc.Status.NodeName != "" && nc.Annotations[v1beta1.AnnotationInstanceTagged] != "true"
+}
+
+func isTaggable(nc *corev1beta1.NodeClaim) bool {
+	// Instance hasn't been launched yet
+	if nc.Status.ProviderID == "" {
 		return false
 	}
 	// NodeClaim is currently terminating
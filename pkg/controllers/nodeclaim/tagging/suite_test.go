@@ -18,11 +18,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/samber/lo"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	coretest "sigs.k8s.io/karpenter/pkg/test"
 
@@ -170,7 +172,7 @@ var _ = Describe("TaggingController", func() {
 				ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
 				NodeName:   "default",
 			},
-			ObjectMeta: v1.ObjectMeta{
+			ObjectMeta: metav1.ObjectMeta{
 				Finalizers: []string{"testing/finalizer"},
 			},
 		})
@@ -224,4 +226,151 @@ var _ = Describe("TaggingController", func() {
 		Entry("with both Name and karpenter.k8s.aws/nodeclaim tags"),
 		Entry("with nothing to tag", v1beta1.TagName, v1beta1.TagNodeClaim),
 	)
+
+	Context("EC2NodeClass Tags", func() {
+		var nodeClass *v1beta1.EC2NodeClass
+		var nodeClaim *corev1beta1.NodeClaim
+
+		BeforeEach(func() {
+			nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+				Spec: v1beta1.EC2NodeClassSpec{
+					Tags: map[string]string{"team": "a", "cost-center": "1234"},
+				},
+			})
+			nodeClaim = coretest.NodeClaim(corev1beta1.NodeClaim{
+				Spec: corev1beta1.NodeClaimSpec{
+					NodeClassRef: &corev1beta1.NodeClassReference{Name: nodeClass.Name},
+				},
+				Status: corev1beta1.NodeClaimStatus{
+					ProviderID: fake.ProviderID(*ec2Instance.InstanceId),
+				},
+			})
+		})
+		It("should apply the EC2NodeClass tags to the instance", func() {
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEC2NodeClassTags, `{"cost-center":"1234","team":"a"}`))
+
+			instanceTags := instance.NewInstance(ec2Instance).Tags
+			Expect(instanceTags).To(HaveKeyWithValue("team", "a"))
+			Expect(instanceTags).To(HaveKeyWithValue("cost-center", "1234"))
+		})
+		It("should update the values of modified tags", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags, &ec2.Tag{Key: aws.String("team"), Value: aws.String("b")})
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEC2NodeClassTags: `{"team":"b"}`}
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+
+			instanceTags := instance.NewInstance(ec2Instance).Tags
+			Expect(instanceTags).To(HaveKeyWithValue("team", "a"))
+			Expect(instanceTags).To(HaveKeyWithValue("cost-center", "1234"))
+		})
+		It("should remove tags that were removed from the EC2NodeClass", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags,
+				&ec2.Tag{Key: aws.String("team"), Value: aws.String("a")},
+				&ec2.Tag{Key: aws.String("cost-center"), Value: aws.String("1234")},
+				&ec2.Tag{Key: aws.String("environment"), Value: aws.String("dev")},
+			)
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEC2NodeClassTags: `{"cost-center":"1234","environment":"dev","team":"a"}`}
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEC2NodeClassTags, `{"cost-center":"1234","team":"a"}`))
+
+			instanceTags := instance.NewInstance(ec2Instance).Tags
+			Expect(instanceTags).ToNot(HaveKey("environment"))
+			Expect(instanceTags).To(HaveKeyWithValue("team", "a"))
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Len()).To(Equal(0))
+		})
+		It("should not remove tags that weren't applied from the EC2NodeClass", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags, &ec2.Tag{Key: aws.String("environment"), Value: aws.String("dev")})
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+
+			Expect(instance.NewInstance(ec2Instance).Tags).To(HaveKeyWithValue("environment", "dev"))
+			Expect(awsEnv.EC2API.DeleteTagsBehavior.CalledWithInput.Len()).To(Equal(0))
+		})
+		It("should apply templated tags that weren't known at launch", func() {
+			nodeClass.Spec.Tags = map[string]string{"team": "a", "placement": "{{ .Zone }}/{{ .InstanceType }}"}
+			nodeClaim.Labels = map[string]string{
+				v1.LabelTopologyZone:       "test-zone-1a",
+				v1.LabelInstanceTypeStable: "m5.large",
+			}
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEC2NodeClassTags: `{"team":"a"}`}
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEC2NodeClassTags, `{"placement":"test-zone-1a/m5.large","team":"a"}`))
+
+			Expect(instance.NewInstance(ec2Instance).Tags).To(HaveKeyWithValue("placement", "test-zone-1a/m5.large"))
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Len()).To(Equal(1))
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Pop().Tags).To(ConsistOf(&ec2.Tag{Key: aws.String("placement"), Value: aws.String("test-zone-1a/m5.large")}))
+		})
+		It("should not modify tags when the EC2NodeClass tags have already been applied", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags,
+				&ec2.Tag{Key: aws.String("team"), Value: aws.String("a")},
+				&ec2.Tag{Key: aws.String("cost-center"), Value: aws.String("1234")},
+			)
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEC2NodeClassTags: `{"cost-center":"1234","team":"a"}`}
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			result := ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+
+			Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Len()).To(Equal(0))
+			Expect(awsEnv.EC2API.DeleteTagsBehavior.CalledWithInput.Len()).To(Equal(0))
+		})
+		It("should correct tags that were modified out-of-band after they were applied", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags,
+				&ec2.Tag{Key: aws.String("team"), Value: aws.String("b")},
+				&ec2.Tag{Key: aws.String("cost-center"), Value: aws.String("1234")},
+			)
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEC2NodeClassTags: `{"cost-center":"1234","team":"a"}`}
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+
+			Expect(instance.NewInstance(ec2Instance).Tags).To(HaveKeyWithValue("team", "a"))
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Len()).To(Equal(1))
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Pop().Tags).To(ConsistOf(&ec2.Tag{Key: aws.String("team"), Value: aws.String("a")}))
+		})
+		It("should converge the tags of the root volume and network interfaces independently of the instance", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags,
+				&ec2.Tag{Key: aws.String("team"), Value: aws.String("a")},
+				&ec2.Tag{Key: aws.String("cost-center"), Value: aws.String("1234")},
+			)
+			ec2Instance.RootDeviceName = aws.String("/dev/xvda")
+			ec2Instance.BlockDeviceMappings = []*ec2.InstanceBlockDeviceMapping{{
+				DeviceName: aws.String("/dev/xvda"),
+				Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String("vol-test1")},
+			}}
+			ec2Instance.NetworkInterfaces = []*ec2.InstanceNetworkInterface{{NetworkInterfaceId: aws.String("eni-test1")}}
+			awsEnv.EC2API.ResourceTags.Store("vol-test1", map[string]string{"team": "a", "cost-center": "1234"})
+			awsEnv.EC2API.ResourceTags.Store("eni-test1", map[string]string{"team": "b", "environment": "dev"})
+			nodeClaim.Annotations = map[string]string{v1beta1.AnnotationEC2NodeClassTags: `{"cost-center":"1234","environment":"dev","team":"a"}`}
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+
+			Expect(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Len()).To(Equal(1))
+			Expect(aws.StringValueSlice(awsEnv.EC2API.CreateTagsBehavior.CalledWithInput.Pop().Resources)).To(ConsistOf("eni-test1"))
+			Expect(awsEnv.EC2API.DeleteTagsBehavior.CalledWithInput.Len()).To(Equal(1))
+			Expect(aws.StringValueSlice(awsEnv.EC2API.DeleteTagsBehavior.CalledWithInput.Pop().Resources)).To(ConsistOf("eni-test1"))
+			eniTags, _ := awsEnv.EC2API.ResourceTags.Load("eni-test1")
+			Expect(eniTags).To(Equal(map[string]string{"team": "a", "cost-center": "1234"}))
+		})
+		It("should only take ownership of the EC2NodeClass tags when the applied tags aren't known", func() {
+			ec2Instance.Tags = append(ec2Instance.Tags,
+				&ec2.Tag{Key: aws.String("team"), Value: aws.String("a")},
+				&ec2.Tag{Key: aws.String("environment"), Value: aws.String("dev")},
+			)
+			ExpectApplied(ctx, env.Client, nodeClass, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, taggingController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1beta1.AnnotationEC2NodeClassTags, `{"cost-center":"1234","team":"a"}`))
+
+			instanceTags := instance.NewInstance(ec2Instance).Tags
+			Expect(instanceTags).To(HaveKeyWithValue("cost-center", "1234"))
+			Expect(instanceTags).To(HaveKeyWithValue("environment", "dev"))
+			Expect(awsEnv.EC2API.DeleteTagsBehavior.CalledWithInput.Len()).To(Equal(0))
+		})
+	})
 })
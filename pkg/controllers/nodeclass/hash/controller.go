@@ -16,6 +16,7 @@ package hash
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/samber/lo"
 	"go.uber.org/multierr"
@@ -102,7 +103,8 @@ func (c *Controller) updateNodeClaimHash(ctx context.Context, nodeClass *v1beta1
 			// Since the hashing mechanism has changed we will not be able to determine if the drifted status of the NodeClaim has changed
 			if nc.StatusConditions().Get(corev1beta1.ConditionTypeDrifted) == nil {
 				nc.Annotations = lo.Assign(nc.Annotations, map[string]string{
-					v1beta1.AnnotationEC2NodeClassHash: nodeClass.Hash(),
+					v1beta1.AnnotationEC2NodeClassHash:        nodeClass.Hash(),
+					v1beta1.AnnotationEC2NodeClassFieldHashes: string(lo.Must(json.Marshal(nodeClass.FieldHashes()))),
 				})
 			}
 
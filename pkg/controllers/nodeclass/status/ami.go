@@ -18,10 +18,14 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
@@ -31,7 +35,9 @@ import (
 )
 
 type AMI struct {
+	kubeClient  client.Client
 	amiProvider amifamily.Provider
+	clk         clock.Clock
 }
 
 func (a *AMI) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
@@ -39,11 +45,13 @@ func (a *AMI) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (r
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("getting amis, %w", err)
 	}
+	nodeClass.Status.ObservedGenerations.AMIs = nodeClass.Generation
 	if len(amis) == 0 {
 		nodeClass.Status.AMIs = nil
-		return reconcile.Result{}, nil
+		nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeAMIsReady, "AMINotFound", "Failed to resolve AMIs")
+		return reconcile.Result{}, nodeClass.StatusConditions().Clear(v1beta1.ConditionTypeAMIsDeprecated)
 	}
-	nodeClass.Status.AMIs = lo.Map(amis, func(ami amifamily.AMI, _ int) v1beta1.AMI {
+	resolved := lo.Map(amis, func(ami amifamily.AMI, _ int) v1beta1.AMI {
 		reqs := lo.Map(ami.Requirements.NodeSelectorRequirements(), func(item corev1beta1.NodeSelectorRequirementWithMinValues, _ int) v1.NodeSelectorRequirement {
 			return item.NodeSelectorRequirement
 		})
@@ -55,10 +63,33 @@ func (a *AMI) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (r
 			return reqs[i].Key < reqs[j].Key
 		})
 		return v1beta1.AMI{
-			Name:         ami.Name,
-			ID:           ami.AmiID,
-			Requirements: reqs,
+			Name:            ami.Name,
+			ID:              ami.AmiID,
+			Requirements:    reqs,
+			CreationTime:    parseTime(ami.CreationDate),
+			DeprecationTime: parseTime(ami.DeprecationDate),
+			Deprecated:      ami.Deprecated,
 		}
 	})
-	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+	nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypeAMIsReady)
+	if lo.EveryBy(amis, func(ami amifamily.AMI) bool { return ami.Deprecated }) {
+		nodeClass.StatusConditions().SetTrueWithReason(v1beta1.ConditionTypeAMIsDeprecated, "AllAMIsDeprecated",
+			fmt.Sprintf("Only deprecated AMIs were resolved (%s)", strings.Join(lo.Uniq(lo.Map(amis, func(ami amifamily.AMI, _ int) string { return ami.AmiID })), ", ")))
+	} else if err := nodeClass.StatusConditions().Clear(v1beta1.ConditionTypeAMIsDeprecated); err != nil {
+		return reconcile.Result{}, err
+	}
+	res, err := a.rollout(ctx, nodeClass, resolved)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return lo.Ternary(res.RequeueAfter != 0, res, reconcile.Result{RequeueAfter: 5 * time.Minute}), nil
+}
+
+// parseTime parses a timestamp returned by the EC2 API, returning nil if it's unset or can't be parsed
+func parseTime(value string) *metav1.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return lo.ToPtr(metav1.NewTime(t))
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
@@ -33,7 +34,11 @@ import (
 )
 
 var _ = Describe("NodeClass AMI Status Controller", func() {
+	var creationTime time.Time
+
 	BeforeEach(func() {
+		// EC2 reports creation dates with second precision
+		creationTime = time.Now().Truncate(time.Second)
 		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
 			Spec: v1beta1.EC2NodeClassSpec{
 				SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
@@ -58,7 +63,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-1"),
 					ImageId:      aws.String("ami-test1"),
-					CreationDate: aws.String(time.Now().Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
@@ -68,7 +73,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-2"),
 					ImageId:      aws.String("ami-test2"),
-					CreationDate: aws.String(time.Now().Add(time.Minute).Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Add(time.Minute).Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-2")},
@@ -78,7 +83,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-3"),
 					ImageId:      aws.String("ami-test3"),
-					CreationDate: aws.String(time.Now().Add(2 * time.Minute).Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Add(2 * time.Minute).Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-3")},
@@ -102,7 +107,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-1"),
 					ImageId:      aws.String("ami-id-123"),
-					CreationDate: aws.String(time.Now().Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
@@ -112,7 +117,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-2"),
 					ImageId:      aws.String("ami-id-456"),
-					CreationDate: aws.String(time.Now().Add(time.Minute).Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Add(time.Minute).Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-2")},
@@ -122,7 +127,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-3"),
 					ImageId:      aws.String("ami-id-789"),
-					CreationDate: aws.String(time.Now().Add(2 * time.Minute).Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Add(2 * time.Minute).Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-3")},
@@ -135,10 +140,11 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 		ExpectApplied(ctx, env.Client, nodeClass)
 		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
-		Expect(nodeClass.Status.AMIs).To(Equal([]v1beta1.AMI{
+		Expect(nodeClass.Status.AMIs).To(BeComparableTo([]v1beta1.AMI{
 			{
-				Name: "test-ami-3",
-				ID:   "ami-id-789",
+				Name:         "test-ami-3",
+				ID:           "ami-id-789",
+				CreationTime: &metav1.Time{Time: creationTime.Add(2 * time.Minute)},
 				Requirements: []v1.NodeSelectorRequirement{
 					{
 						Key:      v1.LabelArchStable,
@@ -156,8 +162,9 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				},
 			},
 			{
-				Name: "test-ami-2",
-				ID:   "ami-id-456",
+				Name:         "test-ami-2",
+				ID:           "ami-id-456",
+				CreationTime: &metav1.Time{Time: creationTime.Add(time.Minute)},
 				Requirements: []v1.NodeSelectorRequirement{
 					{
 						Key:      v1.LabelArchStable,
@@ -171,8 +178,9 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				},
 			},
 			{
-				Name: "test-ami-2",
-				ID:   "ami-id-456",
+				Name:         "test-ami-2",
+				ID:           "ami-id-456",
+				CreationTime: &metav1.Time{Time: creationTime.Add(time.Minute)},
 				Requirements: []v1.NodeSelectorRequirement{
 					{
 						Key:      v1.LabelArchStable,
@@ -186,8 +194,9 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				},
 			},
 			{
-				Name: "test-ami-1",
-				ID:   "ami-id-123",
+				Name:         "test-ami-1",
+				ID:           "ami-id-123",
+				CreationTime: &metav1.Time{Time: creationTime},
 				Requirements: []v1.NodeSelectorRequirement{
 					{
 						Key:      v1.LabelArchStable,
@@ -220,7 +229,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-1"),
 					ImageId:      aws.String("ami-id-123"),
-					CreationDate: aws.String(time.Now().Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Format(time.RFC3339)),
 					Architecture: aws.String("x86_64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
@@ -230,7 +239,7 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				{
 					Name:         aws.String("test-ami-2"),
 					ImageId:      aws.String("ami-id-456"),
-					CreationDate: aws.String(time.Now().Add(time.Minute).Format(time.RFC3339)),
+					CreationDate: aws.String(creationTime.Add(time.Minute).Format(time.RFC3339)),
 					Architecture: aws.String("arm64"),
 					Tags: []*ec2.Tag{
 						{Key: aws.String("Name"), Value: aws.String("test-ami-2")},
@@ -243,10 +252,11 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 
-		Expect(nodeClass.Status.AMIs).To(Equal([]v1beta1.AMI{
+		Expect(nodeClass.Status.AMIs).To(BeComparableTo([]v1beta1.AMI{
 			{
-				Name: "test-ami-2",
-				ID:   "ami-id-456",
+				Name:         "test-ami-2",
+				ID:           "ami-id-456",
+				CreationTime: &metav1.Time{Time: creationTime.Add(time.Minute)},
 				Requirements: []v1.NodeSelectorRequirement{
 					{
 						Key:      v1.LabelArchStable,
@@ -264,8 +274,9 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 				},
 			},
 			{
-				Name: "test-ami-1",
-				ID:   "ami-id-123",
+				Name:         "test-ami-1",
+				ID:           "ami-id-123",
+				CreationTime: &metav1.Time{Time: creationTime},
 				Requirements: []v1.NodeSelectorRequirement{
 					{
 						Key:      v1.LabelArchStable,
@@ -288,11 +299,12 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 		ExpectApplied(ctx, env.Client, nodeClass)
 		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
-		Expect(nodeClass.Status.AMIs).To(Equal(
+		Expect(nodeClass.Status.AMIs).To(BeComparableTo(
 			[]v1beta1.AMI{
 				{
-					Name: "test-ami-3",
-					ID:   "ami-test3",
+					Name:         "test-ami-3",
+					ID:           "ami-test3",
+					CreationTime: &metav1.Time{Time: creationTime.Add(2 * time.Minute)},
 					Requirements: []v1.NodeSelectorRequirement{{
 						Key:      v1.LabelArchStable,
 						Operator: v1.NodeSelectorOpIn,
@@ -303,4 +315,113 @@ var _ = Describe("NodeClass AMI Status Controller", func() {
 			},
 		))
 	})
+	It("Should set AMIsReady to false when no AMIs are resolved", func() {
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{}})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs).To(BeNil())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsReady).Message).To(Equal("Failed to resolve AMIs"))
+	})
+	It("should resolve the deprecation time of AMIs into status", func() {
+		deprecationTime := creationTime.Add(time.Hour)
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					Name:            aws.String("test-ami-1"),
+					ImageId:         aws.String("ami-test1"),
+					CreationDate:    aws.String(creationTime.Format(time.RFC3339)),
+					DeprecationTime: aws.String(deprecationTime.Format(time.RFC3339)),
+					Architecture:    aws.String("x86_64"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
+					},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+		Expect(nodeClass.Status.AMIs[0].CreationTime.Time.Equal(creationTime)).To(BeTrue())
+		Expect(nodeClass.Status.AMIs[0].DeprecationTime.Time.Equal(deprecationTime)).To(BeTrue())
+		Expect(nodeClass.Status.AMIs[0].Deprecated).To(BeFalse())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsDeprecated)).To(BeNil())
+	})
+	It("should prefer AMIs that aren't deprecated and not set AMIsDeprecated", func() {
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					Name:         aws.String("test-ami-1"),
+					ImageId:      aws.String("ami-test1"),
+					CreationDate: aws.String(creationTime.Format(time.RFC3339)),
+					Architecture: aws.String("x86_64"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
+					},
+				},
+				{
+					Name:            aws.String("test-ami-2"),
+					ImageId:         aws.String("ami-test2"),
+					CreationDate:    aws.String(creationTime.Add(time.Minute).Format(time.RFC3339)),
+					DeprecationTime: aws.String(creationTime.Add(-time.Hour).Format(time.RFC3339)),
+					Architecture:    aws.String("x86_64"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-ami-2")},
+					},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-test1"))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsDeprecated)).To(BeNil())
+	})
+	It("should set AMIsDeprecated to true without affecting readiness when only deprecated AMIs are resolved", func() {
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					Name:            aws.String("test-ami-1"),
+					ImageId:         aws.String("ami-test1"),
+					CreationDate:    aws.String(creationTime.Format(time.RFC3339)),
+					DeprecationTime: aws.String(creationTime.Add(-time.Hour).Format(time.RFC3339)),
+					Architecture:    aws.String("x86_64"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-ami-1")},
+					},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+		Expect(nodeClass.Status.AMIs[0].Deprecated).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsDeprecated).IsTrue()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsDeprecated).Message).To(ContainSubstring("ami-test1"))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsReady).IsTrue()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Root().IsTrue()).To(BeTrue())
+
+		// The condition is cleared once an AMI that isn't deprecated is resolved
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					Name:         aws.String("test-ami-2"),
+					ImageId:      aws.String("ami-test2"),
+					CreationDate: aws.String(creationTime.Format(time.RFC3339)),
+					Architecture: aws.String("x86_64"),
+					Tags: []*ec2.Tag{
+						{Key: aws.String("Name"), Value: aws.String("test-ami-2")},
+					},
+				},
+			},
+		})
+		awsEnv.EC2Cache.Flush()
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsDeprecated)).To(BeNil())
+	})
 })
@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+const (
+	// amiRolloutRegistrationTimeout is how long a node launched with the AMIs of a rollout can take to register before
+	// the rollout is rolled back. It's shorter than the registration TTL after which Karpenter deletes the NodeClaim,
+	// so that the failure is observed before the NodeClaim is removed.
+	amiRolloutRegistrationTimeout = 10 * time.Minute
+	// amiRolloutNotReadyTimeout is how long a node launched with the AMIs of a rollout can be NotReady before the
+	// rollout is rolled back
+	amiRolloutNotReadyTimeout = 5 * time.Minute
+	// amiRolloutRequeueInterval is how often the nodes of a progressing rollout are checked
+	amiRolloutRequeueInterval = time.Minute
+)
+
+// rollout stages the resolved AMIs according to the amiRollout of the EC2NodeClass. Newly resolved AMIs only replace
+// the active AMIs in the status once enough nodes launched with them have become ready.
+func (a *AMI) rollout(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, resolved []v1beta1.AMI) (reconcile.Result, error) {
+	// Without a rollout policy, or before any AMIs are active, the resolved AMIs are used immediately
+	if nodeClass.Spec.AMIRollout == nil || len(nodeClass.Status.AMIs) == 0 {
+		nodeClass.Status.AMIs = resolved
+		nodeClass.Status.AMIRollout = nil
+		return reconcile.Result{}, nil
+	}
+	if sameAMIs(resolved, nodeClass.Status.AMIs) {
+		nodeClass.Status.AMIs = resolved
+		// A progressing rollout is abandoned once its AMIs are no longer selected
+		if nodeClass.Status.AMIRollout != nil && nodeClass.Status.AMIRollout.Phase == v1beta1.AMIRolloutPhaseProgressing {
+			nodeClass.Status.AMIRollout = nil
+		}
+		return reconcile.Result{}, nil
+	}
+	rollout := nodeClass.Status.AMIRollout
+	if rollout == nil || rollout.Phase == v1beta1.AMIRolloutPhasePromoted || !sameAMIs(resolved, rollout.AMIs) {
+		nodeClass.Status.AMIRollout = &v1beta1.AMIRolloutStatus{
+			AMIs:      resolved,
+			Phase:     v1beta1.AMIRolloutPhaseProgressing,
+			StartTime: metav1.NewTime(a.clk.Now()),
+		}
+		log.FromContext(ctx).WithValues("ids", amiIDs(resolved)).Info("started ami rollout")
+		return reconcile.Result{RequeueAfter: amiRolloutRequeueInterval}, nil
+	}
+	rollout.AMIs = resolved
+	// A rolled back rollout is only retried once the AMI selection changes
+	if rollout.Phase == v1beta1.AMIRolloutPhaseRolledBack {
+		return reconcile.Result{}, nil
+	}
+	ready, failure, err := a.canaryHealth(ctx, nodeClass, rollout)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("checking ami rollout, %w", err)
+	}
+	if failure != "" {
+		rollout.Phase = v1beta1.AMIRolloutPhaseRolledBack
+		rollout.Message = failure
+		log.FromContext(ctx).WithValues("ids", amiIDs(resolved), "reason", failure).Info("rolled back ami rollout")
+		return reconcile.Result{}, nil
+	}
+	rollout.ReadyNodes = lo.Max([]int32{rollout.ReadyNodes, ready})
+	if rollout.ReadyNodes >= nodeClass.Spec.AMIRollout.PromotionThreshold {
+		nodeClass.Status.AMIs = resolved
+		rollout.Phase = v1beta1.AMIRolloutPhasePromoted
+		log.FromContext(ctx).WithValues("ids", amiIDs(resolved)).Info("promoted ami rollout")
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{RequeueAfter: amiRolloutRequeueInterval}, nil
+}
+
+// canaryHealth returns the number of ready nodes that were launched with the AMIs of the rollout, along with a
+// description of the first of those nodes that failed to register or has been NotReady for too long. A node can't be
+// initialized until it's ready, so the readiness of every registered node is checked, whether or not it's initialized.
+func (a *AMI) canaryHealth(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, rollout *v1beta1.AMIRolloutStatus) (int32, string, error) {
+	ids := sets.New(amiIDs(rollout.AMIs)...)
+	nodeClaimList := &corev1beta1.NodeClaimList{}
+	if err := a.kubeClient.List(ctx, nodeClaimList); err != nil {
+		return 0, "", err
+	}
+	var ready int32
+	for i := range nodeClaimList.Items {
+		nodeClaim := &nodeClaimList.Items[i]
+		// Nodes that are being disrupted are expected to leave the cluster, so they aren't considered
+		if nodeClaim.Spec.NodeClassRef == nil || nodeClaim.Spec.NodeClassRef.Name != nodeClass.Name ||
+			!ids.Has(nodeClaim.Status.ImageID) || !nodeClaim.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if !nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeRegistered).IsTrue() {
+			if launched := nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeLaunched); launched.IsTrue() && a.clk.Since(launched.LastTransitionTime.Time) > amiRolloutRegistrationTimeout {
+				return 0, fmt.Sprintf("NodeClaim %s launched with %s didn't register within %s", nodeClaim.Name, nodeClaim.Status.ImageID, amiRolloutRegistrationTimeout), nil
+			}
+			continue
+		}
+		node := &v1.Node{}
+		if err := a.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Status.NodeName}, node); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return 0, "", err
+		}
+		if condition := nodeutils.GetCondition(node, v1.NodeReady); condition.Status != v1.ConditionTrue {
+			// A node that hasn't reported its readiness yet has been NotReady since it registered
+			notReadySince := condition.LastTransitionTime.Time
+			if condition.Status == "" {
+				notReadySince = nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeRegistered).LastTransitionTime.Time
+			}
+			if a.clk.Since(notReadySince) > amiRolloutNotReadyTimeout {
+				return 0, fmt.Sprintf("Node %s launched with %s has been NotReady for more than %s", node.Name, nodeClaim.Status.ImageID, amiRolloutNotReadyTimeout), nil
+			}
+			continue
+		}
+		if nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeInitialized).IsTrue() {
+			ready++
+		}
+	}
+	return ready, "", nil
+}
+
+func sameAMIs(a, b []v1beta1.AMI) bool {
+	return sets.New(amiIDs(a)...).Equal(sets.New(amiIDs(b)...))
+}
+
+func amiIDs(amis []v1beta1.AMI) []string {
+	return lo.Uniq(lo.Map(amis, func(ami v1beta1.AMI, _ int) string { return ami.ID }))
+}
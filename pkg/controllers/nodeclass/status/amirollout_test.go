@@ -0,0 +1,194 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	coretest "sigs.k8s.io/karpenter/pkg/test"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass AMI Rollout Status Controller", func() {
+	setImage := func(id string) {
+		awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					Name:         aws.String(id),
+					ImageId:      aws.String(id),
+					CreationDate: aws.String(time.Now().Format(time.RFC3339)),
+					Architecture: aws.String("x86_64"),
+				},
+			},
+		})
+		awsEnv.EC2Cache.Flush()
+	}
+	canary := func(id string, launchedAt time.Time, registered bool, ready v1.ConditionStatus) *corev1beta1.NodeClaim {
+		nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{
+			Spec: corev1beta1.NodeClaimSpec{
+				NodeClassRef: &corev1beta1.NodeClassReference{Name: nodeClass.Name},
+			},
+			Status: corev1beta1.NodeClaimStatus{ImageID: id},
+		})
+		nodeClaim.StatusConditions().SetTrue(corev1beta1.ConditionTypeLaunched)
+		if registered {
+			node := coretest.Node(coretest.NodeOptions{ReadyStatus: ready})
+			nodeClaim.Status.NodeName = node.Name
+			nodeClaim.StatusConditions().SetTrue(corev1beta1.ConditionTypeRegistered)
+			// A node can't be initialized until it's ready
+			if ready == v1.ConditionTrue {
+				nodeClaim.StatusConditions().SetTrue(corev1beta1.ConditionTypeInitialized)
+			}
+			node.Status.Conditions[0].LastTransitionTime = metav1.NewTime(launchedAt)
+			ExpectApplied(ctx, env.Client, node)
+		}
+		for i := range nodeClaim.Status.Conditions {
+			nodeClaim.Status.Conditions[i].LastTransitionTime = metav1.NewTime(launchedAt)
+		}
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		return nodeClaim
+	}
+
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Spec: v1beta1.EC2NodeClassSpec{
+				AMISelectorTerms: []v1beta1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}},
+				AMIRollout:       &v1beta1.AMIRollout{CanaryPercentage: 10, PromotionThreshold: 2},
+			},
+		})
+		setImage("ami-old")
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+	})
+	It("should use the first resolved AMIs without a rollout", func() {
+		Expect(nodeClass.Status.AMIs).To(HaveLen(1))
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-old"))
+		Expect(nodeClass.Status.AMIRollout).To(BeNil())
+	})
+	It("should use newly resolved AMIs immediately without a rollout policy", func() {
+		nodeClass.Spec.AMIRollout = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-new"))
+		Expect(nodeClass.Status.AMIRollout).To(BeNil())
+	})
+	It("should start a rollout and keep the active AMIs when new AMIs are resolved", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-old"))
+		Expect(nodeClass.Status.AMIRollout).ToNot(BeNil())
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseProgressing))
+		Expect(nodeClass.Status.AMIRollout.AMIs[0].ID).To(Equal("ami-new"))
+	})
+	It("should promote the rollout once enough canary nodes are ready", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		canary("ami-new", awsEnv.Clock.Now(), true, v1.ConditionTrue)
+		// Nodes launched with the active AMIs don't count towards promotion
+		canary("ami-old", awsEnv.Clock.Now(), true, v1.ConditionTrue)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseProgressing))
+		Expect(nodeClass.Status.AMIRollout.ReadyNodes).To(BeNumerically("==", 1))
+
+		canary("ami-new", awsEnv.Clock.Now(), true, v1.ConditionTrue)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhasePromoted))
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-new"))
+	})
+	It("should roll back the rollout when a canary node doesn't register", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		canary("ami-new", awsEnv.Clock.Now().Add(-time.Hour), false, "")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseRolledBack))
+		Expect(nodeClass.Status.AMIRollout.Message).To(ContainSubstring("didn't register"))
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-old"))
+	})
+	It("should roll back the rollout when a canary node is NotReady", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		canary("ami-new", awsEnv.Clock.Now().Add(-time.Hour), true, v1.ConditionFalse)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseRolledBack))
+		Expect(nodeClass.Status.AMIRollout.Message).To(ContainSubstring("NotReady"))
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-old"))
+	})
+	It("should roll back the rollout once a registered canary node has been NotReady for too long", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClaim := canary("ami-new", awsEnv.Clock.Now(), true, v1.ConditionFalse)
+		Expect(nodeClaim.StatusConditions().Get(corev1beta1.ConditionTypeInitialized).IsTrue()).To(BeFalse())
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseProgressing))
+
+		awsEnv.Clock.Step(6 * time.Minute)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseRolledBack))
+		Expect(nodeClass.Status.AMIRollout.Message).To(ContainSubstring("NotReady"))
+	})
+	It("should roll back the rollout once a canary node hasn't registered in time", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		canary("ami-new", awsEnv.Clock.Now(), false, "")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseProgressing))
+
+		awsEnv.Clock.Step(11 * time.Minute)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseRolledBack))
+		Expect(nodeClass.Status.AMIRollout.Message).To(ContainSubstring("didn't register"))
+	})
+	It("should not wait on canary nodes that are still registering", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		canary("ami-new", awsEnv.Clock.Now(), false, "")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIRollout.Phase).To(Equal(v1beta1.AMIRolloutPhaseProgressing))
+		Expect(nodeClass.Status.AMIRollout.ReadyNodes).To(BeNumerically("==", 0))
+	})
+	It("should abandon a progressing rollout when the active AMIs are selected again", func() {
+		setImage("ami-new")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		setImage("ami-old")
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.AMIs[0].ID).To(Equal("ami-old"))
+		Expect(nodeClass.Status.AMIRollout).To(BeNil())
+	})
+})
@@ -0,0 +1,65 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
+)
+
+type CapacityReservation struct {
+	capacityReservationProvider capacityreservation.Provider
+}
+
+func (c *CapacityReservation) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
+	if len(nodeClass.Spec.CapacityReservationSelectorTerms) == 0 {
+		nodeClass.Status.CapacityReservations = nil
+		nodeClass.Status.ObservedGenerations.CapacityReservations = nodeClass.Generation
+		return reconcile.Result{}, nil
+	}
+	capacityReservations, err := c.capacityReservationProvider.List(ctx, nodeClass)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("getting capacity reservations, %w", err)
+	}
+	nodeClass.Status.ObservedGenerations.CapacityReservations = nodeClass.Generation
+	sort.Slice(capacityReservations, func(i, j int) bool {
+		return aws.StringValue(capacityReservations[i].CapacityReservationId) < aws.StringValue(capacityReservations[j].CapacityReservationId)
+	})
+	nodeClass.Status.CapacityReservations = lo.Map(capacityReservations, func(cr *ec2.CapacityReservation, _ int) v1beta1.CapacityReservation {
+		return v1beta1.CapacityReservation{
+			ID:                     aws.StringValue(cr.CapacityReservationId),
+			InstanceType:           aws.StringValue(cr.InstanceType),
+			Zone:                   aws.StringValue(cr.AvailabilityZone),
+			OwnerID:                aws.StringValue(cr.OwnerId),
+			AvailableInstanceCount: aws.Int64Value(cr.AvailableInstanceCount),
+			ReservationType:        lo.Ternary(aws.StringValue(cr.ReservationType) != "", aws.StringValue(cr.ReservationType), v1beta1.CapacityReservationTypeDefault),
+			StartTime:              lo.Ternary(cr.StartDate != nil, lo.ToPtr(metav1.NewTime(aws.TimeValue(cr.StartDate))), nil),
+			EndTime:                lo.Ternary(cr.EndDate != nil, lo.ToPtr(metav1.NewTime(aws.TimeValue(cr.EndDate))), nil),
+		}
+	})
+	// Available instance counts change as instances launch and terminate, so we re-resolve reservations frequently
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
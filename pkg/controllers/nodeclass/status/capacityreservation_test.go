@@ -0,0 +1,156 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Capacity Reservation Status Controller", func() {
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Spec: v1beta1.EC2NodeClassSpec{
+				SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				CapacityReservationSelectorTerms: []v1beta1.CapacityReservationSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+			},
+		})
+		awsEnv.EC2API.DescribeCapacityReservationsOutput.Set(&ec2.DescribeCapacityReservationsOutput{CapacityReservations: []*ec2.CapacityReservation{
+			{
+				CapacityReservationId:  aws.String("cr-test2"),
+				InstanceType:           aws.String("m5.large"),
+				AvailabilityZone:       aws.String("test-zone-1b"),
+				OwnerId:                aws.String("012345678901"),
+				AvailableInstanceCount: aws.Int64(5),
+				State:                  aws.String(ec2.CapacityReservationStateActive),
+				Tags:                   []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-cr-2")}},
+			},
+			{
+				CapacityReservationId:  aws.String("cr-test1"),
+				InstanceType:           aws.String("m5.large"),
+				AvailabilityZone:       aws.String("test-zone-1a"),
+				OwnerId:                aws.String("012345678901"),
+				AvailableInstanceCount: aws.Int64(2),
+				State:                  aws.String(ec2.CapacityReservationStateActive),
+				Tags:                   []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-cr-1")}},
+			},
+			{
+				CapacityReservationId:  aws.String("cr-test3"),
+				InstanceType:           aws.String("c5.large"),
+				AvailabilityZone:       aws.String("test-zone-1a"),
+				OwnerId:                aws.String("210987654321"),
+				AvailableInstanceCount: aws.Int64(1),
+				State:                  aws.String("expired"),
+				Tags:                   []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("test-cr-3")}},
+			},
+		}})
+	})
+	It("Should update EC2NodeClass status for Capacity Reservations", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.CapacityReservations).To(Equal([]v1beta1.CapacityReservation{
+			{
+				ID:                     "cr-test1",
+				InstanceType:           "m5.large",
+				Zone:                   "test-zone-1a",
+				OwnerID:                "012345678901",
+				AvailableInstanceCount: 2,
+				ReservationType:        v1beta1.CapacityReservationTypeDefault,
+			},
+			{
+				ID:                     "cr-test2",
+				InstanceType:           "m5.large",
+				Zone:                   "test-zone-1b",
+				OwnerID:                "012345678901",
+				AvailableInstanceCount: 5,
+				ReservationType:        v1beta1.CapacityReservationTypeDefault,
+			},
+		}))
+	})
+	It("Should resolve a valid selector for Capacity Reservations by id", func() {
+		nodeClass.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+			{
+				ID: "cr-test2",
+			},
+		}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.CapacityReservations).To(Equal([]v1beta1.CapacityReservation{
+			{
+				ID:                     "cr-test2",
+				InstanceType:           "m5.large",
+				Zone:                   "test-zone-1b",
+				OwnerID:                "012345678901",
+				AvailableInstanceCount: 5,
+				ReservationType:        v1beta1.CapacityReservationTypeDefault,
+			},
+		}))
+	})
+	It("Should resolve a valid selector for Capacity Reservations by tags and owner", func() {
+		nodeClass.Spec.CapacityReservationSelectorTerms = []v1beta1.CapacityReservationSelectorTerm{
+			{
+				Tags:    map[string]string{"Name": "test-cr-1"},
+				OwnerID: "012345678901",
+			},
+		}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.CapacityReservations).To(Equal([]v1beta1.CapacityReservation{
+			{
+				ID:                     "cr-test1",
+				InstanceType:           "m5.large",
+				Zone:                   "test-zone-1a",
+				OwnerID:                "012345678901",
+				AvailableInstanceCount: 2,
+				ReservationType:        v1beta1.CapacityReservationTypeDefault,
+			},
+		}))
+	})
+	It("Should clear Capacity Reservations from status when the selector terms are removed", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.CapacityReservations).To(HaveLen(2))
+
+		nodeClass.Spec.CapacityReservationSelectorTerms = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.CapacityReservations).To(BeEmpty())
+	})
+})
@@ -19,6 +19,7 @@ import (
 
 	"go.uber.org/multierr"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/clock"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -33,9 +34,13 @@ import (
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/placementgroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/snapshot"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 )
 
@@ -46,23 +51,32 @@ type nodeClassStatusReconciler interface {
 type Controller struct {
 	kubeClient client.Client
 
-	ami             *AMI
-	instanceprofile *InstanceProfile
-	subnet          *Subnet
-	securitygroup   *SecurityGroup
-	readiness       *Readiness //TODO : Remove this when we have sub status conditions
+	ami                 *AMI
+	instanceprofile     *InstanceProfile
+	subnet              *Subnet
+	podsubnet           *PodSubnet
+	securitygroup       *SecurityGroup
+	capacityreservation *CapacityReservation
+	placementgroup      *PlacementGroup
+	snapshot            *Snapshot
+	readiness           *Readiness
 }
 
-func NewController(kubeClient client.Client, subnetProvider subnet.Provider, securityGroupProvider securitygroup.Provider,
-	amiProvider amifamily.Provider, instanceProfileProvider instanceprofile.Provider, launchTemplateProvider launchtemplate.Provider) *Controller {
+func NewController(kubeClient client.Client, clk clock.Clock, subnetProvider subnet.Provider, securityGroupProvider securitygroup.Provider,
+	capacityReservationProvider capacityreservation.Provider, placementGroupProvider placementgroup.Provider, snapshotProvider snapshot.Provider, amiProvider amifamily.Provider,
+	instanceProfileProvider instanceprofile.Provider, instanceProvider instance.Provider, launchTemplateProvider launchtemplate.Provider) *Controller {
 	return &Controller{
 		kubeClient: kubeClient,
 
-		ami:             &AMI{amiProvider: amiProvider},
-		subnet:          &Subnet{subnetProvider: subnetProvider},
-		securitygroup:   &SecurityGroup{securityGroupProvider: securityGroupProvider},
-		instanceprofile: &InstanceProfile{instanceProfileProvider: instanceProfileProvider},
-		readiness:       &Readiness{launchTemplateProvider: launchTemplateProvider},
+		ami:                 &AMI{kubeClient: kubeClient, amiProvider: amiProvider, clk: clk},
+		subnet:              &Subnet{subnetProvider: subnetProvider},
+		podsubnet:           &PodSubnet{subnetProvider: subnetProvider},
+		securitygroup:       &SecurityGroup{securityGroupProvider: securityGroupProvider},
+		capacityreservation: &CapacityReservation{capacityReservationProvider: capacityReservationProvider},
+		placementgroup:      &PlacementGroup{placementGroupProvider: placementGroupProvider},
+		snapshot:            &Snapshot{snapshotProvider: snapshotProvider},
+		instanceprofile:     &InstanceProfile{instanceProfileProvider: instanceProfileProvider, instanceProvider: instanceProvider},
+		readiness:           &Readiness{launchTemplateProvider: launchTemplateProvider},
 	}
 }
 
@@ -83,7 +97,11 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeCl
 	for _, reconciler := range []nodeClassStatusReconciler{
 		c.ami,
 		c.subnet,
+		c.podsubnet,
 		c.securitygroup,
+		c.capacityreservation,
+		c.placementgroup,
+		c.snapshot,
 		c.instanceprofile,
 		c.readiness,
 	} {
@@ -91,6 +109,7 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeCl
 		errs = multierr.Append(errs, err)
 		results = append(results, res)
 	}
+	recordConditions(nodeClass)
 
 	if !equality.Semantic.DeepEqual(stored, nodeClass) {
 		if err := c.kubeClient.Status().Patch(ctx, nodeClass, client.MergeFrom(stored)); err != nil {
@@ -17,28 +17,88 @@ package status
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 )
 
 type InstanceProfile struct {
 	instanceProfileProvider instanceprofile.Provider
+	instanceProvider        instance.Provider
+	// cleanedUp contains the UIDs of the EC2NodeClasses whose instance profiles for previous roles have all been
+	// deleted. Unused instance profiles are only looked for after the role changes, or once after startup in case
+	// Karpenter restarted before they could be deleted.
+	cleanedUp sync.Map
 }
 
 func (ip *InstanceProfile) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
 	if nodeClass.Spec.Role != "" {
 		name, err := ip.instanceProfileProvider.Create(ctx, nodeClass)
 		if err != nil {
+			nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeInstanceProfileReady, "InstanceProfileCreationFailed", "Failed to resolve instance profile")
 			return reconcile.Result{}, fmt.Errorf("creating instance profile, %w", err)
 		}
+		previous := nodeClass.Status.InstanceProfile
 		nodeClass.Status.InstanceProfile = name
-	} else {
-		nodeClass.Status.InstanceProfile = lo.FromPtr(nodeClass.Spec.InstanceProfile)
+		nodeClass.Status.ObservedGenerations.InstanceProfile = nodeClass.Generation
+		nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypeInstanceProfileReady)
+		// Launches that were started before the role changed may still be using the previous instance profile, so we
+		// wait before cleaning it up to give their instances time to become visible
+		if previous != "" && previous != name {
+			ip.cleanedUp.Delete(nodeClass.UID)
+			return reconcile.Result{RequeueAfter: time.Minute}, nil
+		}
+		if _, ok := ip.cleanedUp.Load(nodeClass.UID); ok {
+			return reconcile.Result{}, nil
+		}
+		cleanedUp, err := ip.deleteUnusedInstanceProfiles(ctx, nodeClass)
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("deleting unused instance profiles, %w", err)
+		}
+		if cleanedUp {
+			ip.cleanedUp.Store(nodeClass.UID, struct{}{})
+		}
+		return reconcile.Result{}, nil
 	}
-
+	nodeClass.Status.InstanceProfile = lo.FromPtr(nodeClass.Spec.InstanceProfile)
+	nodeClass.Status.ObservedGenerations.InstanceProfile = nodeClass.Generation
+	nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypeInstanceProfileReady)
 	return reconcile.Result{}, nil
 }
+
+// deleteUnusedInstanceProfiles deletes the instance profiles that were created for previous roles of the EC2NodeClass
+// once no instances are using them. Instances that were launched with them are drifted, so they're eventually unused.
+// It returns true once every instance profile for a previous role has been deleted.
+func (ip *InstanceProfile) deleteUnusedInstanceProfiles(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (bool, error) {
+	profileNames, err := ip.instanceProfileProvider.List(ctx, nodeClass)
+	if err != nil {
+		return false, err
+	}
+	profileNames = lo.Without(profileNames, nodeClass.Status.InstanceProfile)
+	if len(profileNames) == 0 {
+		return true, nil
+	}
+	instances, err := ip.instanceProvider.List(ctx)
+	if err != nil {
+		return false, fmt.Errorf("listing instances, %w", err)
+	}
+	inUse := sets.New(lo.Map(instances, func(i *instance.Instance, _ int) string { return i.InstanceProfile })...)
+	for _, profileName := range profileNames {
+		if inUse.Has(profileName) {
+			continue
+		}
+		if err := ip.instanceProfileProvider.DeleteInstanceProfile(ctx, profileName); err != nil {
+			return false, err
+		}
+		log.FromContext(ctx).WithValues("instance-profile", profileName).V(1).Info("deleted unused instance profile")
+	}
+	return !lo.SomeBy(profileNames, inUse.Has), nil
+}
@@ -15,10 +15,16 @@ limitations under the License.
 package status_test
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/samber/lo"
 
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 
@@ -63,7 +69,7 @@ var _ = Describe("NodeClass InstanceProfile Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.InstanceProfile).To(Equal(profileName))
 	})
-	It("should update the role for the instance profile when the wrong role exists", func() {
+	It("should create an instance profile for the role when the instance profile exists with a different role", func() {
 		awsEnv.IAMAPI.InstanceProfiles = map[string]*iam.InstanceProfile{
 			profileName: {
 				InstanceProfileId:   aws.String(fake.InstanceProfileID()),
@@ -80,12 +86,114 @@ var _ = Describe("NodeClass InstanceProfile Status Controller", func() {
 		ExpectApplied(ctx, env.Client, nodeClass)
 		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
 
-		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(1))
-		Expect(awsEnv.IAMAPI.InstanceProfiles[profileName].Roles).To(HaveLen(1))
-		Expect(*awsEnv.IAMAPI.InstanceProfiles[profileName].Roles[0].RoleName).To(Equal("test-role"))
+		roleProfileName := nodeClass.RoleInstanceProfileName(options.FromContext(ctx).ClusterName, fake.DefaultRegion)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).ToNot(HaveKey(profileName))
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey(roleProfileName))
+		Expect(awsEnv.IAMAPI.InstanceProfiles[roleProfileName].Roles).To(HaveLen(1))
+		Expect(*awsEnv.IAMAPI.InstanceProfiles[roleProfileName].Roles[0].RoleName).To(Equal("test-role"))
+		Expect(*awsEnv.IAMAPI.InstanceProfiles[roleProfileName].Path).To(Equal(nodeClass.InstanceProfilePath(options.FromContext(ctx).ClusterName)))
 
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
-		Expect(nodeClass.Status.InstanceProfile).To(Equal(profileName))
+		Expect(nodeClass.Status.InstanceProfile).To(Equal(roleProfileName))
+	})
+	Context("Role Changes", func() {
+		var instance *ec2.Instance
+		BeforeEach(func() {
+			nodeClass.Spec.Role = "test-role"
+			instance = &ec2.Instance{
+				InstanceId: aws.String(fake.InstanceID()),
+				State: &ec2.InstanceState{
+					Name: aws.String(ec2.InstanceStateNameRunning),
+				},
+				Tags: []*ec2.Tag{
+					{
+						Key:   aws.String(fmt.Sprintf("kubernetes.io/cluster/%s", options.FromContext(ctx).ClusterName)),
+						Value: aws.String("owned"),
+					},
+					{
+						Key:   aws.String(corev1beta1.NodePoolLabelKey),
+						Value: aws.String("default"),
+					},
+					{
+						Key:   aws.String(v1beta1.LabelNodeClass),
+						Value: aws.String(nodeClass.Name),
+					},
+				},
+				Placement: &ec2.Placement{
+					AvailabilityZone: aws.String("test-zone-1a"),
+				},
+				IamInstanceProfile: &ec2.IamInstanceProfile{
+					Arn: aws.String(fmt.Sprintf("arn:aws:iam::123456789012:instance-profile/%s", profileName)),
+				},
+			}
+		})
+		It("should create a new instance profile when the role changes", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.InstanceProfile).To(Equal(profileName))
+
+			nodeClass.Spec.Role = "test-role-2"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+
+			roleProfileName := nodeClass.RoleInstanceProfileName(options.FromContext(ctx).ClusterName, fake.DefaultRegion)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(2))
+			Expect(*awsEnv.IAMAPI.InstanceProfiles[profileName].Roles[0].RoleName).To(Equal("test-role"))
+			Expect(*awsEnv.IAMAPI.InstanceProfiles[roleProfileName].Roles[0].RoleName).To(Equal("test-role-2"))
+
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			Expect(nodeClass.Status.InstanceProfile).To(Equal(roleProfileName))
+		})
+		It("should not delete the previous instance profile while instances are using it", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			awsEnv.EC2API.Instances.Store(aws.StringValue(instance.InstanceId), instance)
+
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			nodeClass.Spec.Role = "test-role-2"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(2))
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey(profileName))
+		})
+		It("should delete the previous instance profile once no instances are using it", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			awsEnv.EC2API.Instances.Store(aws.StringValue(instance.InstanceId), instance)
+
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			nodeClass.Spec.Role = "test-role-2"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(2))
+
+			awsEnv.EC2API.Instances.Delete(aws.StringValue(instance.InstanceId))
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			roleProfileName := nodeClass.RoleInstanceProfileName(options.FromContext(ctx).ClusterName, fake.DefaultRegion)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(1))
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveKey(roleProfileName))
+		})
+		It("should only look for unused instance profiles until the previous instance profiles are deleted", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			awsEnv.EC2API.Instances.Store(aws.StringValue(instance.InstanceId), instance)
+
+			nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+			nodeClass.Spec.Role = "test-role-2"
+			ExpectApplied(ctx, env.Client, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			awsEnv.EC2API.Instances.Delete(aws.StringValue(instance.InstanceId))
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(1))
+
+			// the instance profile for the current role is cached, so IAM is only called to look for unused instance profiles
+			calls := awsEnv.IAMAPI.GetInstanceProfileBehavior.Calls()
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+			Expect(awsEnv.IAMAPI.GetInstanceProfileBehavior.Calls()).To(Equal(calls))
+		})
 	})
 	It("should not call CreateInstanceProfile or AddRoleToInstanceProfile when instance profile exists with correct role", func() {
 		awsEnv.IAMAPI.InstanceProfiles = map[string]*iam.InstanceProfile{
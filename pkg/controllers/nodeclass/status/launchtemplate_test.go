@@ -16,6 +16,7 @@ package status_test
 
 import (
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/awslabs/operatorpkg/status"
 	"github.com/samber/lo"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
@@ -84,4 +85,31 @@ var _ = Describe("NodeClass Launch Template CIDR Resolution Controller", func()
 		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
 		Expect(lo.FromPtr(awsEnv.LaunchTemplateProvider.ClusterCIDR.Load())).To(Equal("2001:db8::/64"))
 	})
+	It("should set ClusterCIDRReady to false when the cluster CIDR can't be detected", func() {
+		awsEnv.EKSAPI.DescribeClusterBehavior.Output.Set(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				KubernetesNetworkConfig: &eks.KubernetesNetworkConfigResponse{},
+			},
+		})
+		nodeClass.Spec.AMIFamily = lo.ToPtr(v1beta1.AMIFamilyAL2023)
+		ExpectApplied(ctx, env.Client, nodeClass)
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeClusterCIDRReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeClusterCIDRReady).Message).To(Equal("Failed to detect the cluster CIDR"))
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+
+		awsEnv.EKSAPI.DescribeClusterBehavior.Reset()
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeClusterCIDRReady).IsTrue()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
+	})
+	It("should set ClusterCIDRReady to true for non-AL2023 NodeClasses", func() {
+		nodeClass.Spec.AMIFamily = lo.ToPtr(v1beta1.AMIFamilyAL2)
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeClusterCIDRReady).IsTrue()).To(BeTrue())
+	})
 })
@@ -0,0 +1,74 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/karpenter/pkg/metrics"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+const (
+	nodeClassSubsystem   = "ec2nodeclass"
+	nodeClassLabel       = "nodeclass"
+	conditionTypeLabel   = "type"
+	conditionStatusLabel = "status"
+)
+
+var (
+	conditionStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: nodeClassSubsystem,
+			Name:      "status_condition",
+			Help:      "Current status of each EC2NodeClass condition. The value is 1 for the condition's current status and 0 for its other statuses. Labeled by EC2NodeClass, condition type, and status.",
+		},
+		[]string{
+			nodeClassLabel,
+			conditionTypeLabel,
+			conditionStatusLabel,
+		},
+	)
+)
+
+func init() {
+	crmetrics.Registry.MustRegister(conditionStatus)
+}
+
+// recordConditions updates the status condition metrics of the EC2NodeClass. The metrics of a deleted EC2NodeClass
+// are removed, as are the metrics of the AMIsDeprecated condition once it's been cleared.
+func recordConditions(nodeClass *v1beta1.EC2NodeClass) {
+	if !nodeClass.DeletionTimestamp.IsZero() {
+		conditionStatus.DeletePartialMatch(prometheus.Labels{nodeClassLabel: nodeClass.Name})
+		return
+	}
+	if nodeClass.StatusConditions().Get(v1beta1.ConditionTypeAMIsDeprecated) == nil {
+		conditionStatus.DeletePartialMatch(prometheus.Labels{nodeClassLabel: nodeClass.Name, conditionTypeLabel: v1beta1.ConditionTypeAMIsDeprecated})
+	}
+	for _, condition := range nodeClass.GetConditions() {
+		for _, s := range []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown} {
+			conditionStatus.With(prometheus.Labels{
+				nodeClassLabel:       nodeClass.Name,
+				conditionTypeLabel:   condition.Type,
+				conditionStatusLabel: string(s),
+			}).Set(lo.Ternary[float64](condition.Status == s, 1, 0))
+		}
+	}
+}
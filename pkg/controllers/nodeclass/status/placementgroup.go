@@ -0,0 +1,68 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/placementgroup"
+)
+
+type PlacementGroup struct {
+	placementGroupProvider placementgroup.Provider
+}
+
+func (pg *PlacementGroup) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
+	placementGroup, err := pg.placementGroupProvider.Get(ctx, nodeClass)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("getting placement group, %w", err)
+	}
+	if placementGroup == nil {
+		nodeClass.Status.PlacementGroup = nil
+		nodeClass.Status.ObservedGenerations.PlacementGroup = nodeClass.Generation
+		if nodeClass.Spec.PlacementGroupSelector != nil {
+			nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypePlacementGroupReady, "PlacementGroupNotFound", "Failed to resolve placement group")
+			return reconcile.Result{}, nil
+		}
+		nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypePlacementGroupReady)
+		return reconcile.Result{}, nil
+	}
+	zone, err := pg.placementGroupProvider.GetZone(ctx, placementGroup)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("getting placement group zone, %w", err)
+	}
+	nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{
+		ID:             aws.StringValue(placementGroup.GroupId),
+		Name:           aws.StringValue(placementGroup.GroupName),
+		Strategy:       aws.StringValue(placementGroup.Strategy),
+		PartitionCount: aws.Int64Value(placementGroup.PartitionCount),
+		Zone:           zone,
+	}
+	nodeClass.Status.ObservedGenerations.PlacementGroup = nodeClass.Generation
+	nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypePlacementGroupReady)
+	// The zone of a cluster placement group is fixed once the first instance launches, so we check for it more
+	// frequently until it's known
+	if aws.StringValue(placementGroup.Strategy) == ec2.PlacementStrategyCluster && zone == "" {
+		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
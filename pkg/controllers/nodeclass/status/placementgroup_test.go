@@ -0,0 +1,165 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/awslabs/operatorpkg/status"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Placement Group Status Controller", func() {
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Spec: v1beta1.EC2NodeClassSpec{
+				SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				PlacementGroupSelector: &v1beta1.PlacementGroupSelectorTerm{
+					Name: "test-pg-partition",
+				},
+			},
+		})
+		awsEnv.EC2API.DescribePlacementGroupsOutput.Set(&ec2.DescribePlacementGroupsOutput{PlacementGroups: []*ec2.PlacementGroup{
+			{
+				GroupId:        aws.String("pg-test1"),
+				GroupName:      aws.String("test-pg-partition"),
+				Strategy:       aws.String(ec2.PlacementStrategyPartition),
+				PartitionCount: aws.Int64(3),
+				State:          aws.String(ec2.PlacementGroupStateAvailable),
+				Tags:           []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("bar")}},
+			},
+			{
+				GroupId:   aws.String("pg-test2"),
+				GroupName: aws.String("test-pg-cluster"),
+				Strategy:  aws.String(ec2.PlacementStrategyCluster),
+				State:     aws.String(ec2.PlacementGroupStateAvailable),
+				Tags:      []*ec2.Tag{{Key: aws.String("foo"), Value: aws.String("baz")}},
+			},
+			{
+				GroupId:   aws.String("pg-test3"),
+				GroupName: aws.String("test-pg-deleted"),
+				Strategy:  aws.String(ec2.PlacementStrategySpread),
+				State:     aws.String(ec2.PlacementGroupStateDeleted),
+			},
+		}})
+	})
+	It("Should update EC2NodeClass status for a Placement Group selected by name", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).To(Equal(&v1beta1.PlacementGroup{
+			ID:             "pg-test1",
+			Name:           "test-pg-partition",
+			Strategy:       ec2.PlacementStrategyPartition,
+			PartitionCount: 3,
+		}))
+	})
+	It("Should resolve a Placement Group selected by id", func() {
+		nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{ID: "pg-test2"}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).To(Equal(&v1beta1.PlacementGroup{
+			ID:       "pg-test2",
+			Name:     "test-pg-cluster",
+			Strategy: ec2.PlacementStrategyCluster,
+		}))
+	})
+	It("Should resolve a Placement Group selected by tags", func() {
+		nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Tags: map[string]string{"foo": "baz"}}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).ToNot(BeNil())
+		Expect(nodeClass.Status.PlacementGroup.ID).To(Equal("pg-test2"))
+	})
+	It("Should resolve the zone of a cluster Placement Group from its running instances", func() {
+		nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg-cluster"}
+		awsEnv.EC2API.Instances.Store("i-test", &ec2.Instance{
+			InstanceId: aws.String("i-test"),
+			State:      &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+			Placement: &ec2.Placement{
+				AvailabilityZone: aws.String("test-zone-1b"),
+				GroupName:        aws.String("test-pg-cluster"),
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).ToNot(BeNil())
+		Expect(nodeClass.Status.PlacementGroup.Zone).To(Equal("test-zone-1b"))
+
+		// The zone is cached rather than described again on every reconcile
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		Expect(awsEnv.EC2API.DescribeInstancesBehavior.Calls()).To(Equal(1))
+	})
+	It("Should resolve the zone of a cluster Placement Group once an instance is launched into it", func() {
+		nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg-cluster"}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).ToNot(BeNil())
+		Expect(nodeClass.Status.PlacementGroup.Zone).To(BeEmpty())
+
+		awsEnv.EC2API.Instances.Store("i-test", &ec2.Instance{
+			InstanceId: aws.String("i-test"),
+			State:      &ec2.InstanceState{Name: aws.String(ec2.InstanceStateNameRunning)},
+			Placement: &ec2.Placement{
+				AvailabilityZone: aws.String("test-zone-1c"),
+				GroupName:        aws.String("test-pg-cluster"),
+			},
+		})
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup.Zone).To(Equal("test-zone-1c"))
+	})
+	It("Should not resolve a Placement Group that isn't available", func() {
+		nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg-deleted"}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).To(BeNil())
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypePlacementGroupReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypePlacementGroupReady).Message).To(Equal("Failed to resolve placement group"))
+	})
+	It("Should clear the Placement Group from status when the selector is removed", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).ToNot(BeNil())
+
+		nodeClass.Spec.PlacementGroupSelector = nil
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.PlacementGroup).To(BeNil())
+	})
+})
@@ -0,0 +1,53 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
+)
+
+type PodSubnet struct {
+	subnetProvider subnet.Provider
+}
+
+func (s *PodSubnet) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
+	if len(nodeClass.Spec.PodSubnetSelectorTerms) == 0 {
+		nodeClass.Status.PodSubnets = nil
+		nodeClass.Status.ObservedGenerations.PodSubnets = nodeClass.Generation
+		nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypePodSubnetsReady)
+		return reconcile.Result{}, nil
+	}
+	subnets, err := s.subnetProvider.ListPodSubnets(ctx, nodeClass)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("getting pod subnets, %w", err)
+	}
+	nodeClass.Status.ObservedGenerations.PodSubnets = nodeClass.Generation
+	if len(subnets) == 0 {
+		nodeClass.Status.PodSubnets = nil
+		nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypePodSubnetsReady, "PodSubnetsNotFound", "Failed to resolve pod subnets")
+		return reconcile.Result{}, nil
+	}
+	nodeClass.Status.PodSubnets = statusSubnets(subnets)
+	nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypePodSubnetsReady)
+	// Listing the pod subnets refreshes their available IP address counts, so we re-resolve them as often as node subnets
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
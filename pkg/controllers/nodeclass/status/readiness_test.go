@@ -51,9 +51,32 @@ var _ = Describe("NodeClass Status Condition Controller", func() {
 		ExpectApplied(ctx, env.Client, nodeClass)
 		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
-		Expect(nodeClass.Status.Conditions).To(HaveLen(1))
+		Expect(nodeClass.Status.Conditions).To(HaveLen(9))
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsTrue()).To(BeTrue())
 	})
+	It("should record the generation that the status was resolved for", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusObserved()).To(BeTrue())
+
+		nodeClass.Spec.Tags = map[string]string{"test-key": "test-value"}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusObserved()).To(BeFalse())
+
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.StatusObserved()).To(BeTrue())
+		Expect(nodeClass.Status.ObservedGenerations).To(Equal(v1beta1.ObservedGenerations{
+			Subnets:              nodeClass.Generation,
+			SecurityGroups:       nodeClass.Generation,
+			AMIs:                 nodeClass.Generation,
+			CapacityReservations: nodeClass.Generation,
+			PlacementGroup:       nodeClass.Generation,
+			InstanceProfile:      nodeClass.Generation,
+		}))
+	})
 	It("should update status condition as Not Ready", func() {
 		nodeClass.Spec.SecurityGroupSelectorTerms = []v1beta1.SecurityGroupSelectorTerm{
 			{
@@ -65,6 +88,38 @@ var _ = Describe("NodeClass Status Condition Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
-		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).Message).To(Equal("Failed to resolve security groups"))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSecurityGroupsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSecurityGroupsReady).Message).To(Equal("Failed to resolve security groups"))
+	})
+	It("should expose a metric for each status condition", func() {
+		nodeClass.Spec.SecurityGroupSelectorTerms = []v1beta1.SecurityGroupSelectorTerm{
+			{
+				Tags: map[string]string{"foo": "invalid"},
+			},
+		}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+
+		for conditionType, conditionStatus := range map[string]string{
+			v1beta1.ConditionTypeSubnetsReady:        "True",
+			v1beta1.ConditionTypeSecurityGroupsReady: "False",
+			status.ConditionReady:                    "False",
+		} {
+			metric, ok := FindMetricWithLabelValues("karpenter_ec2nodeclass_status_condition", map[string]string{
+				"nodeclass": nodeClass.Name,
+				"type":      conditionType,
+				"status":    conditionStatus,
+			})
+			Expect(ok).To(BeTrue())
+			Expect(metric.GetGauge().GetValue()).To(BeNumerically("==", 1))
+		}
+		metric, ok := FindMetricWithLabelValues("karpenter_ec2nodeclass_status_condition", map[string]string{
+			"nodeclass": nodeClass.Name,
+			"type":      v1beta1.ConditionTypeSecurityGroupsReady,
+			"status":    "True",
+		})
+		Expect(ok).To(BeTrue())
+		Expect(metric.GetGauge().GetValue()).To(BeNumerically("==", 0))
 	})
 })
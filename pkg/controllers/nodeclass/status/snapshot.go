@@ -0,0 +1,72 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/snapshot"
+)
+
+type Snapshot struct {
+	snapshotProvider snapshot.Provider
+}
+
+func (s *Snapshot) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (reconcile.Result, error) {
+	selectors := lo.FilterMap(nodeClass.Spec.BlockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping, _ int) (string, bool) {
+		return aws.StringValue(bdm.DeviceName), bdm.EBS != nil && len(bdm.EBS.SnapshotSelectorTerms) > 0
+	})
+	if len(selectors) == 0 {
+		nodeClass.Status.Snapshots = nil
+		nodeClass.Status.ObservedGenerations.Snapshots = nodeClass.Generation
+		nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypeSnapshotsReady)
+		return reconcile.Result{}, nil
+	}
+	snapshots, err := s.snapshotProvider.List(ctx, nodeClass)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("getting snapshots, %w", err)
+	}
+	nodeClass.Status.ObservedGenerations.Snapshots = nodeClass.Generation
+	nodeClass.Status.Snapshots = lo.MapToSlice(snapshots, func(deviceName string, snapshot *ec2.Snapshot) v1beta1.Snapshot {
+		return v1beta1.Snapshot{
+			DeviceName:   deviceName,
+			ID:           aws.StringValue(snapshot.SnapshotId),
+			CreationTime: lo.Ternary(snapshot.StartTime != nil, lo.ToPtr(metav1.NewTime(aws.TimeValue(snapshot.StartTime))), nil),
+		}
+	})
+	sort.Slice(nodeClass.Status.Snapshots, func(i, j int) bool {
+		return nodeClass.Status.Snapshots[i].DeviceName < nodeClass.Status.Snapshots[j].DeviceName
+	})
+	if missing := lo.Without(selectors, lo.Keys(snapshots)...); len(missing) > 0 {
+		sort.Strings(missing)
+		nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeSnapshotsReady, "SnapshotNotFound",
+			fmt.Sprintf("Failed to resolve snapshots for block device mappings (%s)", strings.Join(missing, ", ")))
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypeSnapshotsReady)
+	// New snapshots are published independently of the EC2NodeClass, so we re-resolve them periodically
+	return reconcile.Result{RequeueAfter: 5 * time.Minute}, nil
+}
@@ -0,0 +1,153 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status_test
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/awslabs/operatorpkg/status"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/fake"
+	"github.com/aws/karpenter-provider-aws/pkg/test"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("NodeClass Snapshot Status Controller", func() {
+	BeforeEach(func() {
+		nodeClass = test.EC2NodeClass(v1beta1.EC2NodeClass{
+			Spec: v1beta1.EC2NodeClassSpec{
+				SubnetSelectorTerms: []v1beta1.SubnetSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				SecurityGroupSelectorTerms: []v1beta1.SecurityGroupSelectorTerm{
+					{
+						Tags: map[string]string{"*": "*"},
+					},
+				},
+				BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
+					{
+						DeviceName: aws.String("/dev/xvdb"),
+						EBS: &v1beta1.BlockDevice{
+							SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{
+								{
+									Tags: map[string]string{"purpose": "image-cache"},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		awsEnv.EC2API.DescribeSnapshotsOutput.Set(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{
+			{
+				SnapshotId: aws.String("snap-test1"),
+				OwnerId:    aws.String(fake.DefaultAccount),
+				State:      aws.String(ec2.SnapshotStateCompleted),
+				StartTime:  aws.Time(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+				Tags:       []*ec2.Tag{{Key: aws.String("purpose"), Value: aws.String("image-cache")}},
+			},
+			{
+				SnapshotId: aws.String("snap-test2"),
+				OwnerId:    aws.String(fake.DefaultAccount),
+				State:      aws.String(ec2.SnapshotStateCompleted),
+				StartTime:  aws.Time(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+				Tags:       []*ec2.Tag{{Key: aws.String("purpose"), Value: aws.String("image-cache")}},
+			},
+			{
+				SnapshotId: aws.String("snap-test3"),
+				OwnerId:    aws.String(fake.DefaultAccount),
+				State:      aws.String(ec2.SnapshotStatePending),
+				StartTime:  aws.Time(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+				Tags:       []*ec2.Tag{{Key: aws.String("purpose"), Value: aws.String("image-cache")}},
+			},
+			{
+				SnapshotId: aws.String("snap-test4"),
+				OwnerId:    aws.String("210987654321"),
+				State:      aws.String(ec2.SnapshotStateCompleted),
+				StartTime:  aws.Time(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+				Tags:       []*ec2.Tag{{Key: aws.String("Name"), Value: aws.String("shared-cache")}},
+			},
+		}})
+	})
+	It("Should update EC2NodeClass status with the newest completed snapshot", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(HaveLen(1))
+		Expect(nodeClass.Status.Snapshots[0].DeviceName).To(Equal("/dev/xvdb"))
+		Expect(nodeClass.Status.Snapshots[0].ID).To(Equal("snap-test2"))
+		Expect(nodeClass.Status.Snapshots[0].CreationTime.Time.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSnapshotsReady).IsTrue()).To(BeTrue())
+	})
+	It("Should resolve a snapshot selected by name and owner", func() {
+		nodeClass.Spec.BlockDeviceMappings[0].EBS.SnapshotSelectorTerms = []v1beta1.SnapshotSelectorTerm{{Name: "shared-cache", Owner: "210987654321"}}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(HaveLen(1))
+		Expect(nodeClass.Status.Snapshots[0].ID).To(Equal("snap-test4"))
+	})
+	It("Should resolve the newest snapshot across terms", func() {
+		nodeClass.Spec.BlockDeviceMappings[0].EBS.SnapshotSelectorTerms = []v1beta1.SnapshotSelectorTerm{
+			{Name: "shared-cache"},
+			{Tags: map[string]string{"purpose": "image-cache"}, Owner: fake.DefaultAccount},
+		}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(HaveLen(1))
+		Expect(nodeClass.Status.Snapshots[0].ID).To(Equal("snap-test2"))
+	})
+	It("Should only select snapshots owned by the account when no owner is set", func() {
+		nodeClass.Spec.BlockDeviceMappings[0].EBS.SnapshotSelectorTerms = []v1beta1.SnapshotSelectorTerm{{Name: "shared-cache"}}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(BeEmpty())
+		Expect(awsEnv.EC2API.CalledWithDescribeSnapshotsInput.Len()).To(Equal(1))
+		Expect(aws.StringValueSlice(awsEnv.EC2API.CalledWithDescribeSnapshotsInput.Pop().OwnerIds)).To(ConsistOf("self"))
+	})
+	It("Should set SnapshotsReady to false when a block device mapping doesn't resolve a snapshot", func() {
+		nodeClass.Spec.BlockDeviceMappings[0].EBS.SnapshotSelectorTerms = []v1beta1.SnapshotSelectorTerm{{Name: "missing"}}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(BeEmpty())
+		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSnapshotsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSnapshotsReady).Message).To(Equal("Failed to resolve snapshots for block device mappings (/dev/xvdb)"))
+	})
+	It("Should clear the snapshots from status when the selector terms are removed", func() {
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(HaveLen(1))
+
+		nodeClass.Spec.BlockDeviceMappings[0].EBS = &v1beta1.BlockDevice{SnapshotID: aws.String("snap-test1")}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Snapshots).To(BeNil())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSnapshotsReady).IsTrue()).To(BeTrue())
+	})
+})
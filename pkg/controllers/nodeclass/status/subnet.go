@@ -20,6 +20,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/samber/lo"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -37,22 +38,45 @@ func (s *Subnet) Reconcile(ctx context.Context, nodeClass *v1beta1.EC2NodeClass)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("getting subnets, %w", err)
 	}
+	nodeClass.Status.ObservedGenerations.Subnets = nodeClass.Generation
 	if len(subnets) == 0 {
 		nodeClass.Status.Subnets = nil
+		nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeSubnetsReady, "SubnetsNotFound", "Failed to resolve subnets")
 		return reconcile.Result{}, nil
 	}
+	nodeClass.Status.Subnets = statusSubnets(subnets)
+	if len(nodeClass.LaunchSubnets()) == 0 {
+		if nodeClass.IPv6Only() {
+			nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeSubnetsReady, "IPv6OnlySubnetsNotFound", "Failed to resolve IPv6-only subnets")
+		} else {
+			nodeClass.StatusConditions().SetFalse(v1beta1.ConditionTypeSubnetsReady, "IPv6SubnetsNotFound", "Failed to resolve subnets with IPv6 CIDRs")
+		}
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+	nodeClass.StatusConditions().SetTrue(v1beta1.ConditionTypeSubnetsReady)
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+// statusSubnets orders subnets by their available IP address count, descending, and converts them to their status representation
+func statusSubnets(subnets []*ec2.Subnet) []v1beta1.Subnet {
 	sort.Slice(subnets, func(i, j int) bool {
 		if int(*subnets[i].AvailableIpAddressCount) != int(*subnets[j].AvailableIpAddressCount) {
 			return int(*subnets[i].AvailableIpAddressCount) > int(*subnets[j].AvailableIpAddressCount)
 		}
 		return *subnets[i].SubnetId < *subnets[j].SubnetId
 	})
-	nodeClass.Status.Subnets = lo.Map(subnets, func(ec2subnet *ec2.Subnet, _ int) v1beta1.Subnet {
+	return lo.Map(subnets, func(ec2subnet *ec2.Subnet, _ int) v1beta1.Subnet {
+		var ipv6CIDRs []string
+		for _, association := range ec2subnet.Ipv6CidrBlockAssociationSet {
+			if association.Ipv6CidrBlockState != nil && aws.StringValue(association.Ipv6CidrBlockState.State) == ec2.SubnetCidrBlockStateCodeAssociated {
+				ipv6CIDRs = append(ipv6CIDRs, aws.StringValue(association.Ipv6CidrBlock))
+			}
+		}
 		return v1beta1.Subnet{
-			ID:   *ec2subnet.SubnetId,
-			Zone: *ec2subnet.AvailabilityZone,
+			ID:         *ec2subnet.SubnetId,
+			Zone:       *ec2subnet.AvailabilityZone,
+			IPv6CIDRs:  ipv6CIDRs,
+			IPv6Native: aws.BoolValue(ec2subnet.Ipv6Native),
 		}
 	})
-
-	return reconcile.Result{RequeueAfter: time.Minute}, nil
 }
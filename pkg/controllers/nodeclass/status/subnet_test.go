@@ -96,6 +96,78 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 			},
 		}))
 	})
+	It("Should record the associated IPv6 CIDRs of the Subnets", func() {
+		awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+			{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(20), Ipv6CidrBlockAssociationSet: []*ec2.SubnetIpv6CidrBlockAssociation{
+				{Ipv6CidrBlock: aws.String("2600:1f14:c2e:100::/64"), Ipv6CidrBlockState: &ec2.SubnetCidrBlockState{State: aws.String(ec2.SubnetCidrBlockStateCodeAssociated)}},
+				{Ipv6CidrBlock: aws.String("2600:1f14:c2e:200::/64"), Ipv6CidrBlockState: &ec2.SubnetCidrBlockState{State: aws.String(ec2.SubnetCidrBlockStateCodeDisassociated)}},
+			}},
+			{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1b"), AvailableIpAddressCount: aws.Int64(10)},
+		}})
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Subnets).To(Equal([]v1beta1.Subnet{
+			{
+				ID:        "subnet-test1",
+				Zone:      "test-zone-1a",
+				IPv6CIDRs: []string{"2600:1f14:c2e:100::/64"},
+			},
+			{
+				ID:   "subnet-test2",
+				Zone: "test-zone-1b",
+			},
+		}))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).IsTrue()).To(BeTrue())
+	})
+	It("Should record whether the Subnets are IPv6-only", func() {
+		awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+			{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(20), Ipv6Native: aws.Bool(true), Ipv6CidrBlockAssociationSet: []*ec2.SubnetIpv6CidrBlockAssociation{
+				{Ipv6CidrBlock: aws.String("2600:1f14:c2e:100::/64"), Ipv6CidrBlockState: &ec2.SubnetCidrBlockState{State: aws.String(ec2.SubnetCidrBlockStateCodeAssociated)}},
+			}},
+			{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1b"), AvailableIpAddressCount: aws.Int64(10), Ipv6Native: aws.Bool(false)},
+		}})
+		nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Subnets).To(Equal([]v1beta1.Subnet{
+			{
+				ID:         "subnet-test1",
+				Zone:       "test-zone-1a",
+				IPv6CIDRs:  []string{"2600:1f14:c2e:100::/64"},
+				IPv6Native: true,
+			},
+			{
+				ID:   "subnet-test2",
+				Zone: "test-zone-1b",
+			},
+		}))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).IsTrue()).To(BeTrue())
+	})
+	It("Should not be ready when ipv6Only is set and no Subnets are IPv6-only", func() {
+		awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+			{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(20), Ipv6Native: aws.Bool(false), Ipv6CidrBlockAssociationSet: []*ec2.SubnetIpv6CidrBlockAssociation{
+				{Ipv6CidrBlock: aws.String("2600:1f14:c2e:100::/64"), Ipv6CidrBlockState: &ec2.SubnetCidrBlockState{State: aws.String(ec2.SubnetCidrBlockStateCodeAssociated)}},
+			}},
+		}})
+		nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Subnets).To(HaveLen(1))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).Reason).To(Equal("IPv6OnlySubnetsNotFound"))
+	})
+	It("Should not be ready when ipv6 prefixes are assigned and no Subnets have IPv6 CIDRs", func() {
+		nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6PrefixCount: aws.Int64(1)}
+		ExpectApplied(ctx, env.Client, nodeClass)
+		ExpectObjectReconciled(ctx, env.Client, statusController, nodeClass)
+		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
+		Expect(nodeClass.Status.Subnets).To(HaveLen(4))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).Message).To(Equal("Failed to resolve subnets with IPv6 CIDRs"))
+	})
 	It("Should resolve a valid selectors for Subnet by tags", func() {
 		nodeClass.Spec.SubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{
 			{
@@ -233,7 +305,8 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(BeNil())
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
-		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).Message).To(Equal("Failed to resolve subnets"))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).Message).To(Equal("Failed to resolve subnets"))
 	})
 	It("Should not resolve a invalid selectors for an updated subnet selector", func() {
 		ExpectApplied(ctx, env.Client, nodeClass)
@@ -268,6 +341,7 @@ var _ = Describe("NodeClass Subnet Status Controller", func() {
 		nodeClass = ExpectExists(ctx, env.Client, nodeClass)
 		Expect(nodeClass.Status.Subnets).To(BeNil())
 		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
-		Expect(nodeClass.StatusConditions().Get(status.ConditionReady).Message).To(Equal("Failed to resolve subnets"))
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).IsFalse()).To(BeTrue())
+		Expect(nodeClass.StatusConditions().Get(v1beta1.ConditionTypeSubnetsReady).Message).To(Equal("Failed to resolve subnets"))
 	})
 })
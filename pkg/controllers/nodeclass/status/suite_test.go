@@ -54,10 +54,15 @@ var _ = BeforeSuite(func() {
 
 	statusController = status.NewController(
 		env.Client,
+		awsEnv.Clock,
 		awsEnv.SubnetProvider,
 		awsEnv.SecurityGroupProvider,
+		awsEnv.CapacityReservationProvider,
+		awsEnv.PlacementGroupProvider,
+		awsEnv.SnapshotProvider,
 		awsEnv.AMIProvider,
 		awsEnv.InstanceProfileProvider,
+		awsEnv.InstanceProvider,
 		awsEnv.LaunchTemplateProvider,
 	)
 })
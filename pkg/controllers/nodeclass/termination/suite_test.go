@@ -192,6 +192,36 @@ var _ = Describe("NodeClass Termination", func() {
 		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(0))
 		ExpectNotFound(ctx, env.Client, nodeClass)
 	})
+	It("should succeed to delete the instance profiles created for previous roles with no NodeClaims", func() {
+		controllerutil.AddFinalizer(nodeClass, v1beta1.TerminationFinalizer)
+		ExpectApplied(ctx, env.Client, nodeClass)
+		awsEnv.IAMAPI.InstanceProfiles = map[string]*iam.InstanceProfile{
+			profileName: {
+				InstanceProfileName: aws.String(profileName),
+				Roles: []*iam.Role{
+					{
+						RoleId:   aws.String(fake.RoleID()),
+						RoleName: aws.String("previous-role"),
+					},
+				},
+			},
+			"test-profile-2": {
+				InstanceProfileName: aws.String("test-profile-2"),
+				Path:                aws.String(nodeClass.InstanceProfilePath(options.FromContext(ctx).ClusterName)),
+				Roles: []*iam.Role{
+					{
+						RoleId:   aws.String(fake.RoleID()),
+						RoleName: aws.String(nodeClass.Spec.Role),
+					},
+				},
+			},
+		}
+
+		Expect(env.Client.Delete(ctx, nodeClass)).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, terminationController, nodeClass)
+		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(0))
+		ExpectNotFound(ctx, env.Client, nodeClass)
+	})
 	It("should succeed to delete the NodeClass when the instance profile doesn't exist", func() {
 		Expect(awsEnv.IAMAPI.InstanceProfiles).To(HaveLen(0))
 		controllerutil.AddFinalizer(nodeClass, v1beta1.TerminationFinalizer)
@@ -129,6 +129,52 @@ var _ = Describe("Pricing", func() {
 		Expect(ok).To(BeTrue())
 		Expect(price).To(BeNumerically("==", 1.23))
 	})
+	It("should update surplus credit pricing with response from the pricing API", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPrice("c98.large", 1.20),
+				fake.NewSurplusCreditPrice("USE2-CPUCredits:t3", 0.075),
+				fake.NewSurplusCreditPrice("USE2-CPUCredits:t4g", 0.06),
+			},
+		})
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		Expect(awsEnv.PricingProvider.SurplusCreditPrice("t3.large")).To(BeNumerically("==", 0.075))
+		Expect(awsEnv.PricingProvider.SurplusCreditPrice("t4g.small")).To(BeNumerically("==", 0.06))
+		// families without published surplus credit pricing fall back to the default rate
+		Expect(awsEnv.PricingProvider.SurplusCreditPrice("t2.micro")).To(BeNumerically("==", 0.05))
+	})
+	It("should return default surplus credit pricing if pricing API fails", func() {
+		awsEnv.PricingAPI.NextError.Set(fmt.Errorf("failed"))
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+		Expect(awsEnv.PricingProvider.SurplusCreditPrice("t3.large")).To(BeNumerically("==", 0.05))
+		Expect(awsEnv.PricingProvider.SurplusCreditPrice("t4g.small")).To(BeNumerically("==", 0.04))
+	})
+	It("should return shared on-demand pricing for dedicated tenancy if pricing API fails", func() {
+		awsEnv.PricingAPI.NextError.Set(fmt.Errorf("failed"))
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+		sharedPrice, ok := awsEnv.PricingProvider.OnDemandPrice("c5.large")
+		Expect(ok).To(BeTrue())
+		price, ok := awsEnv.PricingProvider.DedicatedOnDemandPrice("c5.large")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", sharedPrice))
+	})
+	It("should update dedicated on-demand pricing with response from the pricing API", func() {
+		awsEnv.PricingAPI.GetProductsOutput.Set(&awspricing.GetProductsOutput{
+			PriceList: []aws.JSONValue{
+				fake.NewOnDemandPrice("c98.large", 1.20),
+				fake.NewOnDemandPrice("c99.large", 1.23),
+			},
+		})
+		ExpectReconcileFailed(ctx, controller, types.NamespacedName{})
+
+		price, ok := awsEnv.PricingProvider.DedicatedOnDemandPrice("c98.large")
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("==", 1.20))
+
+		_, ok = awsEnv.PricingProvider.DedicatedOnDemandPrice("c97.large")
+		Expect(ok).To(BeFalse())
+	})
 	It("should update spot pricing with response from the pricing API", func() {
 		now := time.Now()
 		awsEnv.EC2API.DescribeSpotPriceHistoryOutput.Set(&ec2.DescribeSpotPriceHistoryOutput{
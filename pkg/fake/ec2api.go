@@ -50,6 +50,9 @@ type EC2Behavior struct {
 	DescribeLaunchTemplatesOutput       AtomicPtr[ec2.DescribeLaunchTemplatesOutput]
 	DescribeSubnetsOutput               AtomicPtr[ec2.DescribeSubnetsOutput]
 	DescribeSecurityGroupsOutput        AtomicPtr[ec2.DescribeSecurityGroupsOutput]
+	DescribeCapacityReservationsOutput  AtomicPtr[ec2.DescribeCapacityReservationsOutput]
+	DescribePlacementGroupsOutput       AtomicPtr[ec2.DescribePlacementGroupsOutput]
+	DescribeSnapshotsOutput             AtomicPtr[ec2.DescribeSnapshotsOutput]
 	DescribeInstanceTypesOutput         AtomicPtr[ec2.DescribeInstanceTypesOutput]
 	DescribeInstanceTypeOfferingsOutput AtomicPtr[ec2.DescribeInstanceTypeOfferingsOutput]
 	DescribeAvailabilityZonesOutput     AtomicPtr[ec2.DescribeAvailabilityZonesOutput]
@@ -59,10 +62,13 @@ type EC2Behavior struct {
 	TerminateInstancesBehavior          MockedFunction[ec2.TerminateInstancesInput, ec2.TerminateInstancesOutput]
 	DescribeInstancesBehavior           MockedFunction[ec2.DescribeInstancesInput, ec2.DescribeInstancesOutput]
 	CreateTagsBehavior                  MockedFunction[ec2.CreateTagsInput, ec2.CreateTagsOutput]
+	DeleteTagsBehavior                  MockedFunction[ec2.DeleteTagsInput, ec2.DeleteTagsOutput]
 	CalledWithCreateLaunchTemplateInput AtomicPtrSlice[ec2.CreateLaunchTemplateInput]
 	CalledWithDescribeImagesInput       AtomicPtrSlice[ec2.DescribeImagesInput]
+	CalledWithDescribeSnapshotsInput    AtomicPtrSlice[ec2.DescribeSnapshotsInput]
 	Instances                           sync.Map
 	LaunchTemplates                     sync.Map
+	ResourceTags                        sync.Map
 	InsufficientCapacityPools           atomic.Slice[CapacityPool]
 	NextError                           AtomicError
 }
@@ -86,12 +92,18 @@ func (e *EC2API) Reset() {
 	e.DescribeLaunchTemplatesOutput.Reset()
 	e.DescribeSubnetsOutput.Reset()
 	e.DescribeSecurityGroupsOutput.Reset()
+	e.DescribeCapacityReservationsOutput.Reset()
+	e.DescribePlacementGroupsOutput.Reset()
+	e.DescribeSnapshotsOutput.Reset()
+	e.CalledWithDescribeSnapshotsInput.Reset()
 	e.DescribeInstanceTypesOutput.Reset()
 	e.DescribeInstanceTypeOfferingsOutput.Reset()
 	e.DescribeAvailabilityZonesOutput.Reset()
 	e.CreateFleetBehavior.Reset()
 	e.TerminateInstancesBehavior.Reset()
 	e.DescribeInstancesBehavior.Reset()
+	e.CreateTagsBehavior.Reset()
+	e.DeleteTagsBehavior.Reset()
 	e.CalledWithCreateLaunchTemplateInput.Reset()
 	e.CalledWithDescribeImagesInput.Reset()
 	e.DescribeSpotPriceHistoryInput.Reset()
@@ -104,6 +116,10 @@ func (e *EC2API) Reset() {
 		e.LaunchTemplates.Delete(k)
 		return true
 	})
+	e.ResourceTags.Range(func(k, v any) bool {
+		e.ResourceTags.Delete(k)
+		return true
+	})
 	e.InsufficientCapacityPools.Reset()
 	e.NextError.Reset()
 }
@@ -127,7 +143,7 @@ func (e *EC2API) CreateFleetWithContext(_ context.Context, input *ec2.CreateFlee
 			for _, override := range ltc.Overrides {
 				skipInstance := false
 				e.InsufficientCapacityPools.Range(func(pool CapacityPool) bool {
-					if pool.InstanceType == aws.StringValue(override.InstanceType) &&
+					if pool.InstanceType == aws.StringValue(overrideInstanceType(override)) &&
 						pool.Zone == aws.StringValue(override.AvailabilityZone) &&
 						pool.CapacityType == aws.StringValue(input.TargetCapacitySpecification.DefaultTargetCapacityType) {
 						skippedPools = append(skippedPools, pool)
@@ -152,7 +168,7 @@ func (e *EC2API) CreateFleetWithContext(_ context.Context, input *ec2.CreateFlee
 						InstanceId:            aws.String(test.RandomName()),
 						Placement:             &ec2.Placement{AvailabilityZone: input.LaunchTemplateConfigs[0].Overrides[0].AvailabilityZone},
 						PrivateDnsName:        aws.String(randomdata.IpV4Address()),
-						InstanceType:          input.LaunchTemplateConfigs[0].Overrides[0].InstanceType,
+						InstanceType:          overrideInstanceType(input.LaunchTemplateConfigs[0].Overrides[0]),
 						SpotInstanceRequestId: spotInstanceRequestID,
 						State: &ec2.InstanceState{
 							Name: &instanceState,
@@ -169,13 +185,16 @@ func (e *EC2API) CreateFleetWithContext(_ context.Context, input *ec2.CreateFlee
 		result := &ec2.CreateFleetOutput{Instances: []*ec2.CreateFleetInstance{
 			{
 				InstanceIds:  instanceIds,
-				InstanceType: input.LaunchTemplateConfigs[0].Overrides[0].InstanceType,
+				InstanceType: overrideInstanceType(input.LaunchTemplateConfigs[0].Overrides[0]),
 				Lifecycle:    input.TargetCapacitySpecification.DefaultTargetCapacityType,
 				LaunchTemplateAndOverrides: &ec2.LaunchTemplateAndOverridesResponse{
+					LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecification{
+						LaunchTemplateName: input.LaunchTemplateConfigs[0].LaunchTemplateSpecification.LaunchTemplateName,
+					},
 					Overrides: &ec2.FleetLaunchTemplateOverrides{
 						SubnetId:         input.LaunchTemplateConfigs[0].Overrides[0].SubnetId,
 						ImageId:          input.LaunchTemplateConfigs[0].Overrides[0].ImageId,
-						InstanceType:     input.LaunchTemplateConfigs[0].Overrides[0].InstanceType,
+						InstanceType:     overrideInstanceType(input.LaunchTemplateConfigs[0].Overrides[0]),
 						AvailabilityZone: input.LaunchTemplateConfigs[0].Overrides[0].AvailabilityZone,
 					},
 				},
@@ -196,6 +215,15 @@ func (e *EC2API) CreateFleetWithContext(_ context.Context, input *ec2.CreateFlee
 	})
 }
 
+// overrideInstanceType returns the instance type that an override launches. Overrides with instance requirements
+// launch the first of their allowed instance types.
+func overrideInstanceType(override *ec2.FleetLaunchTemplateOverridesRequest) *string {
+	if override.InstanceRequirements != nil && len(override.InstanceRequirements.AllowedInstanceTypes) > 0 {
+		return override.InstanceRequirements.AllowedInstanceTypes[0]
+	}
+	return override.InstanceType
+}
+
 func (e *EC2API) TerminateInstancesWithContext(_ context.Context, input *ec2.TerminateInstancesInput, _ ...request.Option) (*ec2.TerminateInstancesOutput, error) {
 	return e.TerminateInstancesBehavior.Invoke(input, func(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
 		var instanceStateChanges []*ec2.InstanceStateChange
@@ -226,18 +254,23 @@ func (e *EC2API) CreateLaunchTemplateWithContext(_ context.Context, input *ec2.C
 
 func (e *EC2API) CreateTagsWithContext(_ context.Context, input *ec2.CreateTagsInput, _ ...request.Option) (*ec2.CreateTagsOutput, error) {
 	return e.CreateTagsBehavior.Invoke(input, func(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+		// Upsert any tags that have the same key
+		tagsToMap := func(tag *ec2.Tag) (string, string) {
+			return *tag.Key, *tag.Value
+		}
 		// Update passed in instances with the passed tags
 		for _, id := range input.Resources {
+			// Volumes, network interfaces and spot requests are only tracked by their tags
+			if !strings.HasPrefix(aws.StringValue(id), "i-") {
+				raw, _ := e.ResourceTags.LoadOrStore(aws.StringValue(id), map[string]string{})
+				e.ResourceTags.Store(aws.StringValue(id), lo.Assign(raw.(map[string]string), lo.SliceToMap(input.Tags, tagsToMap)))
+				continue
+			}
 			raw, ok := e.Instances.Load(aws.StringValue(id))
 			if !ok {
 				return nil, fmt.Errorf("instance with id '%s' does not exist", aws.StringValue(id))
 			}
 			instance := raw.(*ec2.Instance)
-
-			// Upsert any tags that have the same key
-			tagsToMap := func(tag *ec2.Tag) (string, string) {
-				return *tag.Key, *tag.Value
-			}
 			tags := lo.Assign(lo.SliceToMap(instance.Tags, tagsToMap), lo.SliceToMap(input.Tags, tagsToMap))
 			instance.Tags = lo.MapToSlice(tags, func(key, value string) *ec2.Tag {
 				return &ec2.Tag{Key: aws.String(key), Value: aws.String(value)}
@@ -247,6 +280,60 @@ func (e *EC2API) CreateTagsWithContext(_ context.Context, input *ec2.CreateTagsI
 	})
 }
 
+func (e *EC2API) DeleteTagsWithContext(_ context.Context, input *ec2.DeleteTagsInput, _ ...request.Option) (*ec2.DeleteTagsOutput, error) {
+	return e.DeleteTagsBehavior.Invoke(input, func(input *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error) {
+		deleted := func(key, value string) bool {
+			return lo.ContainsBy(input.Tags, func(d *ec2.Tag) bool {
+				return aws.StringValue(d.Key) == key && (d.Value == nil || aws.StringValue(d.Value) == value)
+			})
+		}
+		// Remove the passed tags from the passed in instances
+		for _, id := range input.Resources {
+			if !strings.HasPrefix(aws.StringValue(id), "i-") {
+				if raw, ok := e.ResourceTags.Load(aws.StringValue(id)); ok {
+					e.ResourceTags.Store(aws.StringValue(id), lo.OmitBy(raw.(map[string]string), deleted))
+				}
+				continue
+			}
+			raw, ok := e.Instances.Load(aws.StringValue(id))
+			if !ok {
+				return nil, fmt.Errorf("instance with id '%s' does not exist", aws.StringValue(id))
+			}
+			instance := raw.(*ec2.Instance)
+			instance.Tags = lo.Reject(instance.Tags, func(t *ec2.Tag, _ int) bool {
+				return deleted(aws.StringValue(t.Key), aws.StringValue(t.Value))
+			})
+		}
+		return nil, nil
+	})
+}
+
+func (e *EC2API) DescribeTagsPagesWithContext(_ context.Context, input *ec2.DescribeTagsInput, fn func(*ec2.DescribeTagsOutput, bool) bool, _ ...request.Option) error {
+	if !e.NextError.IsNil() {
+		defer e.NextError.Reset()
+		return e.NextError.Get()
+	}
+	var tags []*ec2.TagDescription
+	for _, filter := range input.Filters {
+		if aws.StringValue(filter.Name) != "resource-id" {
+			continue
+		}
+		for _, id := range aws.StringValueSlice(filter.Values) {
+			resourceTags := map[string]string{}
+			if raw, ok := e.Instances.Load(id); ok {
+				resourceTags = lo.SliceToMap(raw.(*ec2.Instance).Tags, func(t *ec2.Tag) (string, string) { return aws.StringValue(t.Key), aws.StringValue(t.Value) })
+			} else if raw, ok := e.ResourceTags.Load(id); ok {
+				resourceTags = raw.(map[string]string)
+			}
+			for key, value := range resourceTags {
+				tags = append(tags, &ec2.TagDescription{ResourceId: aws.String(id), Key: aws.String(key), Value: aws.String(value)})
+			}
+		}
+	}
+	fn(&ec2.DescribeTagsOutput{Tags: tags}, false)
+	return nil
+}
+
 func (e *EC2API) DescribeInstancesWithContext(_ context.Context, input *ec2.DescribeInstancesInput, _ ...request.Option) (*ec2.DescribeInstancesOutput, error) {
 	return e.DescribeInstancesBehavior.Invoke(input, func(input *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
 		var instances []*ec2.Instance
@@ -293,6 +380,11 @@ func filterInstances(instances []*ec2.Instance, filters []*ec2.Filter) []*ec2.In
 					passesFilter = false
 					break OUTER
 				}
+			case aws.StringValue(filter.Name) == "placement-group-name":
+				if instance.Placement == nil || !sets.New(aws.StringValueSlice(filter.Values)...).Has(aws.StringValue(instance.Placement.GroupName)) {
+					passesFilter = false
+					break OUTER
+				}
 			case aws.StringValue(filter.Name) == "tag-key":
 				values := sets.New(aws.StringValueSlice(filter.Values)...)
 				if _, ok := lo.Find(instance.Tags, func(t *ec2.Tag) bool {
@@ -504,6 +596,64 @@ func (e *EC2API) DescribeSecurityGroupsWithContext(_ context.Context, input *ec2
 	return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: FilterDescribeSecurtyGroups(sgs, input.Filters)}, nil
 }
 
+func (e *EC2API) DescribeCapacityReservationsWithContext(_ context.Context, input *ec2.DescribeCapacityReservationsInput, _ ...request.Option) (*ec2.DescribeCapacityReservationsOutput, error) {
+	if !e.NextError.IsNil() {
+		defer e.NextError.Reset()
+		return nil, e.NextError.Get()
+	}
+	if e.DescribeCapacityReservationsOutput.IsNil() {
+		return &ec2.DescribeCapacityReservationsOutput{}, nil
+	}
+	describeCapacityReservationsOutput := e.DescribeCapacityReservationsOutput.Clone()
+	describeCapacityReservationsOutput.CapacityReservations = FilterDescribeCapacityReservations(describeCapacityReservationsOutput.CapacityReservations, input.CapacityReservationIds, input.Filters)
+	return describeCapacityReservationsOutput, nil
+}
+
+func (e *EC2API) DescribeCapacityReservationsPagesWithContext(ctx context.Context, input *ec2.DescribeCapacityReservationsInput, fn func(*ec2.DescribeCapacityReservationsOutput, bool) bool, _ ...request.Option) error {
+	out, err := e.DescribeCapacityReservationsWithContext(ctx, input)
+	if err != nil {
+		return err
+	}
+	fn(out, false)
+	return nil
+}
+
+func (e *EC2API) DescribePlacementGroupsWithContext(_ context.Context, input *ec2.DescribePlacementGroupsInput, _ ...request.Option) (*ec2.DescribePlacementGroupsOutput, error) {
+	if !e.NextError.IsNil() {
+		defer e.NextError.Reset()
+		return nil, e.NextError.Get()
+	}
+	if e.DescribePlacementGroupsOutput.IsNil() {
+		return &ec2.DescribePlacementGroupsOutput{}, nil
+	}
+	describePlacementGroupsOutput := e.DescribePlacementGroupsOutput.Clone()
+	describePlacementGroupsOutput.PlacementGroups = FilterDescribePlacementGroups(describePlacementGroupsOutput.PlacementGroups, input.GroupIds, input.GroupNames, input.Filters)
+	return describePlacementGroupsOutput, nil
+}
+
+func (e *EC2API) DescribeSnapshotsWithContext(_ context.Context, input *ec2.DescribeSnapshotsInput, _ ...request.Option) (*ec2.DescribeSnapshotsOutput, error) {
+	if !e.NextError.IsNil() {
+		defer e.NextError.Reset()
+		return nil, e.NextError.Get()
+	}
+	e.CalledWithDescribeSnapshotsInput.Add(input)
+	if e.DescribeSnapshotsOutput.IsNil() {
+		return &ec2.DescribeSnapshotsOutput{}, nil
+	}
+	describeSnapshotsOutput := e.DescribeSnapshotsOutput.Clone()
+	describeSnapshotsOutput.Snapshots = FilterDescribeSnapshots(describeSnapshotsOutput.Snapshots, input.OwnerIds, input.Filters)
+	return describeSnapshotsOutput, nil
+}
+
+func (e *EC2API) DescribeSnapshotsPagesWithContext(ctx context.Context, input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool, _ ...request.Option) error {
+	out, err := e.DescribeSnapshotsWithContext(ctx, input)
+	if err != nil {
+		return err
+	}
+	fn(out, false)
+	return nil
+}
+
 func (e *EC2API) DescribeAvailabilityZonesWithContext(context.Context, *ec2.DescribeAvailabilityZonesInput, ...request.Option) (*ec2.DescribeAvailabilityZonesOutput, error) {
 	if !e.NextError.IsNil() {
 		defer e.NextError.Reset()
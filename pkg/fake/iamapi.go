@@ -17,6 +17,7 @@ package fake
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -76,6 +77,18 @@ func (s *IAMAPI) GetInstanceProfileWithContext(_ context.Context, input *iam.Get
 	})
 }
 
+func (s *IAMAPI) ListInstanceProfilesPagesWithContext(_ context.Context, input *iam.ListInstanceProfilesInput, fn func(*iam.ListInstanceProfilesOutput, bool) bool, _ ...request.Option) error {
+	s.Lock()
+	defer s.Unlock()
+
+	fn(&iam.ListInstanceProfilesOutput{
+		InstanceProfiles: lo.Filter(lo.Values(s.InstanceProfiles), func(i *iam.InstanceProfile, _ int) bool {
+			return strings.HasPrefix(lo.Ternary(i.Path != nil, aws.StringValue(i.Path), "/"), lo.Ternary(input.PathPrefix != nil, aws.StringValue(input.PathPrefix), "/"))
+		}),
+	}, true)
+	return nil
+}
+
 func (s *IAMAPI) CreateInstanceProfileWithContext(_ context.Context, input *iam.CreateInstanceProfileInput, _ ...request.Option) (*iam.CreateInstanceProfileOutput, error) {
 	return s.CreateInstanceProfileBehavior.Invoke(input, func(output *iam.CreateInstanceProfileInput) (*iam.CreateInstanceProfileOutput, error) {
 		s.Lock()
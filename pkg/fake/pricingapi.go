@@ -55,11 +55,18 @@ func NewOnDemandPrice(instanceType string, price float64) aws.JSONValue {
 }
 
 func NewOnDemandPriceWithCurrency(instanceType string, price float64, currency string) aws.JSONValue {
+	return newPrice(map[string]interface{}{"instanceType": instanceType}, price, currency)
+}
+
+// NewSurplusCreditPrice returns the per vCPU-hour price of surplus CPU credits for a usage type, e.g. USE2-CPUCredits:t3
+func NewSurplusCreditPrice(usageType string, price float64) aws.JSONValue {
+	return newPrice(map[string]interface{}{"usagetype": usageType}, price, "USD")
+}
+
+func newPrice(attributes map[string]interface{}, price float64, currency string) aws.JSONValue {
 	return aws.JSONValue{
 		"product": map[string]interface{}{
-			"attributes": map[string]interface{}{
-				"instanceType": instanceType,
-			},
+			"attributes": attributes,
 		},
 		"terms": map[string]interface{}{
 			"OnDemand": map[string]interface{}{
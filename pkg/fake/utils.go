@@ -101,6 +101,66 @@ func FilterDescribeSubnets(subnets []*ec2.Subnet, filters []*ec2.Filter) []*ec2.
 	})
 }
 
+// FilterDescribeCapacityReservations filters the passed in capacity reservations based on the ids and filters passed in.
+// Filters are chained with a logical "AND"
+func FilterDescribeCapacityReservations(capacityReservations []*ec2.CapacityReservation, ids []*string, filters []*ec2.Filter) []*ec2.CapacityReservation {
+	return lo.Filter(capacityReservations, func(cr *ec2.CapacityReservation, _ int) bool {
+		if len(ids) != 0 && !lo.Contains(aws.StringValueSlice(ids), aws.StringValue(cr.CapacityReservationId)) {
+			return false
+		}
+		return lo.EveryBy(filters, func(filter *ec2.Filter) bool {
+			switch aws.StringValue(filter.Name) {
+			case "state":
+				return lo.Contains(aws.StringValueSlice(filter.Values), aws.StringValue(cr.State))
+			case "owner-id":
+				return lo.Contains(aws.StringValueSlice(filter.Values), aws.StringValue(cr.OwnerId))
+			default:
+				return Filter([]*ec2.Filter{filter}, aws.StringValue(cr.CapacityReservationId), "", cr.Tags)
+			}
+		})
+	})
+}
+
+func FilterDescribePlacementGroups(placementGroups []*ec2.PlacementGroup, ids, names []*string, filters []*ec2.Filter) []*ec2.PlacementGroup {
+	return lo.Filter(placementGroups, func(pg *ec2.PlacementGroup, _ int) bool {
+		if len(ids) != 0 && !lo.Contains(aws.StringValueSlice(ids), aws.StringValue(pg.GroupId)) {
+			return false
+		}
+		if len(names) != 0 && !lo.Contains(aws.StringValueSlice(names), aws.StringValue(pg.GroupName)) {
+			return false
+		}
+		return lo.EveryBy(filters, func(filter *ec2.Filter) bool {
+			switch aws.StringValue(filter.Name) {
+			case "state":
+				return lo.Contains(aws.StringValueSlice(filter.Values), aws.StringValue(pg.State))
+			default:
+				return Filter([]*ec2.Filter{filter}, aws.StringValue(pg.GroupId), aws.StringValue(pg.GroupName), pg.Tags)
+			}
+		})
+	})
+}
+
+// FilterDescribeSnapshots filters the passed in snapshots based on the owners and filters passed in.
+// Filters are chained with a logical "AND"
+func FilterDescribeSnapshots(snapshots []*ec2.Snapshot, owners []*string, filters []*ec2.Filter) []*ec2.Snapshot {
+	// self refers to the snapshots owned by the calling account
+	ownerIDs := lo.Map(aws.StringValueSlice(owners), func(owner string, _ int) string { return lo.Ternary(owner == "self", DefaultAccount, owner) })
+	return lo.Filter(snapshots, func(snapshot *ec2.Snapshot, _ int) bool {
+		if len(ownerIDs) != 0 && !lo.Contains(ownerIDs, aws.StringValue(snapshot.OwnerId)) &&
+			!lo.Contains(ownerIDs, aws.StringValue(snapshot.OwnerAlias)) {
+			return false
+		}
+		return lo.EveryBy(filters, func(filter *ec2.Filter) bool {
+			switch aws.StringValue(filter.Name) {
+			case "status":
+				return lo.Contains(aws.StringValueSlice(filter.Values), aws.StringValue(snapshot.State))
+			default:
+				return Filter([]*ec2.Filter{filter}, aws.StringValue(snapshot.SnapshotId), "", snapshot.Tags)
+			}
+		})
+	})
+}
+
 func FilterDescribeImages(images []*ec2.Image, filters []*ec2.Filter) []*ec2.Image {
 	return lo.Filter(images, func(image *ec2.Image, _ int) bool {
 		return Filter(filters, *image.ImageId, *image.Name, image.Tags)
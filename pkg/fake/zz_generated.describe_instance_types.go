@@ -31,6 +31,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("c6g.large"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -62,6 +63,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(10),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -75,6 +77,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("dl1.24xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -116,6 +119,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			},
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(4000),
+				Disks: []*ec2.DiskInfo{
+					{Count: aws.Int64(4), SizeInGB: aws.Int64(1000), Type: aws.String("ssd")},
+				},
 			},
 			NetworkInfo: &ec2.NetworkInfo{
 				EfaInfo: &ec2.EfaInfo{
@@ -124,6 +130,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(60),
 				Ipv4AddressesPerInterface:    aws.Int64(50),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -149,6 +156,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("g4dn.8xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -190,6 +198,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			},
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(900),
+				Disks: []*ec2.DiskInfo{
+					{Count: aws.Int64(1), SizeInGB: aws.Int64(900), Type: aws.String("ssd")},
+				},
 			},
 			NetworkInfo: &ec2.NetworkInfo{
 				EfaInfo: &ec2.EfaInfo{
@@ -198,6 +209,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -211,6 +223,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("inf1.2xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -251,6 +264,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(10),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -264,6 +278,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("inf1.6xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -304,6 +319,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(8),
 				Ipv4AddressesPerInterface:    aws.Int64(30),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -317,6 +333,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("m5.large"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -348,6 +365,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(10),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -361,6 +379,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("m5.metal"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(true),
 			Hypervisor:                    aws.String(""),
@@ -392,6 +411,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(15),
 				Ipv4AddressesPerInterface:    aws.Int64(50),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -405,6 +425,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("m5.xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -436,6 +457,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -449,6 +471,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("m6idn.32xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -478,6 +501,9 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			},
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(7600),
+				Disks: []*ec2.DiskInfo{
+					{Count: aws.Int64(4), SizeInGB: aws.Int64(1900), Type: aws.String("ssd")},
+				},
 			},
 			NetworkInfo: &ec2.NetworkInfo{
 				EfaInfo: &ec2.EfaInfo{
@@ -486,6 +512,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(16),
 				Ipv4AddressesPerInterface:    aws.Int64(50),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -503,6 +530,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("p3.8xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("xen"),
@@ -546,6 +574,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(8),
 				Ipv4AddressesPerInterface:    aws.Int64(30),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("supported"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -559,6 +588,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("t3.large"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(true),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -590,6 +620,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(12),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -603,6 +634,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("t4g.medium"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"uefi"}),
 			BurstablePerformanceSupported: aws.Bool(true),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -634,6 +666,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(6),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -647,6 +680,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("t4g.small"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"uefi"}),
 			BurstablePerformanceSupported: aws.Bool(true),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -678,6 +712,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(3),
 				Ipv4AddressesPerInterface:    aws.Int64(4),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -691,6 +726,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("t4g.xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"uefi"}),
 			BurstablePerformanceSupported: aws.Bool(true),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -722,6 +758,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(false),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
@@ -735,6 +772,7 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			InstanceType:                  aws.String("trn1.2xlarge"),
 			SupportedUsageClasses:         aws.StringSlice([]string{"on-demand", "spot"}),
 			SupportedVirtualizationTypes:  aws.StringSlice([]string{"hvm"}),
+			SupportedBootModes:            aws.StringSlice([]string{"legacy-bios", "uefi"}),
 			BurstablePerformanceSupported: aws.Bool(false),
 			BareMetal:                     aws.Bool(false),
 			Hypervisor:                    aws.String("nitro"),
@@ -764,11 +802,15 @@ var defaultDescribeInstanceTypesOutput = &ec2.DescribeInstanceTypesOutput{
 			},
 			InstanceStorageInfo: &ec2.InstanceStorageInfo{NvmeSupport: aws.String("required"),
 				TotalSizeInGB: aws.Int64(474),
+				Disks: []*ec2.DiskInfo{
+					{Count: aws.Int64(1), SizeInGB: aws.Int64(474), Type: aws.String("ssd")},
+				},
 			},
 			NetworkInfo: &ec2.NetworkInfo{
 				MaximumNetworkInterfaces:     aws.Int64(4),
 				Ipv4AddressesPerInterface:    aws.Int64(15),
 				EncryptionInTransitSupported: aws.Bool(true),
+				EnaSupport:                   aws.String("required"),
 				DefaultNetworkCardIndex:      aws.Int64(0),
 				NetworkCards: []*ec2.NetworkCardInfo{
 					{
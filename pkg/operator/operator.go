@@ -54,12 +54,15 @@ import (
 	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/placementgroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/snapshot"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/version"
 )
@@ -73,19 +76,22 @@ func init() {
 type Operator struct {
 	*operator.Operator
 
-	Session                   *session.Session
-	UnavailableOfferingsCache *awscache.UnavailableOfferings
-	EC2API                    ec2iface.EC2API
-	SubnetProvider            subnet.Provider
-	SecurityGroupProvider     securitygroup.Provider
-	InstanceProfileProvider   instanceprofile.Provider
-	AMIProvider               amifamily.Provider
-	AMIResolver               *amifamily.Resolver
-	LaunchTemplateProvider    launchtemplate.Provider
-	PricingProvider           pricing.Provider
-	VersionProvider           version.Provider
-	InstanceTypesProvider     instancetype.Provider
-	InstanceProvider          instance.Provider
+	Session                     *session.Session
+	UnavailableOfferingsCache   *awscache.UnavailableOfferings
+	EC2API                      ec2iface.EC2API
+	SubnetProvider              subnet.Provider
+	SecurityGroupProvider       securitygroup.Provider
+	CapacityReservationProvider capacityreservation.Provider
+	PlacementGroupProvider      placementgroup.Provider
+	SnapshotProvider            snapshot.Provider
+	InstanceProfileProvider     instanceprofile.Provider
+	AMIProvider                 amifamily.Provider
+	AMIResolver                 *amifamily.Resolver
+	LaunchTemplateProvider      launchtemplate.Provider
+	PricingProvider             pricing.Provider
+	VersionProvider             version.Provider
+	InstanceTypesProvider       instancetype.Provider
+	InstanceProvider            instance.Provider
 }
 
 func NewOperator(ctx context.Context, operator *operator.Operator) (context.Context, *Operator) {
@@ -136,6 +142,9 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 	unavailableOfferingsCache := awscache.NewUnavailableOfferings()
 	subnetProvider := subnet.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval), cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval))
 	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+	capacityReservationProvider := capacityreservation.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+	placementGroupProvider := placementgroup.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
+	snapshotProvider := snapshot.NewDefaultProvider(ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
 	instanceProfileProvider := instanceprofile.NewDefaultProvider(*sess.Config.Region, iam.New(sess), cache.New(awscache.InstanceProfileTTL, awscache.DefaultCleanupInterval))
 	pricingProvider := pricing.NewDefaultProvider(
 		ctx,
@@ -144,8 +153,8 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		*sess.Config.Region,
 	)
 	versionProvider := version.NewDefaultProvider(operator.KubernetesInterface, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
-	amiProvider := amifamily.NewDefaultProvider(versionProvider, ssm.New(sess), ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval))
-	amiResolver := amifamily.NewResolver(amiProvider)
+	amiProvider := amifamily.NewDefaultProvider(versionProvider, ssm.New(sess), ec2api, cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval), operator.Clock)
+	amiResolver := amifamily.NewResolver(amiProvider, operator.Clock)
 	launchTemplateProvider := launchtemplate.NewDefaultProvider(
 		ctx,
 		cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval),
@@ -166,6 +175,7 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 		subnetProvider,
 		unavailableOfferingsCache,
 		pricingProvider,
+		operator.Clock,
 	)
 	instanceProvider := instance.NewDefaultProvider(
 		ctx,
@@ -178,20 +188,23 @@ func NewOperator(ctx context.Context, operator *operator.Operator) (context.Cont
 	)
 
 	return ctx, &Operator{
-		Operator:                  operator,
-		Session:                   sess,
-		UnavailableOfferingsCache: unavailableOfferingsCache,
-		EC2API:                    ec2api,
-		SubnetProvider:            subnetProvider,
-		SecurityGroupProvider:     securityGroupProvider,
-		InstanceProfileProvider:   instanceProfileProvider,
-		AMIProvider:               amiProvider,
-		AMIResolver:               amiResolver,
-		VersionProvider:           versionProvider,
-		LaunchTemplateProvider:    launchTemplateProvider,
-		PricingProvider:           pricingProvider,
-		InstanceTypesProvider:     instanceTypeProvider,
-		InstanceProvider:          instanceProvider,
+		Operator:                    operator,
+		Session:                     sess,
+		UnavailableOfferingsCache:   unavailableOfferingsCache,
+		EC2API:                      ec2api,
+		SubnetProvider:              subnetProvider,
+		SecurityGroupProvider:       securityGroupProvider,
+		CapacityReservationProvider: capacityReservationProvider,
+		PlacementGroupProvider:      placementGroupProvider,
+		SnapshotProvider:            snapshotProvider,
+		InstanceProfileProvider:     instanceProfileProvider,
+		AMIProvider:                 amiProvider,
+		AMIResolver:                 amiResolver,
+		VersionProvider:             versionProvider,
+		LaunchTemplateProvider:      launchTemplateProvider,
+		PricingProvider:             pricingProvider,
+		InstanceTypesProvider:       instanceTypeProvider,
+		InstanceProvider:            instanceProvider,
 	}
 }
 
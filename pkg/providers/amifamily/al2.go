@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
@@ -36,10 +37,10 @@ type AL2 struct {
 }
 
 // DefaultAMIs returns the AMI name, and Requirements, with an SSM query
-func (a AL2) DefaultAMIs(version string) []DefaultAMIOutput {
+func (a AL2) DefaultAMIs(version, amiVersion string) []DefaultAMIOutput {
 	return []DefaultAMIOutput{
 		{
-			Query: fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2/recommended/image_id", version),
+			Query: a.ssmParameterName(version, "", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpDoesNotExist),
@@ -47,21 +48,21 @@ func (a AL2) DefaultAMIs(version string) []DefaultAMIOutput {
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id", version),
+			Query: a.ssmParameterName(version, "-gpu", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpExists),
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2-gpu/recommended/image_id", version),
+			Query: a.ssmParameterName(version, "-gpu", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpExists),
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2-%s/recommended/image_id", version, corev1beta1.ArchitectureArm64),
+			Query: a.ssmParameterName(version, "-"+corev1beta1.ArchitectureArm64, amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureArm64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpDoesNotExist),
@@ -71,6 +72,12 @@ func (a AL2) DefaultAMIs(version string) []DefaultAMIOutput {
 	}
 }
 
+// ssmParameterName returns the SSM parameter that stores the ID of the AL2 AMI variant at the given release
+func (a AL2) ssmParameterName(version, variant, amiVersion string) string {
+	name := lo.Ternary(amiVersion == v1beta1.AliasVersionLatest, "recommended", fmt.Sprintf("amazon-eks%s-node-%s-%s", variant, version, amiVersion))
+	return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2%s/%s/image_id", version, variant, name)
+}
+
 // UserData returns the exact same string for equivalent input,
 // even if elements of those inputs are in differing orders,
 // guaranteeing it won't cause spurious hash differences.
@@ -78,14 +85,16 @@ func (a AL2) DefaultAMIs(version string) []DefaultAMIOutput {
 func (a AL2) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []v1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string, instanceStorePolicy *v1beta1.InstanceStorePolicy) bootstrap.Bootstrapper {
 	return bootstrap.EKS{
 		Options: bootstrap.Options{
-			ClusterName:         a.Options.ClusterName,
-			ClusterEndpoint:     a.Options.ClusterEndpoint,
-			KubeletConfig:       kubeletConfig,
-			Taints:              taints,
-			Labels:              labels,
-			CABundle:            caBundle,
-			CustomUserData:      customUserData,
-			InstanceStorePolicy: instanceStorePolicy,
+			ClusterName:            a.Options.ClusterName,
+			ClusterEndpoint:        a.Options.ClusterEndpoint,
+			KubeletConfig:          kubeletConfig,
+			Taints:                 taints,
+			Labels:                 labels,
+			CABundle:               caBundle,
+			CustomUserData:         customUserData,
+			InstanceStorePolicy:    instanceStorePolicy,
+			InstanceStoreMountPath: a.Options.InstanceStoreMountPath,
+			IPv6Only:               a.Options.IPv6Only(),
 		},
 	}
 }
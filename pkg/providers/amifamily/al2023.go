@@ -32,16 +32,16 @@ type AL2023 struct {
 	*Options
 }
 
-func (a AL2023) DefaultAMIs(version string) []DefaultAMIOutput {
+func (a AL2023) DefaultAMIs(version, amiVersion string) []DefaultAMIOutput {
 	return []DefaultAMIOutput{
 		{
-			Query: fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", version),
+			Query: a.ssmParameterName(version, "x86_64", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/standard/recommended/image_id", version),
+			Query: a.ssmParameterName(version, corev1beta1.ArchitectureArm64, amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureArm64),
 			),
@@ -49,6 +49,12 @@ func (a AL2023) DefaultAMIs(version string) []DefaultAMIOutput {
 	}
 }
 
+// ssmParameterName returns the SSM parameter that stores the ID of the standard AL2023 AMI at the given release
+func (a AL2023) ssmParameterName(version, architecture, amiVersion string) string {
+	name := lo.Ternary(amiVersion == v1beta1.AliasVersionLatest, "recommended", fmt.Sprintf("amazon-eks-node-al2023-%s-standard-%s-%s", architecture, version, amiVersion))
+	return fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/%s/standard/%s/image_id", version, architecture, name)
+}
+
 func (a AL2023) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []v1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string, instanceStorePolicy *v1beta1.InstanceStorePolicy) bootstrap.Bootstrapper {
 	return bootstrap.Nodeadm{
 		Options: bootstrap.Options{
@@ -62,6 +68,7 @@ func (a AL2023) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints
 			AWSENILimitedPodDensity: false,
 			CustomUserData:          customUserData,
 			InstanceStorePolicy:     instanceStorePolicy,
+			IPv6Only:                a.Options.IPv6Only(),
 		},
 	}
 }
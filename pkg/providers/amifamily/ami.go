@@ -17,7 +17,9 @@ package amifamily
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,11 +32,13 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/version"
 
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
@@ -42,6 +46,7 @@ import (
 
 type Provider interface {
 	List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (AMIs, error)
+	Get(ctx context.Context, id string) (AMI, error)
 }
 
 type DefaultProvider struct {
@@ -51,21 +56,27 @@ type DefaultProvider struct {
 	ec2api          ec2iface.EC2API
 	cm              *pretty.ChangeMonitor
 	versionProvider version.Provider
+	clk             clock.Clock
 }
 
 type AMI struct {
-	Name         string
-	AmiID        string
-	CreationDate string
-	Requirements scheduling.Requirements
+	Name            string
+	AmiID           string
+	CreationDate    string
+	DeprecationDate string
+	Deprecated      bool
+	Requirements    scheduling.Requirements
 }
 
 type AMIs []AMI
 
-// Sort orders the AMIs by creation date in descending order.
+// Sort orders the AMIs so that AMIs which aren't deprecated come first, and then by creation date in descending order.
 // If creation date is nil or two AMIs have the same creation date, the AMIs will be sorted by ID, which is guaranteed to be unique, in ascending order.
 func (a AMIs) Sort() {
 	sort.Slice(a, func(i, j int) bool {
+		if a[i].Deprecated != a[j].Deprecated {
+			return !a[i].Deprecated
+		}
 		itime, _ := time.Parse(time.RFC3339, a[i].CreationDate)
 		jtime, _ := time.Parse(time.RFC3339, a[j].CreationDate)
 		if itime.Unix() != jtime.Unix() {
@@ -75,7 +86,8 @@ func (a AMIs) Sort() {
 	})
 }
 
-// MapToInstanceTypes returns a map of AMIIDs that are the most recent on creationDate to compatible instancetypes
+// MapToInstanceTypes returns a map of AMIIDs to compatible instancetypes. Each instance type is mapped to the first
+// compatible AMI in the order of the status, which prefers AMIs that aren't deprecated and then the most recent creationDate.
 func MapToInstanceTypes(instanceTypes []*cloudprovider.InstanceType, amis []v1beta1.AMI) map[string][]*cloudprovider.InstanceType {
 	amiIDs := map[string][]*cloudprovider.InstanceType{}
 	for _, instanceType := range instanceTypes {
@@ -89,13 +101,39 @@ func MapToInstanceTypes(instanceTypes []*cloudprovider.InstanceType, amis []v1be
 	return amiIDs
 }
 
-func NewDefaultProvider(versionProvider version.Provider, ssm ssmiface.SSMAPI, ec2api ec2iface.EC2API, cache *cache.Cache) *DefaultProvider {
+// AMIsForNodeClaim returns the AMIs that the NodeClaim is launched with. While a rollout of newly resolved AMIs is
+// progressing, the canary percentage of NodeClaims is launched with them, falling back to the active AMIs for instance
+// types that they aren't compatible with. NodeClaims are assigned to the canary by a hash of their name, so that the
+// assignment doesn't change between launch attempts.
+func AMIsForNodeClaim(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim) []v1beta1.AMI {
+	canaryAMIs := CanaryAMIs(nodeClass)
+	if len(canaryAMIs) == 0 {
+		return nodeClass.Status.AMIs
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(nodeClaim.Name))
+	if int32(h.Sum32()%100) >= nodeClass.Spec.AMIRollout.CanaryPercentage {
+		return nodeClass.Status.AMIs
+	}
+	return append(append([]v1beta1.AMI{}, canaryAMIs...), nodeClass.Status.AMIs...)
+}
+
+// CanaryAMIs returns the AMIs of the EC2NodeClass's AMI rollout while it's progressing
+func CanaryAMIs(nodeClass *v1beta1.EC2NodeClass) []v1beta1.AMI {
+	if nodeClass.Spec.AMIRollout == nil || nodeClass.Status.AMIRollout == nil || nodeClass.Status.AMIRollout.Phase != v1beta1.AMIRolloutPhaseProgressing {
+		return nil
+	}
+	return nodeClass.Status.AMIRollout.AMIs
+}
+
+func NewDefaultProvider(versionProvider version.Provider, ssm ssmiface.SSMAPI, ec2api ec2iface.EC2API, cache *cache.Cache, clk clock.Clock) *DefaultProvider {
 	return &DefaultProvider{
 		cache:           cache,
 		ssm:             ssm,
 		ec2api:          ec2api,
 		cm:              pretty.NewChangeMonitor(),
 		versionProvider: versionProvider,
+		clk:             clk,
 	}
 }
 
@@ -106,7 +144,7 @@ func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeCl
 
 	var err error
 	var amis AMIs
-	if len(nodeClass.Spec.AMISelectorTerms) == 0 {
+	if len(nodeClass.Spec.AMISelectorTerms) == 0 || nodeClass.Alias() != "" {
 		amis, err = p.getDefaultAMIs(ctx, nodeClass)
 		if err != nil {
 			return nil, err
@@ -126,18 +164,46 @@ func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeCl
 	return amis, nil
 }
 
+// Get returns the AMI with the given ID, including AMIs that have been deprecated. The AMI doesn't have requirements
+// since they only apply to AMIs selected by an EC2NodeClass.
+func (p *DefaultProvider) Get(ctx context.Context, id string) (AMI, error) {
+	if ami, ok := p.cache.Get(fmt.Sprintf("image/%s", id)); ok {
+		return ami.(AMI), nil
+	}
+	out, err := p.ec2api.DescribeImagesWithContext(ctx, &ec2.DescribeImagesInput{
+		Filters:           []*ec2.Filter{{Name: aws.String("image-id"), Values: aws.StringSlice([]string{id})}},
+		IncludeDeprecated: aws.Bool(true),
+	})
+	if err != nil {
+		return AMI{}, fmt.Errorf("describing images, %w", err)
+	}
+	if len(out.Images) == 0 {
+		return AMI{}, fmt.Errorf("image %q not found", id)
+	}
+	ami := AMI{
+		Name:            aws.StringValue(out.Images[0].Name),
+		AmiID:           aws.StringValue(out.Images[0].ImageId),
+		CreationDate:    aws.StringValue(out.Images[0].CreationDate),
+		DeprecationDate: aws.StringValue(out.Images[0].DeprecationTime),
+		Deprecated:      isDeprecated(out.Images[0], p.clk.Now()),
+	}
+	p.cache.SetDefault(fmt.Sprintf("image/%s", id), ami)
+	return ami, nil
+}
+
 func (p *DefaultProvider) getDefaultAMIs(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (res AMIs, err error) {
-	if images, ok := p.cache.Get(lo.FromPtr(nodeClass.Spec.AMIFamily)); ok {
+	kubernetesVersion, err := p.versionProvider.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting kubernetes version %w", err)
+	}
+	cacheKey := fmt.Sprintf("%s/%s/%s", lo.FromPtr(nodeClass.Spec.AMIFamily), kubernetesVersion, nodeClass.AMIVersion())
+	if images, ok := p.cache.Get(cacheKey); ok {
 		// Ensure what's returned from this function is a deep-copy of AMIs so alterations
 		// to the data don't affect the original
 		return append(AMIs{}, images.(AMIs)...), nil
 	}
 	amiFamily := GetAMIFamily(nodeClass.Spec.AMIFamily, &Options{})
-	kubernetesVersion, err := p.versionProvider.Get(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("getting kubernetes version %w", err)
-	}
-	defaultAMIs := amiFamily.DefaultAMIs(kubernetesVersion)
+	defaultAMIs := amiFamily.DefaultAMIs(kubernetesVersion, nodeClass.AMIVersion())
 	for _, ami := range defaultAMIs {
 		if id, err := p.resolveSSMParameter(ctx, ami.Query); err != nil {
 			log.FromContext(ctx).WithValues("query", ami.Query).Error(err, "failed discovering amis from ssm")
@@ -145,16 +211,19 @@ func (p *DefaultProvider) getDefaultAMIs(ctx context.Context, nodeClass *v1beta1
 			res = append(res, AMI{AmiID: id, Requirements: ami.Requirements})
 		}
 	}
-	// Resolve Name and CreationDate information into the DefaultAMIs
+	// Resolve Name, CreationDate and deprecation information into the DefaultAMIs
 	if err = p.ec2api.DescribeImagesPagesWithContext(ctx, &ec2.DescribeImagesInput{
-		Filters:    []*ec2.Filter{{Name: aws.String("image-id"), Values: aws.StringSlice(lo.Map(res, func(a AMI, _ int) string { return a.AmiID }))}},
-		MaxResults: aws.Int64(500),
+		Filters:           []*ec2.Filter{{Name: aws.String("image-id"), Values: aws.StringSlice(lo.Map(res, func(a AMI, _ int) string { return a.AmiID }))}},
+		IncludeDeprecated: aws.Bool(true),
+		MaxResults:        aws.Int64(500),
 	}, func(page *ec2.DescribeImagesOutput, _ bool) bool {
 		for i := range page.Images {
 			for j := range res {
 				if res[j].AmiID == aws.StringValue(page.Images[i].ImageId) {
 					res[j].Name = aws.StringValue(page.Images[i].Name)
 					res[j].CreationDate = aws.StringValue(page.Images[i].CreationDate)
+					res[j].DeprecationDate = aws.StringValue(page.Images[i].DeprecationTime)
+					res[j].Deprecated = isDeprecated(page.Images[i], p.clk.Now())
 				}
 			}
 		}
@@ -162,7 +231,7 @@ func (p *DefaultProvider) getDefaultAMIs(ctx context.Context, nodeClass *v1beta1
 	}); err != nil {
 		return nil, fmt.Errorf("describing images, %w", err)
 	}
-	p.cache.SetDefault(lo.FromPtr(nodeClass.Spec.AMIFamily), res)
+	p.cache.SetDefault(cacheKey, res)
 	return res, nil
 }
 
@@ -176,6 +245,10 @@ func (p *DefaultProvider) resolveSSMParameter(ctx context.Context, ssmQuery stri
 }
 
 func (p *DefaultProvider) getAMIs(ctx context.Context, terms []v1beta1.AMISelectorTerm) (AMIs, error) {
+	terms, err := p.resolveSSMParameterTerms(ctx, terms)
+	if err != nil {
+		return nil, err
+	}
 	filterAndOwnerSets := GetFilterAndOwnerSets(terms)
 	hash, err := hashstructure.Hash(filterAndOwnerSets, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	if err != nil {
@@ -190,9 +263,12 @@ func (p *DefaultProvider) getAMIs(ctx context.Context, terms []v1beta1.AMISelect
 	for _, filtersAndOwners := range filterAndOwnerSets {
 		if err = p.ec2api.DescribeImagesPagesWithContext(ctx, &ec2.DescribeImagesInput{
 			// Don't include filters in the Describe Images call as EC2 API doesn't allow empty filters.
-			Filters:    lo.Ternary(len(filtersAndOwners.Filters) > 0, filtersAndOwners.Filters, nil),
-			Owners:     lo.Ternary(len(filtersAndOwners.Owners) > 0, aws.StringSlice(filtersAndOwners.Owners), nil),
-			MaxResults: aws.Int64(1000),
+			Filters: lo.Ternary(len(filtersAndOwners.Filters) > 0, filtersAndOwners.Filters, nil),
+			Owners:  lo.Ternary(len(filtersAndOwners.Owners) > 0, aws.StringSlice(filtersAndOwners.Owners), nil),
+			// Deprecated images are only returned to their owner unless they're explicitly included. We include them so
+			// that they can still be used when they're the only images that match, but prefer images that aren't deprecated.
+			IncludeDeprecated: aws.Bool(true),
+			MaxResults:        aws.Int64(1000),
 		}, func(page *ec2.DescribeImagesOutput, _ bool) bool {
 			for i := range page.Images {
 				reqs := p.getRequirementsFromImage(page.Images[i])
@@ -200,8 +276,9 @@ func (p *DefaultProvider) getAMIs(ctx context.Context, terms []v1beta1.AMISelect
 					continue
 				}
 				reqsHash := lo.Must(hashstructure.Hash(reqs.NodeSelectorRequirements(), hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
-				// If the proposed image is newer, store it so that we can return it
-				if v, ok := images[reqsHash]; ok {
+				deprecated := isDeprecated(page.Images[i], p.clk.Now())
+				// If the proposed image isn't deprecated when the existing one is, or is newer, store it so that we can return it
+				if v, ok := images[reqsHash]; ok && v.Deprecated == deprecated {
 					candidateCreationTime, _ := time.Parse(time.RFC3339, lo.FromPtr(page.Images[i].CreationDate))
 					existingCreationTime, _ := time.Parse(time.RFC3339, v.CreationDate)
 					if existingCreationTime == candidateCreationTime && lo.FromPtr(page.Images[i].Name) < v.Name {
@@ -210,12 +287,16 @@ func (p *DefaultProvider) getAMIs(ctx context.Context, terms []v1beta1.AMISelect
 					if candidateCreationTime.Unix() < existingCreationTime.Unix() {
 						continue
 					}
+				} else if ok && deprecated {
+					continue
 				}
 				images[reqsHash] = AMI{
-					Name:         lo.FromPtr(page.Images[i].Name),
-					AmiID:        lo.FromPtr(page.Images[i].ImageId),
-					CreationDate: lo.FromPtr(page.Images[i].CreationDate),
-					Requirements: reqs,
+					Name:            lo.FromPtr(page.Images[i].Name),
+					AmiID:           lo.FromPtr(page.Images[i].ImageId),
+					CreationDate:    lo.FromPtr(page.Images[i].CreationDate),
+					DeprecationDate: lo.FromPtr(page.Images[i].DeprecationTime),
+					Deprecated:      deprecated,
+					Requirements:    reqs,
 				}
 			}
 			return true
@@ -227,6 +308,24 @@ func (p *DefaultProvider) getAMIs(ctx context.Context, terms []v1beta1.AMISelect
 	return lo.Values(images), nil
 }
 
+// resolveSSMParameterTerms replaces the ssmParameter selector terms with id selector terms for the AMIs that the
+// parameters currently store
+func (p *DefaultProvider) resolveSSMParameterTerms(ctx context.Context, terms []v1beta1.AMISelectorTerm) ([]v1beta1.AMISelectorTerm, error) {
+	res := make([]v1beta1.AMISelectorTerm, 0, len(terms))
+	for _, term := range terms {
+		if term.SSMParameter == "" {
+			res = append(res, term)
+			continue
+		}
+		id, err := p.resolveSSMParameter(ctx, term.SSMParameter)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v1beta1.AMISelectorTerm{ID: id})
+	}
+	return res, nil
+}
+
 type FiltersAndOwners struct {
 	Filters []*ec2.Filter
 	Owners  []string
@@ -276,8 +375,33 @@ func GetFilterAndOwnerSets(terms []v1beta1.AMISelectorTerm) (res []FiltersAndOwn
 	return res
 }
 
+// isDeprecated returns whether the deprecation time of the image has passed
+func isDeprecated(image *ec2.Image, now time.Time) bool {
+	if image.DeprecationTime == nil {
+		return false
+	}
+	deprecationTime, err := time.Parse(time.RFC3339, aws.StringValue(image.DeprecationTime))
+	if err != nil {
+		return false
+	}
+	return !deprecationTime.After(now)
+}
+
 func (p *DefaultProvider) getRequirementsFromImage(ec2Image *ec2.Image) scheduling.Requirements {
 	requirements := scheduling.NewRequirements()
+	for _, tag := range ec2Image.Tags {
+		if requirement, ok := requirementFromTag(tag); ok {
+			requirements.Add(requirement)
+		}
+	}
+	// Images that can only boot with UEFI can't launch on instance types that only support legacy BIOS
+	if aws.StringValue(ec2Image.BootMode) == ec2.BootModeValuesUefi {
+		requirements.Add(scheduling.NewRequirement(v1beta1.LabelInstanceUEFISupported, v1.NodeSelectorOpIn, "true"))
+	}
+	// Images without the ENA driver can't launch on instance types that require it
+	if ec2Image.EnaSupport != nil && !aws.BoolValue(ec2Image.EnaSupport) {
+		requirements.Add(scheduling.NewRequirement(v1beta1.LabelInstanceENASupport, v1.NodeSelectorOpNotIn, ec2.EnaSupportRequired))
+	}
 	// Always add the architecture of an image as a requirement, irrespective of what's specified in EC2 tags.
 	architecture := *ec2Image.Architecture
 	if value, ok := v1beta1.AWSToKubeArchitectures[architecture]; ok {
@@ -286,3 +410,33 @@ func (p *DefaultProvider) getRequirementsFromImage(ec2Image *ec2.Image) scheduli
 	requirements.Add(scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, architecture))
 	return requirements
 }
+
+// requirementFromTag parses a requirement from an AMI tag that follows the v1beta1.TagAMIRequirementPrefix convention.
+// The tag value is a comma separated list of values that the label must have, or must not have if it's prefixed
+// with "!". A value of "*" requires the label to exist and an empty value requires that it doesn't. Tags for labels
+// that aren't well known instance type labels are ignored.
+func requirementFromTag(tag *ec2.Tag) (*scheduling.Requirement, bool) {
+	name, ok := strings.CutPrefix(aws.StringValue(tag.Key), v1beta1.TagAMIRequirementPrefix)
+	if !ok {
+		return nil, false
+	}
+	key := fmt.Sprintf("%s/%s", v1beta1.Group, name)
+	if !corev1beta1.WellKnownLabels.Has(key) {
+		return nil, false
+	}
+	value := strings.TrimSpace(aws.StringValue(tag.Value))
+	switch {
+	case value == "":
+		return scheduling.NewRequirement(key, v1.NodeSelectorOpDoesNotExist), true
+	case value == "*":
+		return scheduling.NewRequirement(key, v1.NodeSelectorOpExists), true
+	case strings.HasPrefix(value, "!"):
+		return scheduling.NewRequirement(key, v1.NodeSelectorOpNotIn, splitTagValues(strings.TrimPrefix(value, "!"))...), true
+	default:
+		return scheduling.NewRequirement(key, v1.NodeSelectorOpIn, splitTagValues(value)...), true
+	}
+}
+
+func splitTagValues(value string) []string {
+	return lo.Compact(lo.Map(strings.Split(value, ","), func(v string, _ int) string { return strings.TrimSpace(v) }))
+}
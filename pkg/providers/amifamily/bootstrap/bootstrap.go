@@ -41,6 +41,8 @@ type Options struct {
 	ContainerRuntime        *string
 	CustomUserData          *string
 	InstanceStorePolicy     *v1beta1.InstanceStorePolicy
+	InstanceStoreMountPath  *string
+	IPv6Only                bool
 }
 
 func (o Options) kubeletExtraArgs() (args []string) {
@@ -17,14 +17,21 @@ package bootstrap
 import (
 	"encoding/base64"
 	"fmt"
+	"net"
 	"strconv"
 
 	"github.com/imdario/mergo"
 	"github.com/samber/lo"
 
 	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 )
 
+// bottlerocketEphemeralStorageCommandName is the name of the bootstrap command that configures instance-store disks.
+// Bootstrap commands run in lexicographical order, so the prefix ensures it runs before any user-defined commands.
+const bottlerocketEphemeralStorageCommandName = "000-karpenter-ephemeral-storage"
+
 type Bottlerocket struct {
 	Options
 }
@@ -79,9 +86,49 @@ func (b Bottlerocket) Script() (string, error) {
 	for _, taint := range b.Taints {
 		s.Settings.Kubernetes.NodeTaints[taint.Key] = append(s.Settings.Kubernetes.NodeTaints[taint.Key], fmt.Sprintf("%s:%s", taint.Value, taint.Effect))
 	}
+	// Bottlerocket chooses the node IP family from the cluster DNS IP, so IPv6-only instances require an IPv6 cluster
+	if ip := net.ParseIP(lo.FromPtr(s.Settings.Kubernetes.ClusterDNSIP)); b.IPv6Only && (ip == nil || ip.To4() != nil) {
+		return "", fmt.Errorf("IPv6-only instances require an IPv6 cluster DNS IP, got %q", lo.FromPtr(s.Settings.Kubernetes.ClusterDNSIP))
+	}
+	if command, ok := b.ephemeralStorageCommand(); ok {
+		if s.SettingsRaw == nil {
+			s.SettingsRaw = map[string]interface{}{}
+		}
+		bootstrapCommands, _ := s.SettingsRaw["bootstrap-commands"].(map[string]interface{})
+		if bootstrapCommands == nil {
+			bootstrapCommands = map[string]interface{}{}
+		}
+		bootstrapCommands[bottlerocketEphemeralStorageCommandName] = command
+		s.SettingsRaw["bootstrap-commands"] = bootstrapCommands
+	}
 	script, err := s.MarshalTOML()
 	if err != nil {
 		return "", fmt.Errorf("constructing toml UserData %w", err)
 	}
 	return base64.StdEncoding.EncodeToString(script), nil
 }
+
+// ephemeralStorageCommand returns the bootstrap command that assembles instance-store disks into an array with
+// Bottlerocket's ephemeral-storage API and binds the directories selected by the InstanceStorePolicy to it. Bottlerocket
+// can't mount disks individually, so the Mount policy binds the whole array to the instanceStoreMountPath.
+func (b Bottlerocket) ephemeralStorageCommand() (BottlerocketBootstrapCommand, bool) {
+	var dirs []string
+	switch lo.FromPtr(b.InstanceStorePolicy) {
+	case v1beta1.InstanceStorePolicyRAID0:
+		dirs = []string{"/var/lib/containerd", "/var/lib/kubelet", "/var/log/pods"}
+	case v1beta1.InstanceStorePolicyRAID0Containerd:
+		dirs = []string{"/var/lib/containerd"}
+	case v1beta1.InstanceStorePolicyMount:
+		dirs = []string{b.instanceStoreMountPath()}
+	default:
+		return BottlerocketBootstrapCommand{}, false
+	}
+	return BottlerocketBootstrapCommand{
+		Commands: [][]string{
+			{"apiclient", "ephemeral-storage", "init"},
+			append([]string{"apiclient", "ephemeral-storage", "bind", "--dirs"}, dirs...),
+		},
+		Mode:      "always",
+		Essential: true,
+	}, true
+}
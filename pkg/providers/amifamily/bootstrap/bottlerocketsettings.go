@@ -79,6 +79,13 @@ type BottlerocketKubernetes struct {
 	SeccompDefault                     *bool                                     `toml:"seccomp-default,omitempty"`
 }
 
+// BottlerocketBootstrapCommand is a list of apiclient commands that Bottlerocket runs during boot, before kubelet starts
+type BottlerocketBootstrapCommand struct {
+	Commands  [][]string `toml:"commands"`
+	Mode      string     `toml:"mode"`
+	Essential bool       `toml:"essential"`
+}
+
 type BottlerocketStaticPod struct {
 	Enabled  *bool   `toml:"enabled,omitempty"`
 	Manifest *string `toml:"manifest,omitempty"`
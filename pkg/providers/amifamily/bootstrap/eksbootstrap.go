@@ -62,6 +62,7 @@ func (e EKS) eksBootstrapScript() string {
 	var userData bytes.Buffer
 	userData.WriteString("#!/bin/bash -xe\n")
 	userData.WriteString("exec > >(tee /var/log/user-data.log|logger -t user-data -s 2>/dev/console) 2>&1\n")
+	userData.WriteString(e.instanceStoreScript())
 	// Due to the way bootstrap.sh is written, parameters should not be passed to it with an equal sign
 	userData.WriteString(fmt.Sprintf("/etc/eks/bootstrap.sh '%s' --apiserver-endpoint '%s' %s", e.ClusterName, e.ClusterEndpoint, caBundleArg))
 
@@ -116,6 +117,9 @@ func (e EKS) mergeCustomUserData(userDatas ...string) (string, error) {
 }
 
 func (e EKS) isIPv6() bool {
+	if e.IPv6Only {
+		return true
+	}
 	if e.KubeletConfig == nil || len(e.KubeletConfig.ClusterDNS) == 0 {
 		return false
 	}
@@ -0,0 +1,67 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+// raid0ContainerdScript assembles all NVMe instance-store disks into a RAID-0 array and bind mounts it over the
+// containerd state directory only. The array is mounted outside of the directories that setup-local-disks uses, and
+// any content that's already present in the containerd state directory (e.g. images cached in the AMI) is copied onto
+// it. kubelet and containerd are stopped while the array is set up in case they were started before the script ran.
+const raid0ContainerdScript = `disks=$(find -L /dev/disk/by-id/ -xtype l -name '*NVMe_Instance_Storage_*' -exec readlink -f {} \; | sort -u)
+if [ -n "${disks}" ] && ! mountpoint -q /var/lib/containerd; then
+  stopped=""
+  for unit in kubelet containerd; do
+    if systemctl is-active --quiet "${unit}"; then
+      systemctl stop "${unit}"
+      stopped="${unit} ${stopped}"
+    fi
+  done
+  mdadm --create --force --verbose /dev/md/containerd --level=0 --name=containerd --raid-devices=$(echo "${disks}" | wc -l) ${disks}
+  mkfs.xfs -f -l su=8b /dev/md/containerd
+  mkdir -p /mnt/instance-store/containerd /var/lib/containerd
+  mount -o defaults,noatime /dev/md/containerd /mnt/instance-store/containerd
+  cp -a /var/lib/containerd/. /mnt/instance-store/containerd/
+  mount --bind /mnt/instance-store/containerd /var/lib/containerd
+  for unit in ${stopped}; do
+    systemctl start "${unit}"
+  done
+fi
+`
+
+// instanceStoreScript returns the shell commands that configure instance-store disks for the policies that the
+// EKS bootstrap script doesn't handle natively. An empty string is returned when there's nothing to run.
+func (o Options) instanceStoreScript() string {
+	switch lo.FromPtr(o.InstanceStorePolicy) {
+	case v1beta1.InstanceStorePolicyMount:
+		return fmt.Sprintf("setup-local-disks --dir '%s' mount\n", o.instanceStoreMountPath())
+	case v1beta1.InstanceStorePolicyRAID0Containerd:
+		return raid0ContainerdScript
+	default:
+		return ""
+	}
+}
+
+// instanceStoreMountPath returns the directory that instance-store disks are mounted under with the Mount policy.
+// The path is restricted to characters that don't need to be escaped inside a single-quoted shell string.
+func (o Options) instanceStoreMountPath() string {
+	return lo.FromPtrOr(o.InstanceStoreMountPath, v1beta1.DefaultInstanceStoreMountPath)
+}
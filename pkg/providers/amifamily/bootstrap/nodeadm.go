@@ -17,6 +17,7 @@ package bootstrap
 import (
 	"encoding/base64"
 	"fmt"
+	"net"
 	"strings"
 
 	admapi "github.com/awslabs/amazon-eks-ami/nodeadm/api"
@@ -45,10 +46,19 @@ func (n Nodeadm) Script() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("parsing custom UserData, %w", err)
 	}
-	mimeArchive := mime.Archive(append([]mime.Entry{{
+	entries := []mime.Entry{{
 		ContentType: mime.ContentTypeNodeConfig,
 		Content:     nodeConfigYAML,
-	}}, customEntries...))
+	}}
+	// nodeadm has no strategy that dedicates instance-store disks to containerd, so the array is set up by a script
+	// that stops containerd and kubelet if they're already running
+	if lo.FromPtr(n.InstanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0Containerd {
+		entries = append(entries, mime.Entry{
+			ContentType: mime.ContentTypeShellScript,
+			Content:     fmt.Sprintf("#!/bin/bash -xe\n%s", raid0ContainerdScript),
+		})
+	}
+	mimeArchive := mime.Archive(append(entries, customEntries...))
 	userData, err := mimeArchive.Serialize()
 	if err != nil {
 		return "", err
@@ -83,8 +93,15 @@ func (n Nodeadm) getNodeConfigYAML() (string, error) {
 	} else {
 		return "", cloudprovider.NewNodeClassNotReadyError(fmt.Errorf("resolving cluster CIDR"))
 	}
-	if lo.FromPtr(n.InstanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0 {
+	// nodeadm chooses the node IP family from the cluster CIDR, so IPv6-only instances require an IPv6 cluster
+	if ip, _, err := net.ParseCIDR(config.Spec.Cluster.CIDR); n.IPv6Only && (err != nil || ip.To4() != nil) {
+		return "", fmt.Errorf("IPv6-only instances require an IPv6 cluster CIDR, got %q", config.Spec.Cluster.CIDR)
+	}
+	switch lo.FromPtr(n.InstanceStorePolicy) {
+	case v1beta1.InstanceStorePolicyRAID0:
 		config.Spec.Instance.LocalStorage.Strategy = admv1alpha1.LocalStorageRAID0
+	case v1beta1.InstanceStorePolicyMount:
+		config.Spec.Instance.LocalStorage.Strategy = admv1alpha1.LocalStorageMount
 	}
 	inlineConfig, err := n.generateInlineKubeletConfiguration()
 	if err != nil {
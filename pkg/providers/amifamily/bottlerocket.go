@@ -16,6 +16,7 @@ package amifamily
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 
@@ -38,10 +39,10 @@ type Bottlerocket struct {
 }
 
 // DefaultAMIs returns the AMI name, and Requirements, with an SSM query
-func (b Bottlerocket) DefaultAMIs(version string) []DefaultAMIOutput {
+func (b Bottlerocket) DefaultAMIs(version, amiVersion string) []DefaultAMIOutput {
 	return []DefaultAMIOutput{
 		{
-			Query: fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_id", version),
+			Query: b.ssmParameterName(version, "", "x86_64", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpDoesNotExist),
@@ -49,21 +50,21 @@ func (b Bottlerocket) DefaultAMIs(version string) []DefaultAMIOutput {
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s-nvidia/x86_64/latest/image_id", version),
+			Query: b.ssmParameterName(version, "-nvidia", "x86_64", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpExists),
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s-nvidia/x86_64/latest/image_id", version),
+			Query: b.ssmParameterName(version, "-nvidia", "x86_64", amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpExists),
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/%s/latest/image_id", version, corev1beta1.ArchitectureArm64),
+			Query: b.ssmParameterName(version, "", corev1beta1.ArchitectureArm64, amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureArm64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpDoesNotExist),
@@ -71,14 +72,14 @@ func (b Bottlerocket) DefaultAMIs(version string) []DefaultAMIOutput {
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s-nvidia/%s/latest/image_id", version, corev1beta1.ArchitectureArm64),
+			Query: b.ssmParameterName(version, "-nvidia", corev1beta1.ArchitectureArm64, amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureArm64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpExists),
 			),
 		},
 		{
-			Query: fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s-nvidia/%s/latest/image_id", version, corev1beta1.ArchitectureArm64),
+			Query: b.ssmParameterName(version, "-nvidia", corev1beta1.ArchitectureArm64, amiVersion),
 			Requirements: scheduling.NewRequirements(
 				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureArm64),
 				scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpExists),
@@ -87,17 +88,26 @@ func (b Bottlerocket) DefaultAMIs(version string) []DefaultAMIOutput {
 	}
 }
 
+// ssmParameterName returns the SSM parameter that stores the ID of the Bottlerocket AMI variant at the given release.
+// Bottlerocket releases are published without the "v" prefix, so it's trimmed from pinned versions.
+func (b Bottlerocket) ssmParameterName(version, variant, architecture, amiVersion string) string {
+	return fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s%s/%s/%s/image_id", version, variant, architecture, strings.TrimPrefix(amiVersion, "v"))
+}
+
 // UserData returns the default userdata script for the AMI Family
-func (b Bottlerocket) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []v1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string, _ *v1beta1.InstanceStorePolicy) bootstrap.Bootstrapper {
+func (b Bottlerocket) UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []v1.Taint, labels map[string]string, caBundle *string, _ []*cloudprovider.InstanceType, customUserData *string, instanceStorePolicy *v1beta1.InstanceStorePolicy) bootstrap.Bootstrapper {
 	return bootstrap.Bottlerocket{
 		Options: bootstrap.Options{
-			ClusterName:     b.Options.ClusterName,
-			ClusterEndpoint: b.Options.ClusterEndpoint,
-			KubeletConfig:   kubeletConfig,
-			Taints:          taints,
-			Labels:          labels,
-			CABundle:        caBundle,
-			CustomUserData:  customUserData,
+			ClusterName:            b.Options.ClusterName,
+			ClusterEndpoint:        b.Options.ClusterEndpoint,
+			KubeletConfig:          kubeletConfig,
+			Taints:                 taints,
+			Labels:                 labels,
+			CABundle:               caBundle,
+			CustomUserData:         customUserData,
+			InstanceStorePolicy:    instanceStorePolicy,
+			InstanceStoreMountPath: b.Options.InstanceStoreMountPath,
+			IPv6Only:               b.Options.IPv6Only(),
 		},
 	}
 }
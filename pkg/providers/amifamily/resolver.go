@@ -16,7 +16,10 @@ package amifamily
 
 import (
 	"fmt"
+	"math"
 	"net"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -24,11 +27,13 @@ import (
 	"github.com/samber/lo"
 	core "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/clock"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily/bootstrap"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
@@ -43,16 +48,18 @@ var DefaultEBS = v1beta1.BlockDevice{
 // Resolver is able to fill-in dynamic launch template parameters
 type Resolver struct {
 	amiProvider Provider
+	clk         clock.Clock
 }
 
 // Options define the static launch template parameters
 type Options struct {
-	ClusterName         string
-	ClusterEndpoint     string
-	ClusterCIDR         *string
-	InstanceProfile     string
-	CABundle            *string `hash:"ignore"`
-	InstanceStorePolicy *v1beta1.InstanceStorePolicy
+	ClusterName            string
+	ClusterEndpoint        string
+	ClusterCIDR            *string
+	InstanceProfile        string
+	CABundle               *string `hash:"ignore"`
+	InstanceStorePolicy    *v1beta1.InstanceStorePolicy
+	InstanceStoreMountPath *string
 	// Level-triggered fields that may change out of sync.
 	SecurityGroups           []v1beta1.SecurityGroup
 	Tags                     map[string]string
@@ -60,6 +67,17 @@ type Options struct {
 	KubeDNSIP                net.IP
 	AssociatePublicIPAddress *bool
 	NodeClassName            string
+	// PlacementGroupID and PlacementGroupPartition constrain where instances are placed when the EC2NodeClass
+	// selects a placement group
+	PlacementGroupID        string
+	PlacementGroupPartition int64
+	Tenancy                 *v1beta1.Tenancy
+	NetworkOptions          *v1beta1.NetworkOptions
+}
+
+// IPv6Only returns whether instances are launched without an IPv4 address
+func (o Options) IPv6Only() bool {
+	return o.NetworkOptions != nil && lo.FromPtr(o.NetworkOptions.IPv6Only)
 }
 
 // LaunchTemplate holds the dynamically generated launch template parameters
@@ -68,16 +86,24 @@ type LaunchTemplate struct {
 	UserData            bootstrap.Bootstrapper
 	BlockDeviceMappings []*v1beta1.BlockDeviceMapping
 	MetadataOptions     *v1beta1.MetadataOptions
+	CPUOptions          *v1beta1.CPUOptions
 	AMIID               string
 	InstanceTypes       []*cloudprovider.InstanceType `hash:"ignore"`
 	DetailedMonitoring  bool
 	EFACount            int
 	CapacityType        string
+	// CapacityReservationID is the capacity reservation targeted by the launch template when launching reserved capacity
+	CapacityReservationID string
+	// CapacityReservationType is the type of the targeted capacity reservation, which determines the market options
+	// that the instance must be launched with
+	CapacityReservationType string
+	// CPUCredits is the credit option that burstable performance instances are launched with
+	CPUCredits string
 }
 
 // AMIFamily can be implemented to override the default logic for generating dynamic launch template parameters
 type AMIFamily interface {
-	DefaultAMIs(version string) []DefaultAMIOutput
+	DefaultAMIs(version, amiVersion string) []DefaultAMIOutput
 	UserData(kubeletConfig *corev1beta1.KubeletConfiguration, taints []core.Taint, labels map[string]string, caBundle *string, instanceTypes []*cloudprovider.InstanceType, customUserData *string, instanceStorePolicy *v1beta1.InstanceStorePolicy) bootstrap.Bootstrapper
 	DefaultBlockDeviceMappings() []*v1beta1.BlockDeviceMapping
 	DefaultMetadataOptions() *v1beta1.MetadataOptions
@@ -111,9 +137,10 @@ func (d DefaultFamily) FeatureFlags() FeatureFlags {
 }
 
 // NewResolver constructs a new launch template Resolver
-func NewResolver(amiProvider Provider) *Resolver {
+func NewResolver(amiProvider Provider, clk clock.Clock) *Resolver {
 	return &Resolver{
 		amiProvider: amiProvider,
+		clk:         clk,
 	}
 }
 
@@ -121,12 +148,13 @@ func NewResolver(amiProvider Provider) *Resolver {
 // Multiple ResolvedTemplates are returned based on the instanceTypes passed in to support special AMIs for certain instance types like GPUs.
 func (r Resolver) Resolve(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, capacityType string, options *Options) ([]*LaunchTemplate, error) {
 	amiFamily := GetAMIFamily(nodeClass.Spec.AMIFamily, options)
-	if len(nodeClass.Status.AMIs) == 0 {
+	amis := AMIsForNodeClaim(nodeClass, nodeClaim)
+	if len(amis) == 0 {
 		return nil, fmt.Errorf("no amis exist given constraints")
 	}
-	mappedAMIs := MapToInstanceTypes(instanceTypes, nodeClass.Status.AMIs)
+	mappedAMIs := MapToInstanceTypes(instanceTypes, amis)
 	if len(mappedAMIs) == 0 {
-		return nil, fmt.Errorf("no instance types satisfy requirements of amis %v", lo.Uniq(lo.Map(nodeClass.Status.AMIs, func(a v1beta1.AMI, _ int) string { return a.ID })))
+		return nil, fmt.Errorf("no instance types satisfy requirements of amis %v", lo.Uniq(lo.Map(amis, func(a v1beta1.AMI, _ int) string { return a.ID })))
 	}
 	var resolvedTemplates []*LaunchTemplate
 	for amiID, instanceTypes := range mappedAMIs {
@@ -134,10 +162,14 @@ func (r Resolver) Resolve(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta
 		// we need to pass down the max-pods calculation to the kubelet.
 		// This requires that we resolve a unique launch template per max-pods value.
 		// Similarly, instance types configured with EfAs require unique launch templates depending on the number of
-		// EFAs they support.
+		// EFAs they support. Credit specifications can only be applied to burstable performance instances, so they
+		// are resolved in a separate launch template as well.
+		// Volumes with a size policy are sized per instance type, so instance types are also grouped by their volume sizes.
 		type launchTemplateParams struct {
-			efaCount int
-			maxPods  int
+			efaCount    int
+			maxPods     int
+			burstable   bool
+			volumeSizes string
 		}
 		paramsToInstanceTypes := lo.GroupBy(instanceTypes, func(instanceType *cloudprovider.InstanceType) launchTemplateParams {
 			return launchTemplateParams{
@@ -146,20 +178,46 @@ func (r Resolver) Resolve(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta
 					int(lo.ToPtr(instanceType.Capacity[v1beta1.ResourceEFA]).Value()),
 					0,
 				),
-				maxPods: int(instanceType.Capacity.Pods().Value()),
+				maxPods:     int(instanceType.Capacity.Pods().Value()),
+				burstable:   nodeClass.Spec.CreditSpecification != nil && instanceType.Requirements.Get(v1beta1.LabelInstanceBurstable).Has("true"),
+				volumeSizes: volumeSizesKey(nodeClass.Spec.BlockDeviceMappings, instanceType),
 			}
 		})
 		for params, instanceTypes := range paramsToInstanceTypes {
-			resolved, err := r.resolveLaunchTemplate(nodeClass, nodeClaim, instanceTypes, capacityType, amiFamily, amiID, params.maxPods, params.efaCount, options)
-			if err != nil {
-				return nil, err
+			// Launch templates can only target a single capacity reservation, so reserved launches
+			// require a unique launch template per capacity reservation.
+			reservationsToInstanceTypes := map[string][]*cloudprovider.InstanceType{"": instanceTypes}
+			if capacityType == v1beta1.CapacityTypeReserved {
+				reservationsToInstanceTypes = mapToCapacityReservations(nodeClaim, instanceTypes, nodeClass.Status.CapacityReservations, r.clk.Now())
+			}
+			for capacityReservationID, instanceTypes := range reservationsToInstanceTypes {
+				resolved, err := r.resolveLaunchTemplate(nodeClass, nodeClaim, instanceTypes, capacityType, amiFamily, amiID, params.maxPods, params.efaCount, params.burstable, capacityReservationID, options)
+				if err != nil {
+					return nil, err
+				}
+				resolvedTemplates = append(resolvedTemplates, resolved)
 			}
-			resolvedTemplates = append(resolvedTemplates, resolved)
 		}
 	}
 	return resolvedTemplates, nil
 }
 
+// mapToCapacityReservations groups the instance types by the available capacity reservations that they can be launched
+// into, limited to the reservations allowed by the NodeClaim's capacity reservation ID requirement
+func mapToCapacityReservations(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, capacityReservations []v1beta1.CapacityReservation, now time.Time) map[string][]*cloudprovider.InstanceType {
+	allowed := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(v1beta1.LabelCapacityReservationID)
+	reservationsToInstanceTypes := map[string][]*cloudprovider.InstanceType{}
+	for _, cr := range capacityReservations {
+		if cr.AvailableInstanceCount == 0 || !capacityreservation.IsActive(cr, now) || !allowed.Has(cr.ID) {
+			continue
+		}
+		if it, ok := lo.Find(instanceTypes, func(it *cloudprovider.InstanceType) bool { return it.Name == cr.InstanceType }); ok {
+			reservationsToInstanceTypes[cr.ID] = append(reservationsToInstanceTypes[cr.ID], it)
+		}
+	}
+	return reservationsToInstanceTypes
+}
+
 func GetAMIFamily(amiFamily *string, options *Options) AMIFamily {
 	switch aws.StringValue(amiFamily) {
 	case v1beta1.AMIFamilyBottlerocket:
@@ -180,9 +238,11 @@ func GetAMIFamily(amiFamily *string, options *Options) AMIFamily {
 }
 
 func (o Options) DefaultMetadataOptions() *v1beta1.MetadataOptions {
+	// IPv6-only instances can only reach the instance metadata service through its IPv6 endpoint
+	ipv6 := o.IPv6Only() || (o.KubeDNSIP != nil && o.KubeDNSIP.To4() == nil)
 	return &v1beta1.MetadataOptions{
 		HTTPEndpoint:            aws.String(ec2.LaunchTemplateInstanceMetadataEndpointStateEnabled),
-		HTTPProtocolIPv6:        aws.String(lo.Ternary(o.KubeDNSIP == nil || o.KubeDNSIP.To4() != nil, ec2.LaunchTemplateInstanceMetadataProtocolIpv6Disabled, ec2.LaunchTemplateInstanceMetadataProtocolIpv6Enabled)),
+		HTTPProtocolIPv6:        aws.String(lo.Ternary(ipv6, ec2.LaunchTemplateInstanceMetadataProtocolIpv6Enabled, ec2.LaunchTemplateInstanceMetadataProtocolIpv6Disabled)),
 		HTTPPutResponseHopLimit: aws.Int64(2),
 		HTTPTokens:              aws.String(ec2.LaunchTemplateHttpTokensStateRequired),
 	}
@@ -206,10 +266,10 @@ func (r Resolver) defaultClusterDNS(opts *Options, kubeletConfig *corev1beta1.Ku
 }
 
 func (r Resolver) resolveLaunchTemplate(nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, capacityType string,
-	amiFamily AMIFamily, amiID string, maxPods int, efaCount int, options *Options) (*LaunchTemplate, error) {
+	amiFamily AMIFamily, amiID string, maxPods int, efaCount int, burstable bool, capacityReservationID string, options *Options) (*LaunchTemplate, error) {
 	kubeletConfig := &corev1beta1.KubeletConfiguration{}
-	if nodeClaim.Spec.Kubelet != nil {
-		if err := mergo.Merge(kubeletConfig, nodeClaim.Spec.Kubelet); err != nil {
+	if kubelet := nodeClass.KubeletConfiguration(nodeClaim.Spec.Kubelet); kubelet != nil {
+		if err := mergo.Merge(kubeletConfig, kubelet); err != nil {
 			return nil, err
 		}
 	}
@@ -227,19 +287,115 @@ func (r Resolver) resolveLaunchTemplate(nodeClass *v1beta1.EC2NodeClass, nodeCla
 			nodeClass.Spec.UserData,
 			options.InstanceStorePolicy,
 		),
-		BlockDeviceMappings: nodeClass.Spec.BlockDeviceMappings,
-		MetadataOptions:     nodeClass.Spec.MetadataOptions,
-		DetailedMonitoring:  aws.BoolValue(nodeClass.Spec.DetailedMonitoring),
-		AMIID:               amiID,
-		InstanceTypes:       instanceTypes,
-		EFACount:            efaCount,
-		CapacityType:        capacityType,
+		BlockDeviceMappings:   nodeClass.Spec.BlockDeviceMappings,
+		MetadataOptions:       nodeClass.Spec.MetadataOptions,
+		CPUOptions:            nodeClass.Spec.CPUOptions,
+		DetailedMonitoring:    aws.BoolValue(nodeClass.Spec.DetailedMonitoring),
+		AMIID:                 amiID,
+		InstanceTypes:         instanceTypes,
+		EFACount:              efaCount,
+		CapacityType:          capacityType,
+		CapacityReservationID: capacityReservationID,
 	}
 	if len(resolved.BlockDeviceMappings) == 0 {
 		resolved.BlockDeviceMappings = amiFamily.DefaultBlockDeviceMappings()
 	}
+	resolved.BlockDeviceMappings = resolveSnapshots(resolved.BlockDeviceMappings, nodeClass.Status.Snapshots)
+	// Instance types are grouped by their volume sizes, so any of them can be used to resolve the sizes
+	resolved.BlockDeviceMappings = resolveVolumeSizes(resolved.BlockDeviceMappings, instanceTypes[0])
 	if resolved.MetadataOptions == nil {
 		resolved.MetadataOptions = amiFamily.DefaultMetadataOptions()
+	} else if resolved.MetadataOptions.HTTPProtocolIPv6 == nil {
+		// httpProtocolIPv6 isn't defaulted by the CRD, since its default depends on ipv6Only and the cluster's IP family
+		metadataOptions := *resolved.MetadataOptions
+		metadataOptions.HTTPProtocolIPv6 = amiFamily.DefaultMetadataOptions().HTTPProtocolIPv6
+		resolved.MetadataOptions = &metadataOptions
+	}
+	if burstable {
+		resolved.CPUCredits = nodeClass.Spec.CreditSpecification.CPUCredits
+	}
+	if cr, ok := lo.Find(nodeClass.Status.CapacityReservations, func(cr v1beta1.CapacityReservation) bool { return cr.ID == capacityReservationID }); ok {
+		resolved.CapacityReservationType = cr.ReservationType
 	}
 	return resolved, nil
 }
+
+// VolumeSize returns the size of the block device for an instance type with the given number of vCPUs and pods.
+// Block devices with a volume size policy are sized from it, while all others use their fixed volume size.
+func VolumeSize(blockDevice *v1beta1.BlockDevice, vcpus int64, pods int64) *resource.Quantity {
+	if blockDevice == nil {
+		return nil
+	}
+	if blockDevice.VolumeSizePolicy == nil {
+		return blockDevice.VolumeSize
+	}
+	policy := blockDevice.VolumeSizePolicy
+	var size float64
+	if policy.Base != nil {
+		size = policy.Base.AsApproximateFloat64()
+	}
+	if policy.PerVCPU != nil {
+		size += policy.PerVCPU.AsApproximateFloat64() * float64(vcpus)
+	}
+	if policy.PerPod != nil {
+		size += policy.PerPod.AsApproximateFloat64() * float64(pods)
+	}
+	if policy.Min != nil {
+		size = math.Max(size, policy.Min.AsApproximateFloat64())
+	}
+	if policy.Max != nil {
+		size = math.Min(size, policy.Max.AsApproximateFloat64())
+	}
+	// EBS volumes are sized in whole Gi
+	return resource.NewQuantity(int64(math.Ceil(size/math.Pow(2, 30)))*int64(math.Pow(2, 30)), resource.BinarySI)
+}
+
+// resolveVolumeSizes returns the block device mappings with the volumes that have a size policy sized for the instance type
+func resolveVolumeSizes(blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceType *cloudprovider.InstanceType) []*v1beta1.BlockDeviceMapping {
+	if !lo.SomeBy(blockDeviceMappings, hasVolumeSizePolicy) {
+		return blockDeviceMappings
+	}
+	return lo.Map(blockDeviceMappings, func(blockDeviceMapping *v1beta1.BlockDeviceMapping, _ int) *v1beta1.BlockDeviceMapping {
+		if !hasVolumeSizePolicy(blockDeviceMapping) {
+			return blockDeviceMapping
+		}
+		resolved := blockDeviceMapping.DeepCopy()
+		resolved.EBS.VolumeSize = VolumeSize(blockDeviceMapping.EBS, instanceType.Capacity.Cpu().Value(), instanceType.Capacity.Pods().Value())
+		resolved.EBS.VolumeSizePolicy = nil
+		return resolved
+	})
+}
+
+// resolveSnapshots returns the block device mappings with the volumes that select a snapshot created from the snapshot
+// that was resolved for their device
+func resolveSnapshots(blockDeviceMappings []*v1beta1.BlockDeviceMapping, snapshots []v1beta1.Snapshot) []*v1beta1.BlockDeviceMapping {
+	if !lo.SomeBy(blockDeviceMappings, hasSnapshotSelectorTerms) {
+		return blockDeviceMappings
+	}
+	return lo.Map(blockDeviceMappings, func(blockDeviceMapping *v1beta1.BlockDeviceMapping, _ int) *v1beta1.BlockDeviceMapping {
+		if !hasSnapshotSelectorTerms(blockDeviceMapping) {
+			return blockDeviceMapping
+		}
+		resolved := blockDeviceMapping.DeepCopy()
+		resolved.EBS.SnapshotSelectorTerms = nil
+		if snapshot, ok := lo.Find(snapshots, func(s v1beta1.Snapshot) bool { return s.DeviceName == aws.StringValue(blockDeviceMapping.DeviceName) }); ok {
+			resolved.EBS.SnapshotID = lo.ToPtr(snapshot.ID)
+		}
+		return resolved
+	})
+}
+
+func hasSnapshotSelectorTerms(blockDeviceMapping *v1beta1.BlockDeviceMapping) bool {
+	return blockDeviceMapping.EBS != nil && len(blockDeviceMapping.EBS.SnapshotSelectorTerms) > 0
+}
+
+// volumeSizesKey identifies the sizes that the volumes with a size policy resolve to for the instance type
+func volumeSizesKey(blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceType *cloudprovider.InstanceType) string {
+	return strings.Join(lo.FilterMap(resolveVolumeSizes(blockDeviceMappings, instanceType), func(blockDeviceMapping *v1beta1.BlockDeviceMapping, i int) (string, bool) {
+		return fmt.Sprintf("%d:%s", i, blockDeviceMapping.EBS.VolumeSize), hasVolumeSizePolicy(blockDeviceMappings[i])
+	}), ",")
+}
+
+func hasVolumeSizePolicy(blockDeviceMapping *v1beta1.BlockDeviceMapping) bool {
+	return blockDeviceMapping.EBS != nil && blockDeviceMapping.EBS.VolumeSizePolicy != nil
+}
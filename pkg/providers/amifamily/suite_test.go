@@ -33,6 +33,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
@@ -289,6 +290,104 @@ var _ = Describe("AMIProvider", func() {
 			Expect(amis).To(HaveLen(1))
 		})
 	})
+	Context("Alias", func() {
+		It("should resolve the latest AMIs when the alias version is latest", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2023
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "al2023@latest"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", version): amd64AMI,
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/standard/recommended/image_id", version):  arm64AMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(amd64AMI, arm64AMI))
+		})
+		It("should resolve the AMIs of a pinned release (AL2)", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "al2@v20240807"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2/amazon-eks-node-%[1]s-v20240807/image_id", version):             amd64AMI,
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2-gpu/amazon-eks-gpu-node-%[1]s-v20240807/image_id", version):     amd64NvidiaAMI,
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2-arm64/amazon-eks-arm64-node-%[1]s-v20240807/image_id", version): arm64AMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(4))
+		})
+		It("should resolve the AMIs of a pinned release (AL2023)", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2023
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "al2023@v20240807"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2023/x86_64/standard/amazon-eks-node-al2023-x86_64-standard-%[1]s-v20240807/image_id", version): amd64AMI,
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2023/arm64/standard/amazon-eks-node-al2023-arm64-standard-%[1]s-v20240807/image_id", version):   arm64AMI,
+				// The latest release shouldn't be resolved when the release is pinned
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", version): amd64NvidiaAMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(amd64AMI, arm64AMI))
+		})
+		It("should resolve the AMIs of a pinned release (Bottlerocket)", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "bottlerocket@v1.20.5"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/x86_64/1.20.5/image_id", version): amd64AMI,
+				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/arm64/1.20.5/image_id", version):  arm64AMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(amd64AMI, arm64AMI))
+		})
+		It("should resolve new AMIs when the pinned release changes", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2023
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "al2023@v20240807"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2023/x86_64/standard/amazon-eks-node-al2023-x86_64-standard-%[1]s-v20240807/image_id", version): amd64AMI,
+				fmt.Sprintf("/aws/service/eks/optimized-ami/%[1]s/amazon-linux-2023/x86_64/standard/amazon-eks-node-al2023-x86_64-standard-%[1]s-v20240901/image_id", version): amd64NvidiaAMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(amd64AMI))
+
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Alias: "al2023@v20240901"}}
+			amis, err = awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(amd64NvidiaAMI))
+		})
+	})
+	Context("SSM Parameter", func() {
+		It("should resolve the AMI stored in the ssm parameter", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-amis/arm64"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				"/golden-amis/arm64": arm64AMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal(arm64AMI))
+			Expect(amis[0].Requirements.Get(v1.LabelArchStable).Has(corev1beta1.ArchitectureArm64)).To(BeTrue())
+		})
+		It("should combine ssm parameter terms with other terms", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{
+				{SSMParameter: "/golden-amis/arm64"},
+				{ID: amd64AMI},
+			}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				"/golden-amis/arm64": arm64AMI,
+			}
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lo.Map(amis, func(a amifamily.AMI, _ int) string { return a.AmiID })).To(ConsistOf(amd64AMI, arm64AMI))
+		})
+		It("should fail when the ssm parameter doesn't exist", func() {
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{SSMParameter: "/golden-amis/missing"}}
+			awsEnv.SSMAPI.Parameters = map[string]string{
+				"/golden-amis/arm64": arm64AMI,
+			}
+			_, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).To(HaveOccurred())
+		})
+	})
 	Context("AMI Tag Requirements", func() {
 		var img *ec2.Image
 		BeforeEach(func() {
@@ -329,6 +428,254 @@ var _ = Describe("AMIProvider", func() {
 			}))
 		})
 	})
+	Context("AMI Deprecation", func() {
+		var current, deprecated *ec2.Image
+		BeforeEach(func() {
+			current = &ec2.Image{
+				Name:         aws.String(amd64AMI),
+				ImageId:      aws.String("amd64-ami-id"),
+				CreationDate: aws.String(time.Now().Add(-time.Hour).Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+			}
+			deprecated = &ec2.Image{
+				Name:            aws.String(amd64AMI),
+				ImageId:         aws.String("amd64-deprecated-ami-id"),
+				CreationDate:    aws.String(time.Now().Format(time.RFC3339)),
+				DeprecationTime: aws.String(time.Now().Add(-time.Minute).Format(time.RFC3339)),
+				Architecture:    aws.String("x86_64"),
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Name: amd64AMI}}
+		})
+		It("should include deprecated images when describing images", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{current}})
+			_, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(awsEnv.EC2API.CalledWithDescribeImagesInput.Len()).To(Equal(1))
+			Expect(aws.BoolValue(awsEnv.EC2API.CalledWithDescribeImagesInput.Pop().IncludeDeprecated)).To(BeTrue())
+		})
+		It("should prefer an image that isn't deprecated over a newer deprecated image", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{deprecated, current}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("amd64-ami-id"))
+			Expect(amis[0].Deprecated).To(BeFalse())
+		})
+		It("should resolve a deprecated image when it's the only image that matches", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{deprecated}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].AmiID).To(Equal("amd64-deprecated-ami-id"))
+			Expect(amis[0].Deprecated).To(BeTrue())
+			Expect(amis[0].DeprecationDate).To(Equal(aws.StringValue(deprecated.DeprecationTime)))
+		})
+		It("should not consider an image deprecated before its deprecation time", func() {
+			current.DeprecationTime = aws.String(time.Now().Add(time.Hour).Format(time.RFC3339))
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{current}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Deprecated).To(BeFalse())
+		})
+		It("should consider an image deprecated once the deprecation time has passed", func() {
+			current.DeprecationTime = aws.String(awsEnv.Clock.Now().Add(time.Hour).Format(time.RFC3339))
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{current}})
+			awsEnv.Clock.Step(2 * time.Hour)
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Deprecated).To(BeTrue())
+		})
+		It("should get a deprecated image by id", func() {
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{current, deprecated}})
+			ami, err := awsEnv.AMIProvider.Get(ctx, "amd64-deprecated-ami-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ami.AmiID).To(Equal("amd64-deprecated-ami-id"))
+			Expect(ami.CreationDate).To(Equal(aws.StringValue(deprecated.CreationDate)))
+			Expect(ami.Deprecated).To(BeTrue())
+		})
+		It("should sort deprecated amis after amis that aren't deprecated", func() {
+			amis := amifamily.AMIs{
+				{
+					Name:         "test-ami-1",
+					AmiID:        "test-ami-1-id",
+					CreationDate: "2021-08-31T00:12:42.000Z",
+					Deprecated:   true,
+					Requirements: scheduling.NewRequirements(),
+				},
+				{
+					Name:         "test-ami-2",
+					AmiID:        "test-ami-2-id",
+					CreationDate: "2021-08-31T00:10:42.000Z",
+					Requirements: scheduling.NewRequirements(),
+				},
+			}
+			amis.Sort()
+			Expect(amis[0].AmiID).To(Equal("test-ami-2-id"))
+			Expect(amis[1].AmiID).To(Equal("test-ami-1-id"))
+		})
+	})
+	Context("AMI Rollout", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.AMIRollout = &v1beta1.AMIRollout{CanaryPercentage: 50, PromotionThreshold: 1}
+			nodeClass.Status.AMIs = []v1beta1.AMI{{ID: "ami-active"}}
+			nodeClass.Status.AMIRollout = &v1beta1.AMIRolloutStatus{
+				AMIs:  []v1beta1.AMI{{ID: "ami-canary"}},
+				Phase: v1beta1.AMIRolloutPhaseProgressing,
+			}
+		})
+		It("should launch a share of NodeClaims with the canary AMIs while the rollout is progressing", func() {
+			canaries := 0
+			for i := 0; i < 1000; i++ {
+				amis := amifamily.AMIsForNodeClaim(nodeClass, coretest.NodeClaim())
+				if amis[0].ID == "ami-canary" {
+					// Canary NodeClaims fall back to the active AMIs
+					Expect(amis).To(HaveLen(2))
+					Expect(amis[1].ID).To(Equal("ami-active"))
+					canaries++
+				} else {
+					Expect(amis).To(HaveLen(1))
+				}
+			}
+			Expect(canaries).To(BeNumerically("~", 500, 100))
+		})
+		It("should consistently assign a NodeClaim to the canary", func() {
+			nodeClaim := coretest.NodeClaim()
+			amis := amifamily.AMIsForNodeClaim(nodeClass, nodeClaim)
+			for i := 0; i < 10; i++ {
+				Expect(amifamily.AMIsForNodeClaim(nodeClass, nodeClaim)).To(Equal(amis))
+			}
+		})
+		It("should only launch with the active AMIs when the rollout isn't progressing", func() {
+			for _, phase := range []string{v1beta1.AMIRolloutPhasePromoted, v1beta1.AMIRolloutPhaseRolledBack} {
+				nodeClass.Status.AMIRollout.Phase = phase
+				Expect(amifamily.CanaryAMIs(nodeClass)).To(BeEmpty())
+				for i := 0; i < 100; i++ {
+					Expect(amifamily.AMIsForNodeClaim(nodeClass, coretest.NodeClaim())).To(Equal(nodeClass.Status.AMIs))
+				}
+			}
+		})
+		It("should only launch with the active AMIs when the rollout policy is removed", func() {
+			nodeClass.Spec.AMIRollout = nil
+			Expect(amifamily.CanaryAMIs(nodeClass)).To(BeEmpty())
+		})
+	})
+	Context("AMI Attribute Requirements", func() {
+		var img *ec2.Image
+		BeforeEach(func() {
+			img = &ec2.Image{
+				Name:         aws.String(amd64AMI),
+				ImageId:      aws.String("amd64-ami-id"),
+				CreationDate: aws.String(time.Now().Format(time.RFC3339)),
+				Architecture: aws.String("x86_64"),
+			}
+			nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{ID: "amd64-ami-id"}}
+		})
+		It("should require UEFI support for UEFI images", func() {
+			img.BootMode = aws.String(ec2.BootModeValuesUefi)
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{img}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Requirements).To(Equal(scheduling.NewRequirements(
+				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+				scheduling.NewRequirement(v1beta1.LabelInstanceUEFISupported, v1.NodeSelectorOpIn, "true"),
+			)))
+		})
+		It("should not add a boot mode requirement for images that prefer UEFI", func() {
+			img.BootMode = aws.String(ec2.BootModeValuesUefiPreferred)
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{img}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Requirements.Has(v1beta1.LabelInstanceUEFISupported)).To(BeFalse())
+		})
+		It("should exclude instance types that require ENA for images without ENA support", func() {
+			img.EnaSupport = aws.Bool(false)
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{img}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Requirements).To(Equal(scheduling.NewRequirements(
+				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+				scheduling.NewRequirement(v1beta1.LabelInstanceENASupport, v1.NodeSelectorOpNotIn, ec2.EnaSupportRequired),
+			)))
+		})
+		It("should resolve requirements from requirement tags", func() {
+			img.Tags = []*ec2.Tag{
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "instance-category"), Value: aws.String("g, p")},
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "instance-gpu-manufacturer"), Value: aws.String("!amd")},
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "instance-gpu-count"), Value: aws.String("*")},
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "instance-accelerator-count"), Value: aws.String("")},
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "instance-ebs-nvme-support"), Value: aws.String("!required")},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{img}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Requirements).To(Equal(scheduling.NewRequirements(
+				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+				scheduling.NewRequirement(v1beta1.LabelInstanceCategory, v1.NodeSelectorOpIn, "g", "p"),
+				scheduling.NewRequirement(v1beta1.LabelInstanceGPUManufacturer, v1.NodeSelectorOpNotIn, "amd"),
+				scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpExists),
+				scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpDoesNotExist),
+				scheduling.NewRequirement(v1beta1.LabelInstanceEBSNVMeSupport, v1.NodeSelectorOpNotIn, "required"),
+			)))
+		})
+		It("should ignore requirement tags for labels that aren't well known", func() {
+			img.Tags = []*ec2.Tag{
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "unknown-label"), Value: aws.String("value")},
+				{Key: aws.String(v1beta1.TagAMIRequirementPrefix + "ec2nodeclass"), Value: aws.String("default")},
+			}
+			awsEnv.EC2API.DescribeImagesOutput.Set(&ec2.DescribeImagesOutput{Images: []*ec2.Image{img}})
+			amis, err := awsEnv.AMIProvider.List(ctx, nodeClass)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amis).To(HaveLen(1))
+			Expect(amis[0].Requirements).To(Equal(scheduling.NewRequirements(
+				scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+			)))
+		})
+		It("should map accelerated and non-accelerated AMIs to their instance types", func() {
+			gpuInstanceType := &cloudprovider.InstanceType{
+				Name: "g4dn.8xlarge",
+				Requirements: scheduling.NewRequirements(
+					scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+					scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpIn, "1"),
+					scheduling.NewRequirement(v1beta1.LabelInstanceUEFISupported, v1.NodeSelectorOpIn, "true"),
+				),
+			}
+			instanceType := &cloudprovider.InstanceType{
+				Name: "m5.large",
+				Requirements: scheduling.NewRequirements(
+					scheduling.NewRequirement(v1.LabelArchStable, v1.NodeSelectorOpIn, corev1beta1.ArchitectureAmd64),
+					scheduling.NewRequirement(v1beta1.LabelInstanceGPUCount, v1.NodeSelectorOpDoesNotExist),
+					scheduling.NewRequirement(v1beta1.LabelInstanceUEFISupported, v1.NodeSelectorOpIn, "false"),
+				),
+			}
+			amis := []v1beta1.AMI{
+				{
+					ID: "ami-accelerated",
+					Requirements: []v1.NodeSelectorRequirement{
+						{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.ArchitectureAmd64}},
+						{Key: v1beta1.LabelInstanceGPUCount, Operator: v1.NodeSelectorOpExists},
+						{Key: v1beta1.LabelInstanceUEFISupported, Operator: v1.NodeSelectorOpIn, Values: []string{"true"}},
+					},
+				},
+				{
+					ID: "ami-standard",
+					Requirements: []v1.NodeSelectorRequirement{
+						{Key: v1.LabelArchStable, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.ArchitectureAmd64}},
+						{Key: v1beta1.LabelInstanceGPUCount, Operator: v1.NodeSelectorOpDoesNotExist},
+					},
+				},
+			}
+			mapped := amifamily.MapToInstanceTypes([]*cloudprovider.InstanceType{gpuInstanceType, instanceType}, amis)
+			Expect(mapped).To(HaveLen(2))
+			Expect(mapped["ami-accelerated"]).To(ConsistOf(gpuInstanceType))
+			Expect(mapped["ami-standard"]).To(ConsistOf(instanceType))
+		})
+	})
 	Context("AMI Selectors", func() {
 		// When you tag public or shared resources, the tags you assign are available only to your AWS account; no other AWS account will have access to those tags
 		// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/Using_Tags.html#tag-restrictions
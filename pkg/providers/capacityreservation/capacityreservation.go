@@ -0,0 +1,172 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacityreservation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+const (
+	// CapacityBlockTerminationLeadTime is how long before the end time of a capacity block that EC2 begins
+	// terminating the instances running in it
+	CapacityBlockTerminationLeadTime = 30 * time.Minute
+	// CapacityBlockDrainLeadTime is how long before the end time of a capacity block that Karpenter begins draining
+	// the nodes running in it, giving pods time to be rescheduled before EC2 terminates the instances
+	CapacityBlockDrainLeadTime = CapacityBlockTerminationLeadTime + 10*time.Minute
+)
+
+type Provider interface {
+	List(context.Context, *v1beta1.EC2NodeClass) ([]*ec2.CapacityReservation, error)
+}
+
+type DefaultProvider struct {
+	sync.Mutex
+	ec2api ec2iface.EC2API
+	cache  *cache.Cache
+	cm     *pretty.ChangeMonitor
+}
+
+func NewDefaultProvider(ec2api ec2iface.EC2API, cache *cache.Cache) *DefaultProvider {
+	return &DefaultProvider{
+		ec2api: ec2api,
+		cm:     pretty.NewChangeMonitor(),
+		cache:  cache,
+	}
+}
+
+func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) ([]*ec2.CapacityReservation, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if len(nodeClass.Spec.CapacityReservationSelectorTerms) == 0 {
+		return nil, nil
+	}
+	capacityReservations, err := p.getCapacityReservations(ctx, getQueries(nodeClass.Spec.CapacityReservationSelectorTerms))
+	if err != nil {
+		return nil, err
+	}
+	if p.cm.HasChanged(fmt.Sprintf("capacity-reservations/%s", nodeClass.Name), lo.Map(capacityReservations, func(cr *ec2.CapacityReservation, _ int) string {
+		return aws.StringValue(cr.CapacityReservationId)
+	})) {
+		log.FromContext(ctx).
+			WithValues("capacity-reservations", lo.Map(capacityReservations, func(cr *ec2.CapacityReservation, _ int) string {
+				return aws.StringValue(cr.CapacityReservationId)
+			})).
+			V(1).Info("discovered capacity reservations")
+	}
+	return capacityReservations, nil
+}
+
+func (p *DefaultProvider) getCapacityReservations(ctx context.Context, queries []*ec2.DescribeCapacityReservationsInput) ([]*ec2.CapacityReservation, error) {
+	hash, err := hashstructure.Hash(queries, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	if err != nil {
+		return nil, err
+	}
+	if cr, ok := p.cache.Get(fmt.Sprint(hash)); ok {
+		// Ensure what's returned from this function is a shallow-copy of the slice (not a deep-copy of the data itself)
+		// so that modifications to the ordering of the data don't affect the original
+		return append([]*ec2.CapacityReservation{}, cr.([]*ec2.CapacityReservation)...), nil
+	}
+	capacityReservations := map[string]*ec2.CapacityReservation{}
+	for _, query := range queries {
+		if err := p.ec2api.DescribeCapacityReservationsPagesWithContext(ctx, query, func(page *ec2.DescribeCapacityReservationsOutput, _ bool) bool {
+			for i := range page.CapacityReservations {
+				capacityReservations[lo.FromPtr(page.CapacityReservations[i].CapacityReservationId)] = page.CapacityReservations[i]
+			}
+			return true
+		}); err != nil {
+			return nil, fmt.Errorf("describing capacity reservations %+v, %w", queries, err)
+		}
+	}
+	p.cache.SetDefault(fmt.Sprint(hash), lo.Values(capacityReservations))
+	return lo.Values(capacityReservations), nil
+}
+
+// IsActive returns whether instances can be launched into the capacity reservation at the given time. Capacity blocks
+// are only active between their start time and the time at which Karpenter begins draining them.
+func IsActive(capacityReservation v1beta1.CapacityReservation, now time.Time) bool {
+	if capacityReservation.StartTime != nil && now.Before(capacityReservation.StartTime.Time) {
+		return false
+	}
+	if drainTime, ok := DrainTime(capacityReservation); ok && !now.Before(drainTime) {
+		return false
+	}
+	return capacityReservation.EndTime == nil || now.Before(capacityReservation.EndTime.Time)
+}
+
+// DrainTime returns the time at which nodes running in the capacity reservation should be drained. Only capacity
+// blocks are drained, since instances in other reservations continue running as on-demand instances once the
+// reservation expires.
+func DrainTime(capacityReservation v1beta1.CapacityReservation) (time.Time, bool) {
+	if capacityReservation.ReservationType != v1beta1.CapacityReservationTypeCapacityBlock || capacityReservation.EndTime == nil {
+		return time.Time{}, false
+	}
+	return capacityReservation.EndTime.Add(-CapacityBlockDrainLeadTime), true
+}
+
+// getQueries returns the set of DescribeCapacityReservations requests needed to resolve the selector terms.
+// Scheduled reservations are returned alongside active ones so that capacity blocks are discovered ahead of their
+// start time. Instances can't be launched into any other reservation state.
+func getQueries(terms []v1beta1.CapacityReservationSelectorTerm) (res []*ec2.DescribeCapacityReservationsInput) {
+	stateFilter := &ec2.Filter{Name: aws.String("state"), Values: aws.StringSlice([]string{ec2.CapacityReservationStateActive, ec2.CapacityReservationStateScheduled})}
+	var ids []*string
+	for _, term := range terms {
+		switch {
+		case term.ID != "":
+			ids = append(ids, aws.String(term.ID))
+		default:
+			filters := []*ec2.Filter{stateFilter}
+			if term.OwnerID != "" {
+				filters = append(filters, &ec2.Filter{
+					Name:   aws.String("owner-id"),
+					Values: []*string{aws.String(term.OwnerID)},
+				})
+			}
+			for k, v := range term.Tags {
+				if v == "*" {
+					filters = append(filters, &ec2.Filter{
+						Name:   aws.String("tag-key"),
+						Values: []*string{aws.String(k)},
+					})
+				} else {
+					filters = append(filters, &ec2.Filter{
+						Name:   aws.String(fmt.Sprintf("tag:%s", k)),
+						Values: []*string{aws.String(v)},
+					})
+				}
+			}
+			res = append(res, &ec2.DescribeCapacityReservationsInput{Filters: filters})
+		}
+	}
+	if len(ids) > 0 {
+		res = append(res, &ec2.DescribeCapacityReservationsInput{CapacityReservationIds: ids, Filters: []*ec2.Filter{stateFilter}})
+	}
+	return res
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -52,6 +53,8 @@ import (
 const (
 	instanceTypeFlexibilityThreshold = 5 // falling back to on-demand without flexibility risks insufficient capacity errors
 	maxInstanceTypes                 = 60
+	// maxAllowedInstanceTypes is the maximum number of instance types that can be allowed by EC2 instance requirements
+	maxAllowedInstanceTypes = 400
 )
 
 var (
@@ -66,7 +69,9 @@ type Provider interface {
 	Get(context.Context, string) (*Instance, error)
 	List(context.Context) ([]*Instance, error)
 	Delete(context.Context, string) error
-	CreateTags(context.Context, string, map[string]string) error
+	CreateTags(context.Context, string, map[string]string, ...string) error
+	DeleteTags(context.Context, string, []string, ...string) error
+	GetTags(context.Context, string, ...string) (map[string]map[string]string, error)
 }
 
 type DefaultProvider struct {
@@ -98,22 +103,27 @@ func (p *DefaultProvider) Create(ctx context.Context, nodeClass *v1beta1.EC2Node
 	if !schedulingRequirements.HasMinValues() {
 		instanceTypes = p.filterInstanceTypes(nodeClaim, instanceTypes)
 	}
-	instanceTypes, err := cloudprovider.InstanceTypes(instanceTypes).Truncate(schedulingRequirements, maxInstanceTypes)
-	if err != nil {
-		return nil, fmt.Errorf("truncating instance types, %w", err)
+	// Attribute-based instance type selection describes the compatible instance types as instance requirements, so
+	// they don't need to be truncated to the number of overrides that EC2 Fleet accepts
+	if !isAttributeBased(nodeClass) {
+		var err error
+		instanceTypes, err = truncateInstanceTypes(nodeClass, schedulingRequirements, instanceTypes, maxInstanceTypes)
+		if err != nil {
+			return nil, fmt.Errorf("truncating instance types, %w", err)
+		}
 	}
-	tags := getTags(ctx, nodeClass, nodeClaim)
-	fleetInstance, err := p.launchInstance(ctx, nodeClass, nodeClaim, instanceTypes, tags)
+	tags := getTags(ctx, nodeClass, nodeClaim, p.getCapacityType(nodeClaim, instanceTypes), instanceTypes)
+	fleetInstance, launchTemplate, err := p.launchInstance(ctx, nodeClass, nodeClaim, instanceTypes, tags)
 	if awserrors.IsLaunchTemplateNotFound(err) {
 		// retry once if launch template is not found. This allows karpenter to generate a new LT if the
 		// cache was out-of-sync on the first try
-		fleetInstance, err = p.launchInstance(ctx, nodeClass, nodeClaim, instanceTypes, tags)
+		fleetInstance, launchTemplate, err = p.launchInstance(ctx, nodeClass, nodeClaim, instanceTypes, tags)
 	}
 	if err != nil {
 		return nil, err
 	}
 	efaEnabled := lo.Contains(lo.Keys(nodeClaim.Spec.Resources.Requests), v1beta1.ResourceEFA)
-	return NewInstanceFromFleet(fleetInstance, tags, efaEnabled), nil
+	return NewInstanceFromFleet(fleetInstance, tags, efaEnabled, launchTemplate), nil
 }
 
 func (p *DefaultProvider) Get(ctx context.Context, id string) (*Instance, error) {
@@ -184,12 +194,14 @@ func (p *DefaultProvider) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (p *DefaultProvider) CreateTags(ctx context.Context, id string, tags map[string]string) error {
+// CreateTags tags the instance, along with any of its resources that are passed, e.g. its root volume. Requests are
+// batched with those of other instances that apply the same tags.
+func (p *DefaultProvider) CreateTags(ctx context.Context, id string, tags map[string]string, resourceIDs ...string) error {
 	ec2Tags := lo.MapToSlice(tags, func(key, value string) *ec2.Tag {
 		return &ec2.Tag{Key: aws.String(key), Value: aws.String(value)}
 	})
-	if _, err := p.ec2api.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
-		Resources: aws.StringSlice([]string{id}),
+	if _, err := p.ec2Batcher.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: aws.StringSlice(append([]string{id}, resourceIDs...)),
 		Tags:      ec2Tags,
 	}); err != nil {
 		if awserrors.IsNotFound(err) {
@@ -200,17 +212,52 @@ func (p *DefaultProvider) CreateTags(ctx context.Context, id string, tags map[st
 	return nil
 }
 
-func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, tags map[string]string) (*ec2.CreateFleetInstance, error) {
+// DeleteTags removes the tag keys from the instance, along with any of its resources that are passed
+func (p *DefaultProvider) DeleteTags(ctx context.Context, id string, keys []string, resourceIDs ...string) error {
+	ec2Tags := lo.Map(keys, func(key string, _ int) *ec2.Tag {
+		return &ec2.Tag{Key: aws.String(key)}
+	})
+	if _, err := p.ec2Batcher.DeleteTags(ctx, &ec2.DeleteTagsInput{
+		Resources: aws.StringSlice(append([]string{id}, resourceIDs...)),
+		Tags:      ec2Tags,
+	}); err != nil {
+		if awserrors.IsNotFound(err) {
+			return cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("untagging instance, %w", err))
+		}
+		return fmt.Errorf("untagging instance, %w", err)
+	}
+	return nil
+}
+
+// GetTags returns the tags of the instance, along with any of its resources that are passed, keyed by resource id
+func (p *DefaultProvider) GetTags(ctx context.Context, id string, resourceIDs ...string) (map[string]map[string]string, error) {
+	tags := map[string]map[string]string{}
+	if err := p.ec2api.DescribeTagsPagesWithContext(ctx, &ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{{Name: aws.String("resource-id"), Values: aws.StringSlice(append([]string{id}, resourceIDs...))}},
+	}, func(page *ec2.DescribeTagsOutput, _ bool) bool {
+		for _, tag := range page.Tags {
+			tags[aws.StringValue(tag.ResourceId)] = lo.Assign(tags[aws.StringValue(tag.ResourceId)], map[string]string{aws.StringValue(tag.Key): aws.StringValue(tag.Value)})
+		}
+		return true
+	}); err != nil {
+		return nil, fmt.Errorf("describing tags, %w", err)
+	}
+	return tags, nil
+}
+
+// launchInstance creates a fleet request for a single instance. The launch template that the instance was launched with
+// is returned alongside the fleet instance, since it determines the capacity reservation and placement of the instance.
+func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, tags map[string]string) (*ec2.CreateFleetInstance, *launchtemplate.LaunchTemplate, error) {
 	capacityType := p.getCapacityType(nodeClaim, instanceTypes)
 	zonalSubnets, err := p.subnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, capacityType)
 	if err != nil {
-		return nil, fmt.Errorf("getting subnets, %w", err)
+		return nil, nil, fmt.Errorf("getting subnets, %w", err)
 	}
 
 	// Get Launch Template Configs, which may differ due to GPU or Architecture requirements
-	launchTemplateConfigs, err := p.getLaunchTemplateConfigs(ctx, nodeClass, nodeClaim, instanceTypes, zonalSubnets, capacityType, tags)
+	launchTemplateConfigs, launchTemplates, err := p.getLaunchTemplateConfigs(ctx, nodeClass, nodeClaim, instanceTypes, zonalSubnets, capacityType, tags)
 	if err != nil {
-		return nil, fmt.Errorf("getting launch template configs, %w", err)
+		return nil, nil, fmt.Errorf("getting launch template configs, %w", err)
 	}
 	if err := p.checkODFallback(nodeClaim, instanceTypes, launchTemplateConfigs); err != nil {
 		log.FromContext(ctx).Error(err, "failed while checking on-demand fallback")
@@ -221,7 +268,7 @@ func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1
 		Context:               nodeClass.Spec.Context,
 		LaunchTemplateConfigs: launchTemplateConfigs,
 		TargetCapacitySpecification: &ec2.TargetCapacitySpecificationRequest{
-			DefaultTargetCapacityType: aws.String(capacityType),
+			DefaultTargetCapacityType: aws.String(getTargetCapacityType(capacityType, launchTemplates)),
 			TotalTargetCapacity:       aws.Int64(1),
 		},
 		TagSpecifications: []*ec2.TagSpecification{
@@ -231,41 +278,110 @@ func (p *DefaultProvider) launchInstance(ctx context.Context, nodeClass *v1beta1
 		},
 	}
 	if capacityType == corev1beta1.CapacityTypeSpot {
-		createFleetInput.SpotOptions = &ec2.SpotOptionsRequest{AllocationStrategy: aws.String(ec2.SpotAllocationStrategyPriceCapacityOptimized)}
-	} else {
-		createFleetInput.OnDemandOptions = &ec2.OnDemandOptionsRequest{AllocationStrategy: aws.String(ec2.FleetOnDemandAllocationStrategyLowestPrice)}
+		createFleetInput.SpotOptions = &ec2.SpotOptionsRequest{AllocationStrategy: aws.String(getSpotAllocationStrategy(nodeClass))}
+	} else if aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType) != v1beta1.CapacityReservationTypeCapacityBlock {
+		createFleetInput.OnDemandOptions = &ec2.OnDemandOptionsRequest{AllocationStrategy: aws.String(getOnDemandAllocationStrategy(nodeClass))}
 	}
 
 	createFleetOutput, err := p.ec2Batcher.CreateFleet(ctx, createFleetInput)
-	p.subnetProvider.UpdateInflightIPs(createFleetInput, createFleetOutput, instanceTypes, lo.Values(zonalSubnets), capacityType)
+	p.subnetProvider.UpdateInflightIPs(ctx, createFleetInput, createFleetOutput, instanceTypes, lo.Values(zonalSubnets), capacityType)
 	if err != nil {
 		if awserrors.IsLaunchTemplateNotFound(err) {
 			for _, lt := range launchTemplateConfigs {
 				p.launchTemplateProvider.InvalidateCache(ctx, aws.StringValue(lt.LaunchTemplateSpecification.LaunchTemplateName), aws.StringValue(lt.LaunchTemplateSpecification.LaunchTemplateId))
 			}
-			return nil, fmt.Errorf("creating fleet %w", err)
+			return nil, nil, fmt.Errorf("creating fleet %w", err)
 		}
 		var reqFailure awserr.RequestFailure
 		if errors.As(err, &reqFailure) {
-			return nil, fmt.Errorf("creating fleet %w (%s)", err, reqFailure.RequestID())
+			return nil, nil, fmt.Errorf("creating fleet %w (%s)", err, reqFailure.RequestID())
 		}
-		return nil, fmt.Errorf("creating fleet %w", err)
+		return nil, nil, fmt.Errorf("creating fleet %w", err)
 	}
-	p.updateUnavailableOfferingsCache(ctx, createFleetOutput.Errors, capacityType)
+	p.updateUnavailableOfferingsCache(ctx, nodeClass, createFleetOutput.Errors, capacityType)
 	if len(createFleetOutput.Instances) == 0 || len(createFleetOutput.Instances[0].InstanceIds) == 0 {
-		return nil, combineFleetErrors(createFleetOutput.Errors)
+		return nil, nil, combineFleetErrors(createFleetOutput.Errors)
+	}
+	fleetInstance := createFleetOutput.Instances[0]
+	var launchTemplate *launchtemplate.LaunchTemplate
+	if fleetInstance.LaunchTemplateAndOverrides != nil && fleetInstance.LaunchTemplateAndOverrides.LaunchTemplateSpecification != nil {
+		launchTemplate = launchTemplates[aws.StringValue(fleetInstance.LaunchTemplateAndOverrides.LaunchTemplateSpecification.LaunchTemplateName)]
+	}
+	return fleetInstance, launchTemplate, nil
+}
+
+// getTargetCapacityType returns the capacity type that the fleet request should target. Capacity reservations are
+// consumed by on-demand launches that target them through the launch template, with the exception of capacity blocks
+// which must be launched with their own capacity type.
+func getTargetCapacityType(capacityType string, launchTemplates map[string]*launchtemplate.LaunchTemplate) string {
+	if capacityType != v1beta1.CapacityTypeReserved {
+		return capacityType
+	}
+	if lo.SomeBy(lo.Values(launchTemplates), isCapacityBlock) {
+		return v1beta1.CapacityReservationTypeCapacityBlock
+	}
+	return corev1beta1.CapacityTypeOnDemand
+}
+
+// getSpotAllocationStrategy returns the spot allocation strategy configured on the EC2NodeClass, defaulting to
+// price-capacity-optimized
+func getSpotAllocationStrategy(nodeClass *v1beta1.EC2NodeClass) string {
+	if nodeClass.Spec.AllocationStrategy == nil || nodeClass.Spec.AllocationStrategy.Spot == nil {
+		return ec2.SpotAllocationStrategyPriceCapacityOptimized
 	}
-	return createFleetOutput.Instances[0], nil
+	return aws.StringValue(nodeClass.Spec.AllocationStrategy.Spot)
+}
+
+// getOnDemandAllocationStrategy returns the on-demand allocation strategy configured on the EC2NodeClass, defaulting
+// to lowest-price
+func getOnDemandAllocationStrategy(nodeClass *v1beta1.EC2NodeClass) string {
+	if nodeClass.Spec.AllocationStrategy == nil || nodeClass.Spec.AllocationStrategy.OnDemand == nil {
+		return ec2.FleetOnDemandAllocationStrategyLowestPrice
+	}
+	return aws.StringValue(nodeClass.Spec.AllocationStrategy.OnDemand)
+}
+
+// isAttributeBased returns whether the EC2NodeClass passes instance types to EC2 Fleet as instance requirements
+func isAttributeBased(nodeClass *v1beta1.EC2NodeClass) bool {
+	return nodeClass.Spec.AllocationStrategy != nil &&
+		aws.StringValue(nodeClass.Spec.AllocationStrategy.InstanceTypeSelection) == v1beta1.InstanceTypeSelectionAttributeBased
 }
 
-func getTags(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim) map[string]string {
+func isCapacityBlock(launchTemplate *launchtemplate.LaunchTemplate) bool {
+	return launchTemplate.CapacityReservationType == v1beta1.CapacityReservationTypeCapacityBlock
+}
+
+func getTags(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim, capacityType string, instanceTypes []*cloudprovider.InstanceType) map[string]string {
 	staticTags := map[string]string{
 		fmt.Sprintf("kubernetes.io/cluster/%s", options.FromContext(ctx).ClusterName): "owned",
 		corev1beta1.NodePoolLabelKey:       nodeClaim.Labels[corev1beta1.NodePoolLabelKey],
 		corev1beta1.ManagedByAnnotationKey: options.FromContext(ctx).ClusterName,
 		v1beta1.LabelNodeClass:             nodeClass.Name,
 	}
-	return lo.Assign(nodeClass.Spec.Tags, staticTags)
+	return lo.Assign(v1beta1.ResolveTags(nodeClass.Spec.Tags, getTagTemplateData(nodeClaim, capacityType, instanceTypes)), staticTags)
+}
+
+// getTagTemplateData returns the data that is known before the instance is launched. The zone and instance type are
+// only known when they're constrained to a single value, since otherwise they're chosen by EC2 Fleet.
+func getTagTemplateData(nodeClaim *corev1beta1.NodeClaim, capacityType string, instanceTypes []*cloudprovider.InstanceType) v1beta1.TagTemplateData {
+	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...)
+	singleValue := func(key string) string {
+		if requirement := requirements.Get(key); requirement.Operator() == v1.NodeSelectorOpIn && requirement.Len() == 1 {
+			return requirement.Any()
+		}
+		return ""
+	}
+	data := v1beta1.TagTemplateData{
+		NodePool:     nodeClaim.Labels[corev1beta1.NodePoolLabelKey],
+		Zone:         singleValue(v1.LabelTopologyZone),
+		CapacityType: capacityType,
+		InstanceType: singleValue(v1.LabelInstanceTypeStable),
+		Labels:       nodeClaim.Labels,
+	}
+	if len(instanceTypes) == 1 {
+		data.InstanceType = instanceTypes[0].Name
+	}
+	return data
 }
 
 func (p *DefaultProvider) checkODFallback(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, launchTemplateConfigs []*ec2.FleetLaunchTemplateConfigRequest) error {
@@ -291,16 +407,40 @@ func (p *DefaultProvider) checkODFallback(nodeClaim *corev1beta1.NodeClaim, inst
 	return nil
 }
 
+// getLaunchTemplateConfigs returns the launch template configs for the fleet request along with the launch templates
+// they reference, keyed by launch template name.
 func (p *DefaultProvider) getLaunchTemplateConfigs(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, nodeClaim *corev1beta1.NodeClaim,
-	instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, capacityType string, tags map[string]string) ([]*ec2.FleetLaunchTemplateConfigRequest, error) {
+	instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, capacityType string, tags map[string]string) ([]*ec2.FleetLaunchTemplateConfigRequest, map[string]*launchtemplate.LaunchTemplate, error) {
 	var launchTemplateConfigs []*ec2.FleetLaunchTemplateConfigRequest
+	launchTemplatesByName := map[string]*launchtemplate.LaunchTemplate{}
+	var priorities map[string]int32
+	if nodeClass.Spec.AllocationStrategy != nil {
+		priorities = nodeClass.Spec.AllocationStrategy.InstanceTypePriorities
+	}
 	launchTemplates, err := p.launchTemplateProvider.EnsureAll(ctx, nodeClass, nodeClaim, instanceTypes, capacityType, tags)
 	if err != nil {
-		return nil, fmt.Errorf("getting launch templates, %w", err)
+		return nil, nil, fmt.Errorf("getting launch templates, %w", err)
 	}
 	for _, launchTemplate := range launchTemplates {
+		zones := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(v1.LabelTopologyZone)
+		if launchTemplate.CapacityReservationID != "" {
+			// A capacity reservation can only be consumed in the zone that it was created in
+			cr, ok := lo.Find(nodeClass.Status.CapacityReservations, func(cr v1beta1.CapacityReservation) bool { return cr.ID == launchTemplate.CapacityReservationID })
+			if !ok {
+				continue
+			}
+			zones = zones.Intersection(scheduling.NewRequirement(v1.LabelTopologyZone, v1.NodeSelectorOpIn, cr.Zone))
+		}
+		var overrides []*ec2.FleetLaunchTemplateOverridesRequest
+		// Launch templates that target a capacity reservation can only launch the reserved instance type, so their
+		// instance types are always passed explicitly
+		if isAttributeBased(nodeClass) && launchTemplate.CapacityReservationID == "" {
+			overrides = p.getAttributeBasedOverrides(nodeClaim, launchTemplate.InstanceTypes, zonalSubnets, zones, capacityType, launchTemplate.ImageID)
+		} else {
+			overrides = p.getOverrides(launchTemplate.InstanceTypes, zonalSubnets, zones, capacityType, launchTemplate.ImageID, priorities)
+		}
 		launchTemplateConfig := &ec2.FleetLaunchTemplateConfigRequest{
-			Overrides: p.getOverrides(launchTemplate.InstanceTypes, zonalSubnets, scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(v1.LabelTopologyZone), capacityType, launchTemplate.ImageID),
+			Overrides: overrides,
 			LaunchTemplateSpecification: &ec2.FleetLaunchTemplateSpecificationRequest{
 				LaunchTemplateName: aws.String(launchTemplate.Name),
 				Version:            aws.String("$Latest"),
@@ -308,17 +448,29 @@ func (p *DefaultProvider) getLaunchTemplateConfigs(ctx context.Context, nodeClas
 		}
 		if len(launchTemplateConfig.Overrides) > 0 {
 			launchTemplateConfigs = append(launchTemplateConfigs, launchTemplateConfig)
+			launchTemplatesByName[launchTemplate.Name] = launchTemplate
 		}
 	}
 	if len(launchTemplateConfigs) == 0 {
-		return nil, fmt.Errorf("no capacity offerings are currently available given the constraints")
+		return nil, nil, fmt.Errorf("no capacity offerings are currently available given the constraints")
+	}
+	// A fleet request can't mix capacity blocks with other capacity, so capacity blocks are preferred when they're
+	// available since they're only usable for a limited window
+	if lo.SomeBy(lo.Values(launchTemplatesByName), isCapacityBlock) {
+		launchTemplatesByName = lo.PickBy(launchTemplatesByName, func(_ string, lt *launchtemplate.LaunchTemplate) bool { return isCapacityBlock(lt) })
+		launchTemplateConfigs = lo.Filter(launchTemplateConfigs, func(ltc *ec2.FleetLaunchTemplateConfigRequest, _ int) bool {
+			_, ok := launchTemplatesByName[aws.StringValue(ltc.LaunchTemplateSpecification.LaunchTemplateName)]
+			return ok
+		})
 	}
-	return launchTemplateConfigs, nil
+	return launchTemplateConfigs, launchTemplatesByName, nil
 }
 
 // getOverrides creates and returns launch template overrides for the cross product of InstanceTypes and subnets (with subnets being constrained by
-// zones and the offerings in InstanceTypes)
-func (p *DefaultProvider) getOverrides(instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, zones *scheduling.Requirement, capacityType string, image string) []*ec2.FleetLaunchTemplateOverridesRequest {
+// zones and the offerings in InstanceTypes). Instance types with a configured priority have it set on their overrides for prioritized
+// allocation strategies.
+func (p *DefaultProvider) getOverrides(instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, zones *scheduling.Requirement,
+	capacityType string, image string, priorities map[string]int32) []*ec2.FleetLaunchTemplateOverridesRequest {
 	// Unwrap all the offerings to a flat slice that includes a pointer
 	// to the parent instance type name
 	type offeringWithParentName struct {
@@ -348,32 +500,168 @@ func (p *DefaultProvider) getOverrides(instanceTypes []*cloudprovider.InstanceTy
 		if !ok {
 			continue
 		}
-		overrides = append(overrides, &ec2.FleetLaunchTemplateOverridesRequest{
+		override := &ec2.FleetLaunchTemplateOverridesRequest{
 			InstanceType: aws.String(offering.parentInstanceTypeName),
 			SubnetId:     lo.ToPtr(subnet.ID),
 			ImageId:      aws.String(image),
 			// This is technically redundant, but is useful if we have to parse insufficient capacity errors from
 			// CreateFleet so that we can figure out the zone rather than additional API calls to look up the subnet
 			AvailabilityZone: lo.ToPtr(subnet.Zone),
+		}
+		if priority, ok := priorities[offering.parentInstanceTypeName]; ok {
+			override.Priority = aws.Float64(float64(priority))
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides
+}
+
+// getAttributeBasedOverrides creates and returns a launch template override per subnet (with subnets being constrained by
+// zones). Rather than enumerating instance types, each override describes the instance types with an available offering
+// in the subnet's zone as instance requirements, and EC2 Fleet chooses between them.
+func (p *DefaultProvider) getAttributeBasedOverrides(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, zonalSubnets map[string]*subnet.Subnet, zones *scheduling.Requirement,
+	capacityType string, image string) []*ec2.FleetLaunchTemplateOverridesRequest {
+	var overrides []*ec2.FleetLaunchTemplateOverridesRequest
+	for _, zone := range lo.Keys(zonalSubnets) {
+		if !zones.Has(zone) {
+			continue
+		}
+		zonalInstanceTypes := lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+			return lo.ContainsBy(it.Offerings.Available(), func(of cloudprovider.Offering) bool {
+				return of.CapacityType == capacityType && of.Zone == zone
+			})
+		})
+		if len(zonalInstanceTypes) == 0 {
+			continue
+		}
+		overrides = append(overrides, &ec2.FleetLaunchTemplateOverridesRequest{
+			InstanceRequirements: getInstanceRequirements(nodeClaim, zonalInstanceTypes, capacityType, zone),
+			SubnetId:             lo.ToPtr(zonalSubnets[zone].ID),
+			ImageId:              aws.String(image),
+			AvailabilityZone:     lo.ToPtr(zonalSubnets[zone].Zone),
 		})
 	}
+	sort.Slice(overrides, func(i, j int) bool {
+		return aws.StringValue(overrides[i].AvailabilityZone) < aws.StringValue(overrides[j].AvailabilityZone)
+	})
 	return overrides
 }
 
-func (p *DefaultProvider) updateUnavailableOfferingsCache(ctx context.Context, errors []*ec2.CreateFleetError, capacityType string) {
+// getInstanceRequirements translates the NodeClaim's requirements into EC2 instance requirements for the instance
+// types with an offering of the capacity type in the zone. The vCPU and memory ranges come from the NodeClaim's
+// instance-cpu and instance-memory requirements, with the minimums raised to the smallest compatible instance type.
+// Instance types launched from the same launch template share its user data, such as the kubelet's max pods, so the
+// compatible instance types are allowed by name. This excludes the instance types that the NodeClaim excludes, and
+// ensures that the launched instance type can be mapped back onto the NodeClaim. The price protection threshold is
+// set so that EC2 Fleet won't launch an instance type that is more expensive than the most expensive allowed offering.
+func getInstanceRequirements(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType, capacityType string, zone string) *ec2.InstanceRequirementsRequest {
+	prices := map[string]float64{}
+	for _, it := range instanceTypes {
+		if offering, ok := lo.Find(it.Offerings.Available(), func(of cloudprovider.Offering) bool {
+			return of.CapacityType == capacityType && of.Zone == zone
+		}); ok {
+			prices[it.Name] = offering.Price
+		}
+	}
+	// EC2 limits the number of allowed instance types, so the cheapest instance types are preferred
+	instanceTypes = lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool { _, ok := prices[it.Name]; return ok })
+	sort.SliceStable(instanceTypes, func(i, j int) bool { return prices[instanceTypes[i].Name] < prices[instanceTypes[j].Name] })
+	instanceTypes = lo.Slice(instanceTypes, 0, maxAllowedInstanceTypes)
+	minVCPUs, maxVCPUs := requirementBounds(nodeClaim.Spec.Requirements, v1beta1.LabelInstanceCPU)
+	minMemory, maxMemory := requirementBounds(nodeClaim.Spec.Requirements, v1beta1.LabelInstanceMemory)
+	instanceRequirements := &ec2.InstanceRequirementsRequest{
+		VCpuCount: &ec2.VCpuCountRangeRequest{
+			Min: aws.Int64(lo.Max([]int64{minVCPUs, lo.Min(lo.Map(instanceTypes, func(it *cloudprovider.InstanceType, _ int) int64 { return it.Capacity.Cpu().Value() }))})),
+			Max: maxVCPUs,
+		},
+		MemoryMiB: &ec2.MemoryMiBRequest{
+			Min: aws.Int64(lo.Max([]int64{minMemory, lo.Min(lo.Map(instanceTypes, func(it *cloudprovider.InstanceType, _ int) int64 { return it.Capacity.Memory().Value() / 1024 / 1024 }))})),
+			Max: maxMemory,
+		},
+		AllowedInstanceTypes: lo.Map(instanceTypes, func(it *cloudprovider.InstanceType, _ int) *string { return aws.String(it.Name) }),
+		// EC2 excludes bare metal and burstable performance instance types unless they're explicitly included
+		BareMetal:            aws.String(ec2.BareMetalIncluded),
+		BurstablePerformance: aws.String(ec2.BurstablePerformanceIncluded),
+	}
+	nonZeroPrices := lo.FilterMap(instanceTypes, func(it *cloudprovider.InstanceType, _ int) (float64, bool) {
+		return prices[it.Name], prices[it.Name] > 0
+	})
+	if len(nonZeroPrices) == 0 {
+		return instanceRequirements
+	}
+	threshold := aws.Int64(int64(math.Ceil((lo.Max(nonZeroPrices)/lo.Min(nonZeroPrices) - 1) * 100)))
+	if capacityType == corev1beta1.CapacityTypeSpot {
+		instanceRequirements.SpotMaxPricePercentageOverLowestPrice = threshold
+	} else {
+		instanceRequirements.OnDemandMaxPricePercentageOverLowestPrice = threshold
+	}
+	return instanceRequirements
+}
+
+// requirementBounds returns the inclusive bounds of the NodeClaim's numeric requirements for the key. The minimum
+// defaults to zero and the maximum is nil when the requirements don't bound it.
+func requirementBounds(requirements []corev1beta1.NodeSelectorRequirementWithMinValues, key string) (int64, *int64) {
+	var minimum int64
+	var maximum *int64
+	for _, requirement := range requirements {
+		if requirement.Key != key {
+			continue
+		}
+		values := lo.FilterMap(requirement.Values, func(value string, _ int) (int64, bool) {
+			v, err := strconv.ParseInt(value, 10, 64)
+			return v, err == nil
+		})
+		if len(values) == 0 {
+			continue
+		}
+		lower, upper := lo.Min(values), lo.Max(values)
+		switch requirement.Operator {
+		case v1.NodeSelectorOpGt:
+			minimum = lo.Max([]int64{minimum, lower + 1})
+		case v1.NodeSelectorOpLt:
+			maximum = lo.ToPtr(lo.Min([]int64{lo.FromPtrOr(maximum, math.MaxInt64), upper - 1}))
+		case v1.NodeSelectorOpIn:
+			minimum = lo.Max([]int64{minimum, lower})
+			maximum = lo.ToPtr(lo.Min([]int64{lo.FromPtrOr(maximum, math.MaxInt64), upper}))
+		}
+	}
+	return minimum, maximum
+}
+
+func (p *DefaultProvider) updateUnavailableOfferingsCache(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, errors []*ec2.CreateFleetError, capacityType string) {
 	for _, err := range errors {
-		if awserrors.IsUnfulfillableCapacity(err) {
-			p.unavailableOfferings.MarkUnavailableForFleetErr(ctx, err, capacityType)
+		if !awserrors.IsUnfulfillableCapacity(err) {
+			continue
 		}
+		// Capacity errors when launching into a placement group only reflect the capacity available to the group
+		if nodeClass.Status.PlacementGroup != nil {
+			p.unavailableOfferings.MarkUnavailableForPlacementGroupFleetErr(ctx, err, capacityType, nodeClass.Status.PlacementGroup.ID)
+			continue
+		}
+		p.unavailableOfferings.MarkUnavailableForFleetErr(ctx, err, capacityType)
 	}
 }
 
-// getCapacityType selects spot if both constraints are flexible and there is an
-// available offering. The AWS Cloud Provider defaults to [ on-demand ], so spot
+// getCapacityType selects reserved if it is allowed and there is an available reserved offering, since that
+// capacity has already been paid for. NodeClaims that require a capacity reservation ID are only launched into reserved
+// capacity. Otherwise, it selects spot if both constraints are flexible and there is an
+// available offering. The AWS Cloud Provider defaults to [ on-demand ], so reserved and spot
 // must be explicitly included in capacity type requirements.
 func (p *DefaultProvider) getCapacityType(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) string {
 	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.
 		Spec.Requirements...)
+	if requirements.Get(corev1beta1.CapacityTypeLabelKey).Has(v1beta1.CapacityTypeReserved) {
+		if requirements.Has(v1beta1.LabelCapacityReservationID) && requirements.Get(v1beta1.LabelCapacityReservationID).Operator() != v1.NodeSelectorOpDoesNotExist {
+			return v1beta1.CapacityTypeReserved
+		}
+		for _, instanceType := range instanceTypes {
+			for _, offering := range instanceType.Offerings.Available() {
+				if requirements.Get(v1.LabelTopologyZone).Has(offering.Zone) && offering.CapacityType == v1beta1.CapacityTypeReserved {
+					return v1beta1.CapacityTypeReserved
+				}
+			}
+		}
+	}
 	if requirements.Get(corev1beta1.CapacityTypeLabelKey).Has(corev1beta1.CapacityTypeSpot) {
 		for _, instanceType := range instanceTypes {
 			for _, offering := range instanceType.Offerings.Available() {
@@ -386,6 +674,32 @@ func (p *DefaultProvider) getCapacityType(nodeClaim *corev1beta1.NodeClaim, inst
 	return corev1beta1.CapacityTypeOnDemand
 }
 
+// truncateInstanceTypes limits the instance types that are passed to EC2 Fleet to the maxItems cheapest instance types.
+// Instance types with a configured priority are ordered ahead of the others by their priority, so that they aren't
+// dropped in favor of cheaper instance types before the prioritized allocation strategies can consider them.
+func truncateInstanceTypes(nodeClass *v1beta1.EC2NodeClass, requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType, maxItems int) (cloudprovider.InstanceTypes, error) {
+	if nodeClass.Spec.AllocationStrategy == nil || len(nodeClass.Spec.AllocationStrategy.InstanceTypePriorities) == 0 {
+		return cloudprovider.InstanceTypes(instanceTypes).Truncate(requirements, maxItems)
+	}
+	priorities := nodeClass.Spec.AllocationStrategy.InstanceTypePriorities
+	orderedInstanceTypes := cloudprovider.InstanceTypes(instanceTypes).OrderByPrice(requirements)
+	sort.SliceStable(orderedInstanceTypes, func(i, j int) bool {
+		iPriority, iOk := priorities[orderedInstanceTypes[i].Name]
+		jPriority, jOk := priorities[orderedInstanceTypes[j].Name]
+		if iOk != jOk {
+			return iOk
+		}
+		return iOk && iPriority < jPriority
+	})
+	truncatedInstanceTypes := cloudprovider.InstanceTypes(lo.Slice(orderedInstanceTypes, 0, maxItems))
+	if requirements.HasMinValues() {
+		if _, err := truncatedInstanceTypes.SatisfiesMinValues(requirements); err != nil {
+			return instanceTypes, fmt.Errorf("validating minValues, %w", err)
+		}
+	}
+	return truncatedInstanceTypes, nil
+}
+
 // filterInstanceTypes is used to provide filtering on the list of potential instance types to further limit it to those
 // that make the most sense given our specific AWS cloudprovider.
 func (p *DefaultProvider) filterInstanceTypes(nodeClaim *corev1beta1.NodeClaim, instanceTypes []*cloudprovider.InstanceType) []*cloudprovider.InstanceType {
@@ -15,6 +15,7 @@ limitations under the License.
 package instance
 
 import (
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -22,6 +23,9 @@ import (
 	"github.com/samber/lo"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
 )
 
 // Instance is an internal data representation of either an ec2.Instance or an ec2.FleetInstance
@@ -38,6 +42,20 @@ type Instance struct {
 	SubnetID         string
 	Tags             map[string]string
 	EFAEnabled       bool
+	// CapacityReservationID is the id of the capacity reservation the instance was launched into, if any
+	CapacityReservationID string
+	// PlacementGroupID and PlacementGroupPartition describe the placement group the instance was launched into, if any
+	PlacementGroupID        string
+	PlacementGroupPartition int64
+	// Tenancy is the tenancy the instance was launched with
+	Tenancy string
+	// InstanceProfile is the name of the instance profile the instance was launched with
+	InstanceProfile string
+	// RootVolumeID and NetworkInterfaceIDs identify the resources that are tagged along with the instance
+	RootVolumeID        string
+	NetworkInterfaceIDs []string
+	// SpotInstanceRequestID is the id of the spot request that the instance was launched with, if any
+	SpotInstanceRequestID string
 }
 
 func NewInstance(out *ec2.Instance) *Instance {
@@ -48,7 +66,7 @@ func NewInstance(out *ec2.Instance) *Instance {
 		ImageID:      aws.StringValue(out.ImageId),
 		Type:         aws.StringValue(out.InstanceType),
 		Zone:         aws.StringValue(out.Placement.AvailabilityZone),
-		CapacityType: lo.Ternary(out.SpotInstanceRequestId != nil, corev1beta1.CapacityTypeSpot, corev1beta1.CapacityTypeOnDemand),
+		CapacityType: capacityType(out),
 		SecurityGroupIDs: lo.Map(out.SecurityGroups, func(securitygroup *ec2.GroupIdentifier, _ int) string {
 			return aws.StringValue(securitygroup.GroupId)
 		}),
@@ -57,21 +75,71 @@ func NewInstance(out *ec2.Instance) *Instance {
 		EFAEnabled: lo.ContainsBy(out.NetworkInterfaces, func(ni *ec2.InstanceNetworkInterface) bool {
 			return ni != nil && lo.FromPtr(ni.InterfaceType) == ec2.NetworkInterfaceTypeEfa
 		}),
+		CapacityReservationID:   aws.StringValue(out.CapacityReservationId),
+		PlacementGroupID:        aws.StringValue(out.Placement.GroupId),
+		PlacementGroupPartition: aws.Int64Value(out.Placement.PartitionNumber),
+		Tenancy:                 aws.StringValue(out.Placement.Tenancy),
+		InstanceProfile:         instanceProfileName(out.IamInstanceProfile),
+		RootVolumeID:            rootVolumeID(out),
+		NetworkInterfaceIDs: lo.FilterMap(out.NetworkInterfaces, func(ni *ec2.InstanceNetworkInterface, _ int) (string, bool) {
+			if ni == nil || ni.NetworkInterfaceId == nil {
+				return "", false
+			}
+			return aws.StringValue(ni.NetworkInterfaceId), true
+		}),
+		SpotInstanceRequestID: aws.StringValue(out.SpotInstanceRequestId),
 	}
 
 }
 
-func NewInstanceFromFleet(out *ec2.CreateFleetInstance, tags map[string]string, efaEnabled bool) *Instance {
+func NewInstanceFromFleet(out *ec2.CreateFleetInstance, tags map[string]string, efaEnabled bool, launchTemplate *launchtemplate.LaunchTemplate) *Instance {
+	if launchTemplate == nil {
+		launchTemplate = &launchtemplate.LaunchTemplate{}
+	}
 	return &Instance{
-		LaunchTime:   time.Now(), // estimate the launch time since we just launched
-		State:        ec2.StatePending,
-		ID:           aws.StringValue(out.InstanceIds[0]),
-		ImageID:      aws.StringValue(out.LaunchTemplateAndOverrides.Overrides.ImageId),
-		Type:         aws.StringValue(out.InstanceType),
-		Zone:         aws.StringValue(out.LaunchTemplateAndOverrides.Overrides.AvailabilityZone),
-		CapacityType: aws.StringValue(out.Lifecycle),
-		SubnetID:     aws.StringValue(out.LaunchTemplateAndOverrides.Overrides.SubnetId),
-		Tags:         tags,
-		EFAEnabled:   efaEnabled,
+		LaunchTime:              time.Now(), // estimate the launch time since we just launched
+		State:                   ec2.StatePending,
+		ID:                      aws.StringValue(out.InstanceIds[0]),
+		ImageID:                 aws.StringValue(out.LaunchTemplateAndOverrides.Overrides.ImageId),
+		Type:                    aws.StringValue(out.InstanceType),
+		Zone:                    aws.StringValue(out.LaunchTemplateAndOverrides.Overrides.AvailabilityZone),
+		CapacityType:            lo.Ternary(launchTemplate.CapacityReservationID != "", v1beta1.CapacityTypeReserved, aws.StringValue(out.Lifecycle)),
+		SubnetID:                aws.StringValue(out.LaunchTemplateAndOverrides.Overrides.SubnetId),
+		Tags:                    tags,
+		EFAEnabled:              efaEnabled,
+		CapacityReservationID:   launchTemplate.CapacityReservationID,
+		PlacementGroupID:        launchTemplate.PlacementGroupID,
+		PlacementGroupPartition: launchTemplate.PlacementGroupPartition,
+		Tenancy:                 launchTemplate.Tenancy,
+	}
+}
+
+// instanceProfileName returns the name of the instance profile from its ARN, which includes the instance profile's path
+func instanceProfileName(instanceProfile *ec2.IamInstanceProfile) string {
+	if instanceProfile == nil {
+		return ""
+	}
+	arn := aws.StringValue(instanceProfile.Arn)
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+func rootVolumeID(out *ec2.Instance) string {
+	mapping, ok := lo.Find(out.BlockDeviceMappings, func(m *ec2.InstanceBlockDeviceMapping) bool {
+		return m != nil && m.Ebs != nil && aws.StringValue(m.DeviceName) == aws.StringValue(out.RootDeviceName)
+	})
+	if !ok {
+		return ""
+	}
+	return aws.StringValue(mapping.Ebs.VolumeId)
+}
+
+func capacityType(out *ec2.Instance) string {
+	switch {
+	case out.SpotInstanceRequestId != nil:
+		return corev1beta1.CapacityTypeSpot
+	case out.CapacityReservationId != nil:
+		return v1beta1.CapacityTypeReserved
+	default:
+		return corev1beta1.CapacityTypeOnDemand
 	}
 }
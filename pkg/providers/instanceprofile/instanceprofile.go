@@ -34,13 +34,17 @@ import (
 type ResourceOwner interface {
 	GetUID() types.UID
 	InstanceProfileName(string, string) string
+	RoleInstanceProfileName(string, string) string
+	InstanceProfilePath(string) string
 	InstanceProfileRole() string
 	InstanceProfileTags(string) map[string]string
 }
 
 type Provider interface {
 	Create(context.Context, ResourceOwner) (string, error)
+	List(context.Context, ResourceOwner) ([]string, error)
 	Delete(context.Context, ResourceOwner) error
+	DeleteInstanceProfile(context.Context, string) error
 }
 
 type DefaultProvider struct {
@@ -57,71 +61,113 @@ func NewDefaultProvider(region string, iamapi iamiface.IAMAPI, cache *cache.Cach
 	}
 }
 
+// Create returns the name of the instance profile for the role of the ResourceOwner, creating it if it doesn't exist.
+// The instance profile named by InstanceProfileName is used for as long as it has the role. Once the role changes,
+// a separate instance profile is created for the new role rather than replacing the role on the existing instance
+// profile, so that running instances keep the role they were launched with until they're replaced.
 func (p *DefaultProvider) Create(ctx context.Context, m ResourceOwner) (string, error) {
-	profileName := m.InstanceProfileName(options.FromContext(ctx).ClusterName, p.region)
-	tags := lo.Assign(m.InstanceProfileTags(options.FromContext(ctx).ClusterName), map[string]string{v1.LabelTopologyRegion: p.region})
-
-	// An instance profile exists for this NodeClass
-	if _, ok := p.cache.Get(string(m.GetUID())); ok {
-		return profileName, nil
+	clusterName := options.FromContext(ctx).ClusterName
+	key := fmt.Sprintf("%s/%s", m.GetUID(), m.InstanceProfileRole())
+	// An instance profile exists for this NodeClass and role
+	if profileName, ok := p.cache.Get(key); ok {
+		return profileName.(string), nil
 	}
-	// Validate if the instance profile exists and has the correct role assigned to it
-	var instanceProfile *iam.InstanceProfile
-	out, err := p.iamapi.GetInstanceProfileWithContext(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(profileName)})
+	profileName := m.RoleInstanceProfileName(clusterName, p.region)
+	instanceProfile, err := p.get(ctx, profileName)
 	if err != nil {
-		if !awserrors.IsNotFound(err) {
-			return "", fmt.Errorf("getting instance profile %q, %w", profileName, err)
+		return "", err
+	}
+	if instanceProfile == nil {
+		profileName = m.InstanceProfileName(clusterName, p.region)
+		if instanceProfile, err = p.get(ctx, profileName); err != nil {
+			return "", err
 		}
-		o, err := p.iamapi.CreateInstanceProfileWithContext(ctx, &iam.CreateInstanceProfileInput{
+		// Instance profiles can only have a single role assigned to them so this profile either has 1 or 0 roles
+		// https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_use_switch-role-ec2_instance-profiles.html
+		if instanceProfile != nil && len(instanceProfile.Roles) == 1 && aws.StringValue(instanceProfile.Roles[0].RoleName) != m.InstanceProfileRole() {
+			profileName = m.RoleInstanceProfileName(clusterName, p.region)
+			instanceProfile = nil
+		}
+	}
+	if instanceProfile == nil {
+		tags := lo.Assign(m.InstanceProfileTags(clusterName), map[string]string{v1.LabelTopologyRegion: p.region})
+		out, err := p.iamapi.CreateInstanceProfileWithContext(ctx, &iam.CreateInstanceProfileInput{
 			InstanceProfileName: aws.String(profileName),
+			Path:                aws.String(m.InstanceProfilePath(clusterName)),
 			Tags:                lo.MapToSlice(tags, func(k, v string) *iam.Tag { return &iam.Tag{Key: aws.String(k), Value: aws.String(v)} }),
 		})
 		if err != nil {
 			return "", fmt.Errorf("creating instance profile %q, %w", profileName, err)
 		}
-		instanceProfile = o.InstanceProfile
-	} else {
 		instanceProfile = out.InstanceProfile
 	}
-	// Instance profiles can only have a single role assigned to them so this profile either has 1 or 0 roles
-	// https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_use_switch-role-ec2_instance-profiles.html
-	if len(instanceProfile.Roles) == 1 {
-		if aws.StringValue(instanceProfile.Roles[0].RoleName) == m.InstanceProfileRole() {
-			return profileName, nil
-		}
-		if _, err = p.iamapi.RemoveRoleFromInstanceProfileWithContext(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+	if len(instanceProfile.Roles) == 0 {
+		if _, err = p.iamapi.AddRoleToInstanceProfileWithContext(ctx, &iam.AddRoleToInstanceProfileInput{
 			InstanceProfileName: aws.String(profileName),
-			RoleName:            instanceProfile.Roles[0].RoleName,
+			RoleName:            aws.String(m.InstanceProfileRole()),
 		}); err != nil {
-			return "", fmt.Errorf("removing role %q for instance profile %q, %w", aws.StringValue(instanceProfile.Roles[0].RoleName), profileName, err)
+			return "", fmt.Errorf("adding role %q to instance profile %q, %w", m.InstanceProfileRole(), profileName, err)
 		}
 	}
-	if _, err = p.iamapi.AddRoleToInstanceProfileWithContext(ctx, &iam.AddRoleToInstanceProfileInput{
-		InstanceProfileName: aws.String(profileName),
-		RoleName:            aws.String(m.InstanceProfileRole()),
+	p.cache.SetDefault(key, profileName)
+	return profileName, nil
+}
+
+// List returns the names of the instance profiles that have been created for the ResourceOwner, including the ones
+// created for roles that it no longer uses
+func (p *DefaultProvider) List(ctx context.Context, m ResourceOwner) ([]string, error) {
+	clusterName := options.FromContext(ctx).ClusterName
+	var profileNames []string
+	if err := p.iamapi.ListInstanceProfilesPagesWithContext(ctx, &iam.ListInstanceProfilesInput{
+		PathPrefix: aws.String(m.InstanceProfilePath(clusterName)),
+	}, func(page *iam.ListInstanceProfilesOutput, _ bool) bool {
+		for _, instanceProfile := range page.InstanceProfiles {
+			profileNames = append(profileNames, aws.StringValue(instanceProfile.InstanceProfileName))
+		}
+		return true
 	}); err != nil {
-		return "", fmt.Errorf("adding role %q to instance profile %q, %w", m.InstanceProfileRole(), profileName, err)
+		return nil, fmt.Errorf("listing instance profiles, %w", err)
+	}
+	// Instance profiles that were created before they were scoped by path can only be found by name
+	profileName := m.InstanceProfileName(clusterName, p.region)
+	instanceProfile, err := p.get(ctx, profileName)
+	if err != nil {
+		return nil, err
+	}
+	if instanceProfile != nil {
+		profileNames = append(profileNames, profileName)
 	}
-	p.cache.SetDefault(string(m.GetUID()), nil)
-	return aws.StringValue(instanceProfile.InstanceProfileName), nil
+	return lo.Uniq(profileNames), nil
 }
 
+// Delete deletes all of the instance profiles that have been created for the ResourceOwner
 func (p *DefaultProvider) Delete(ctx context.Context, m ResourceOwner) error {
-	profileName := m.InstanceProfileName(options.FromContext(ctx).ClusterName, p.region)
-	out, err := p.iamapi.GetInstanceProfileWithContext(ctx, &iam.GetInstanceProfileInput{
-		InstanceProfileName: aws.String(profileName),
-	})
+	profileNames, err := p.List(ctx, m)
 	if err != nil {
-		return awserrors.IgnoreNotFound(fmt.Errorf("getting instance profile %q, %w", profileName, err))
+		return err
+	}
+	for _, profileName := range profileNames {
+		if err := p.DeleteInstanceProfile(ctx, profileName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteInstanceProfile removes the role from the instance profile and deletes it
+func (p *DefaultProvider) DeleteInstanceProfile(ctx context.Context, profileName string) error {
+	instanceProfile, err := p.get(ctx, profileName)
+	if err != nil || instanceProfile == nil {
+		return err
 	}
 	// Instance profiles can only have a single role assigned to them so this profile either has 1 or 0 roles
 	// https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_use_switch-role-ec2_instance-profiles.html
-	if len(out.InstanceProfile.Roles) == 1 {
+	if len(instanceProfile.Roles) == 1 {
 		if _, err = p.iamapi.RemoveRoleFromInstanceProfileWithContext(ctx, &iam.RemoveRoleFromInstanceProfileInput{
 			InstanceProfileName: aws.String(profileName),
-			RoleName:            out.InstanceProfile.Roles[0].RoleName,
+			RoleName:            instanceProfile.Roles[0].RoleName,
 		}); err != nil {
-			return fmt.Errorf("removing role %q from instance profile %q, %w", aws.StringValue(out.InstanceProfile.Roles[0].RoleName), profileName, err)
+			return fmt.Errorf("removing role %q from instance profile %q, %w", aws.StringValue(instanceProfile.Roles[0].RoleName), profileName, err)
 		}
 	}
 	if _, err = p.iamapi.DeleteInstanceProfileWithContext(ctx, &iam.DeleteInstanceProfileInput{
@@ -129,5 +175,24 @@ func (p *DefaultProvider) Delete(ctx context.Context, m ResourceOwner) error {
 	}); err != nil {
 		return awserrors.IgnoreNotFound(fmt.Errorf("deleting instance profile %q, %w", profileName, err))
 	}
+	// The role may be changed back to the one that the instance profile was created for, so the instance profile
+	// shouldn't be resolved from the cache once it's deleted
+	for k, item := range p.cache.Items() {
+		if item.Object == profileName {
+			p.cache.Delete(k)
+		}
+	}
 	return nil
 }
+
+// get returns the instance profile with the given name, or nil if it doesn't exist
+func (p *DefaultProvider) get(ctx context.Context, profileName string) (*iam.InstanceProfile, error) {
+	out, err := p.iamapi.GetInstanceProfileWithContext(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(profileName)})
+	if err != nil {
+		if awserrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting instance profile %q, %w", profileName, err)
+	}
+	return out.InstanceProfile, nil
+}
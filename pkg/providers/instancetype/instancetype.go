@@ -35,13 +35,17 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/clock"
 
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
 )
 
@@ -57,6 +61,7 @@ type DefaultProvider struct {
 	ec2api          ec2iface.EC2API
 	subnetProvider  subnet.Provider
 	pricingProvider pricing.Provider
+	clk             clock.Clock
 
 	// Values stored *before* considering insufficient capacity errors from the unavailableOfferings cache.
 	// Fully initialized Instance Types are also cached based on the set of all instance types, zones, unavailableOfferings cache,
@@ -80,12 +85,13 @@ type DefaultProvider struct {
 }
 
 func NewDefaultProvider(region string, instanceTypesCache *cache.Cache, ec2api ec2iface.EC2API, subnetProvider subnet.Provider,
-	unavailableOfferingsCache *awscache.UnavailableOfferings, pricingProvider pricing.Provider) *DefaultProvider {
+	unavailableOfferingsCache *awscache.UnavailableOfferings, pricingProvider pricing.Provider, clk clock.Clock) *DefaultProvider {
 	return &DefaultProvider{
 		ec2api:                ec2api,
 		region:                region,
 		subnetProvider:        subnetProvider,
 		pricingProvider:       pricingProvider,
+		clk:                   clk,
 		instanceTypesInfo:     []*ec2.InstanceTypeInfo{},
 		instanceTypeOfferings: map[string]sets.Set[string]{},
 		instanceTypesCache:    instanceTypesCache,
@@ -101,6 +107,8 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 	defer p.muInstanceTypeInfo.RUnlock()
 	defer p.muInstanceTypeOfferings.RUnlock()
 
+	// Merging the EC2NodeClass's kubelet configuration before hashing includes it in the instance type cache key
+	kc = nodeClass.KubeletConfiguration(kc)
 	if kc == nil {
 		kc = &corev1beta1.KubeletConfiguration{}
 	}
@@ -110,27 +118,49 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 	if len(p.instanceTypeOfferings) == 0 {
 		return nil, fmt.Errorf("no instance types offerings found")
 	}
-	if len(nodeClass.Status.Subnets) == 0 {
+	if len(nodeClass.LaunchSubnets()) == 0 {
 		return nil, fmt.Errorf("no subnets found")
 	}
 
-	subnetZones := sets.New(lo.Map(nodeClass.Status.Subnets, func(s v1beta1.Subnet, _ int) string {
+	subnetZones := sets.New(lo.Map(nodeClass.LaunchSubnets(), func(s v1beta1.Subnet, _ int) string {
 		return aws.StringValue(&s.Zone)
 	})...)
+	// Pods are assigned IP addresses from the pod subnets when they're selected, so zones without pod IP capacity aren't offered
+	if len(nodeClass.Status.PodSubnets) > 0 {
+		subnetZones = subnetZones.Intersection(p.subnetProvider.PodSubnetZones(ctx, nodeClass))
+	}
 
 	// Compute fully initialized instance types hash key
 	subnetZonesHash, _ := hashstructure.Hash(subnetZones, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	kcHash, _ := hashstructure.Hash(kc, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
 	blockDeviceMappingsHash, _ := hashstructure.Hash(nodeClass.Spec.BlockDeviceMappings, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
-	key := fmt.Sprintf("%d-%d-%d-%016x-%016x-%016x-%s-%s",
+	// Capacity reservations can become active or inactive without any change to the status, so the set of currently
+	// active reservations is included in the hash as well
+	capacityReservationsHash, _ := hashstructure.Hash([]any{
+		nodeClass.Status.CapacityReservations,
+		lo.FilterMap(nodeClass.Status.CapacityReservations, func(cr v1beta1.CapacityReservation, _ int) (string, bool) {
+			return cr.ID, capacityreservation.IsActive(cr, p.clk.Now())
+		}),
+	}, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	placementGroupHash, _ := hashstructure.Hash(nodeClass.Status.PlacementGroup, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	cpuOptionsHash, _ := hashstructure.Hash(nodeClass.Spec.CPUOptions, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	// The estimated surplus utilization is excluded from the nodeclass hash, but it still changes offering prices
+	creditSpecification := lo.FromPtr(nodeClass.Spec.CreditSpecification)
+	creditSpecificationHash, _ := hashstructure.Hash([]interface{}{creditSpecification.CPUCredits, creditSpecification.EstimatedSurplusUtilization}, hashstructure.FormatV2, nil)
+	key := fmt.Sprintf("%d-%d-%d-%016x-%016x-%016x-%016x-%016x-%016x-%016x-%s-%s-%s",
 		p.instanceTypesSeqNum,
 		p.instanceTypeOfferingsSeqNum,
 		p.unavailableOfferings.SeqNum,
 		subnetZonesHash,
 		kcHash,
 		blockDeviceMappingsHash,
+		capacityReservationsHash,
+		placementGroupHash,
+		cpuOptionsHash,
+		creditSpecificationHash,
 		aws.StringValue((*string)(nodeClass.Spec.InstanceStorePolicy)),
 		aws.StringValue(nodeClass.Spec.AMIFamily),
+		nodeClass.TenancyType(),
 	)
 	if item, ok := p.instanceTypesCache.Get(key); ok {
 		// Ensure what's returned from this function is a shallow-copy of the slice (not a deep-copy of the data itself)
@@ -150,7 +180,11 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 		log.FromContext(ctx).WithValues("zones", allZones.UnsortedList()).V(1).Info("discovered zones")
 	}
 	amiFamily := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{})
-	result := lo.Map(p.instanceTypesInfo, func(i *ec2.InstanceTypeInfo, _ int) *cloudprovider.InstanceType {
+	// Instance types that can't be launched with the EC2NodeClass's CPU options are excluded entirely
+	instanceTypesInfo := lo.Filter(p.instanceTypesInfo, func(i *ec2.InstanceTypeInfo, _ int) bool {
+		return supportsCPUOptions(i, nodeClass.Spec.CPUOptions)
+	})
+	result := lo.Map(instanceTypesInfo, func(i *ec2.InstanceTypeInfo, _ int) *cloudprovider.InstanceType {
 		instanceTypeVCPU.With(prometheus.Labels{
 			instanceTypeLabel: *i.InstanceType,
 		}).Set(float64(aws.Int64Value(i.VCpuInfo.DefaultVCpus)))
@@ -162,10 +196,15 @@ func (p *DefaultProvider) List(ctx context.Context, kc *corev1beta1.KubeletConfi
 		// Any changes to the values passed into the NewInstanceType method will require making updates to the cache key
 		// so that Karpenter is able to cache the set of InstanceTypes based on values that alter the set of instance types
 		// !!! Important !!!
-		return NewInstanceType(ctx, i, p.region,
-			nodeClass.Spec.BlockDeviceMappings, nodeClass.Spec.InstanceStorePolicy,
+		it := NewInstanceType(ctx, i, p.region,
+			nodeClass.Spec.BlockDeviceMappings, nodeClass.Spec.InstanceStorePolicy, nodeClass.Spec.CPUOptions,
 			kc.MaxPods, kc.PodsPerCore, kc.KubeReserved, kc.SystemReserved, kc.EvictionHard, kc.EvictionSoft,
-			amiFamily, p.createOfferings(ctx, i, p.instanceTypeOfferings[aws.StringValue(i.InstanceType)], allZones, subnetZones))
+			amiFamily, p.createOfferings(ctx, i, p.instanceTypeOfferings[aws.StringValue(i.InstanceType)], allZones, subnetZones,
+				nodeClass.Status.CapacityReservations, nodeClass.Status.PlacementGroup, nodeClass.TenancyType(), nodeClass.Spec.CreditSpecification))
+		// Every instance type launched by the EC2NodeClass shares its tenancy, so pods can select on it
+		it.Requirements.Add(scheduling.NewRequirement(v1beta1.LabelTenancy, v1.NodeSelectorOpIn, nodeClass.TenancyType()))
+		it.Requirements.Add(p.capacityReservationRequirement(it, nodeClass.Status.CapacityReservations))
+		return it
 	})
 	p.instanceTypesCache.SetDefault(key, result)
 	return result, nil
@@ -249,28 +288,42 @@ func (p *DefaultProvider) UpdateInstanceTypeOfferings(ctx context.Context) error
 	return nil
 }
 
-func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2.InstanceTypeInfo, instanceTypeZones, zones, subnetZones sets.Set[string]) []cloudprovider.Offering {
+func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2.InstanceTypeInfo, instanceTypeZones, zones, subnetZones sets.Set[string],
+	capacityReservations []v1beta1.CapacityReservation, placementGroup *v1beta1.PlacementGroup, tenancy string, creditSpecification *v1beta1.CreditSpecification) []cloudprovider.Offering {
 	var offerings []cloudprovider.Offering
+	surcharge := p.unlimitedModeSurcharge(instanceType, creditSpecification)
 	for zone := range zones {
 		// while usage classes should be a distinct set, there's no guarantee of that
 		for capacityType := range sets.NewString(aws.StringValueSlice(instanceType.SupportedUsageClasses)...) {
 			// exclude any offerings that have recently seen an insufficient capacity error from EC2
-			isUnavailable := p.unavailableOfferings.IsUnavailable(*instanceType.InstanceType, zone, capacityType)
+			isUnavailable := p.isUnavailable(*instanceType.InstanceType, zone, capacityType, placementGroup)
 			var price float64
 			var ok bool
 			switch capacityType {
 			case ec2.UsageClassTypeSpot:
+				// spot instances can only be launched with shared tenancy
+				if tenancy != v1beta1.TenancyDefault {
+					continue
+				}
 				price, ok = p.pricingProvider.SpotPrice(*instanceType.InstanceType, zone)
 			case ec2.UsageClassTypeOnDemand:
-				price, ok = p.pricingProvider.OnDemandPrice(*instanceType.InstanceType)
+				// Dedicated instances carry a premium over shared tenancy. Dedicated Hosts are billed per host rather than
+				// per instance, so instances launched onto them keep the shared tenancy price to preserve a relative
+				// ordering between instance types.
+				if tenancy == v1beta1.TenancyDedicated {
+					price, ok = p.pricingProvider.DedicatedOnDemandPrice(*instanceType.InstanceType)
+				} else {
+					price, ok = p.pricingProvider.OnDemandPrice(*instanceType.InstanceType)
+				}
 			case "capacity-block":
-				// ignore since karpenter doesn't support it yet, but do not log an unknown capacity type error
+				// capacity blocks are discovered as capacity reservations, and are offered by createReservedOfferings
 				continue
 			default:
 				log.FromContext(ctx).WithValues("capacity-type", capacityType, "instance-type", *instanceType.InstanceType).Error(fmt.Errorf("received unknown capacity type"), "failed parsing offering")
 				continue
 			}
 			available := !isUnavailable && ok && instanceTypeZones.Has(zone) && subnetZones.Has(zone)
+			price += surcharge
 			offerings = append(offerings, cloudprovider.Offering{
 				Zone:         zone,
 				CapacityType: capacityType,
@@ -289,9 +342,85 @@ func (p *DefaultProvider) createOfferings(ctx context.Context, instanceType *ec2
 			}).Set(price)
 		}
 	}
+	return append(offerings, p.createReservedOfferings(instanceType, instanceTypeZones, subnetZones, capacityReservations, placementGroup)...)
+}
+
+// unlimitedModeSurcharge estimates the hourly cost of the surplus credits that a burstable performance instance accrues
+// in unlimited mode, based on the estimated percentage of time that its vCPUs spend bursting above their baseline
+func (p *DefaultProvider) unlimitedModeSurcharge(instanceType *ec2.InstanceTypeInfo, creditSpecification *v1beta1.CreditSpecification) float64 {
+	if !aws.BoolValue(instanceType.BurstablePerformanceSupported) || creditSpecification == nil ||
+		creditSpecification.CPUCredits != v1beta1.CPUCreditsUnlimited || creditSpecification.EstimatedSurplusUtilization == nil {
+		return 0
+	}
+	return float64(aws.Int64Value(instanceType.VCpuInfo.DefaultVCpus)) * float64(*creditSpecification.EstimatedSurplusUtilization) / 100 *
+		p.pricingProvider.SurplusCreditPrice(aws.StringValue(instanceType.InstanceType))
+}
+
+// createReservedOfferings creates an offering for each zone where the instance type has a resolved capacity reservation.
+// Reserved capacity is already paid for, so these offerings are zero-priced and are only available while the
+// reservations in the zone are active and have remaining instance capacity.
+func (p *DefaultProvider) createReservedOfferings(instanceType *ec2.InstanceTypeInfo, instanceTypeZones, subnetZones sets.Set[string],
+	capacityReservations []v1beta1.CapacityReservation, placementGroup *v1beta1.PlacementGroup) []cloudprovider.Offering {
+	var offerings []cloudprovider.Offering
+	zonalAvailableCounts := map[string]int64{}
+	for _, cr := range capacityReservations {
+		if cr.InstanceType != aws.StringValue(instanceType.InstanceType) {
+			continue
+		}
+		zonalAvailableCounts[cr.Zone] += lo.Ternary(capacityreservation.IsActive(cr, p.clk.Now()), cr.AvailableInstanceCount, 0)
+	}
+	for zone, count := range zonalAvailableCounts {
+		isUnavailable := p.isUnavailable(*instanceType.InstanceType, zone, v1beta1.CapacityTypeReserved, placementGroup)
+		available := !isUnavailable && count > 0 && instanceTypeZones.Has(zone) && subnetZones.Has(zone)
+		offerings = append(offerings, cloudprovider.Offering{
+			Zone:         zone,
+			CapacityType: v1beta1.CapacityTypeReserved,
+			Price:        0,
+			Available:    available,
+		})
+		instanceTypeOfferingAvailable.With(prometheus.Labels{
+			instanceTypeLabel: *instanceType.InstanceType,
+			capacityTypeLabel: v1beta1.CapacityTypeReserved,
+			zoneLabel:         zone,
+		}).Set(float64(lo.Ternary(available, 1, 0)))
+		instanceTypeOfferingPriceEstimate.With(prometheus.Labels{
+			instanceTypeLabel: *instanceType.InstanceType,
+			capacityTypeLabel: v1beta1.CapacityTypeReserved,
+			zoneLabel:         zone,
+		}).Set(0)
+	}
 	return offerings
 }
 
+// capacityReservationRequirement returns the IDs of the capacity reservations that the instance type's available reserved
+// offerings launch into, so that NodePools and pods can select on them. Instance types without an available reserved
+// offering can't satisfy a capacity reservation ID requirement.
+func (p *DefaultProvider) capacityReservationRequirement(it *cloudprovider.InstanceType, capacityReservations []v1beta1.CapacityReservation) *scheduling.Requirement {
+	zones := sets.New(lo.FilterMap(it.Offerings.Available(), func(o cloudprovider.Offering, _ int) (string, bool) {
+		return o.Zone, o.CapacityType == v1beta1.CapacityTypeReserved
+	})...)
+	ids := lo.FilterMap(capacityReservations, func(cr v1beta1.CapacityReservation, _ int) (string, bool) {
+		return cr.ID, cr.InstanceType == it.Name && zones.Has(cr.Zone) && cr.AvailableInstanceCount > 0 && capacityreservation.IsActive(cr, p.clk.Now())
+	})
+	if len(ids) == 0 {
+		return scheduling.NewRequirement(v1beta1.LabelCapacityReservationID, v1.NodeSelectorOpDoesNotExist)
+	}
+	return scheduling.NewRequirement(v1beta1.LabelCapacityReservationID, v1.NodeSelectorOpIn, ids...)
+}
+
+// isUnavailable returns true if the offering has recently seen an insufficient capacity error from EC2. When the
+// EC2NodeClass selects a placement group, offerings outside of the placement group's zone are also unavailable, along
+// with offerings that have seen insufficient capacity errors when launching into the placement group.
+func (p *DefaultProvider) isUnavailable(instanceType, zone, capacityType string, placementGroup *v1beta1.PlacementGroup) bool {
+	if placementGroup == nil {
+		return p.unavailableOfferings.IsUnavailable(instanceType, zone, capacityType)
+	}
+	if placementGroup.Zone != "" && placementGroup.Zone != zone {
+		return true
+	}
+	return p.unavailableOfferings.IsUnavailableForPlacementGroup(placementGroup.ID, instanceType, zone, capacityType)
+}
+
 func (p *DefaultProvider) Reset() {
 	p.instanceTypesInfo = []*ec2.InstanceTypeInfo{}
 	p.instanceTypeOfferings = map[string]sets.Set[string]{}
@@ -35,6 +35,7 @@ import (
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	clock "k8s.io/utils/clock/testing"
@@ -244,6 +245,11 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceAcceleratorName:              "inferentia",
 			v1beta1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1beta1.LabelInstanceAcceleratorCount:             "1",
+			v1beta1.LabelTenancy:                              v1beta1.TenancyDefault,
+			v1beta1.LabelInstanceBurstable:                    "false",
+			v1beta1.LabelInstanceUEFISupported:                "true",
+			v1beta1.LabelInstanceENASupport:                   "required",
+			v1beta1.LabelInstanceEBSNVMeSupport:               "required",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -254,8 +260,12 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1.LabelWindowsBuild:            v1beta1.Windows2022Build,
 		}
 
-		// Ensure that we're exercising all well known labels
-		Expect(lo.Keys(nodeSelector)).To(ContainElements(append(corev1beta1.WellKnownLabels.UnsortedList(), lo.Keys(corev1beta1.NormalizedLabels)...)))
+		// Ensure that we're exercising all well known labels except for the placement group partition, which is only set
+		// when launching into a partition placement group
+		Expect(lo.Keys(nodeSelector)).To(ContainElements(append(corev1beta1.WellKnownLabels.Difference(sets.New(
+			v1beta1.LabelCapacityReservationID,
+			v1beta1.LabelPlacementGroupPartition,
+		)).UnsortedList(), lo.Keys(corev1beta1.NormalizedLabels)...)))
 
 		var pods []*v1.Pod
 		for key, value := range nodeSelector {
@@ -295,6 +305,11 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceGPUCount:                     "1",
 			v1beta1.LabelInstanceGPUMemory:                    "16384",
 			v1beta1.LabelInstanceLocalNVME:                    "900",
+			v1beta1.LabelTenancy:                              v1beta1.TenancyDefault,
+			v1beta1.LabelInstanceBurstable:                    "false",
+			v1beta1.LabelInstanceUEFISupported:                "true",
+			v1beta1.LabelInstanceENASupport:                   "required",
+			v1beta1.LabelInstanceEBSNVMeSupport:               "required",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -311,6 +326,8 @@ var _ = Describe("InstanceTypeProvider", func() {
 					v1beta1.LabelInstanceAcceleratorCount,
 					v1beta1.LabelInstanceAcceleratorName,
 					v1beta1.LabelInstanceAcceleratorManufacturer,
+					v1beta1.LabelCapacityReservationID,
+					v1beta1.LabelPlacementGroupPartition,
 					v1.LabelWindowsBuild,
 				)).UnsortedList(), lo.Keys(corev1beta1.NormalizedLabels)...)))
 
@@ -345,6 +362,11 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceAcceleratorName:              "inferentia",
 			v1beta1.LabelInstanceAcceleratorManufacturer:      "aws",
 			v1beta1.LabelInstanceAcceleratorCount:             "1",
+			v1beta1.LabelTenancy:                              v1beta1.TenancyDefault,
+			v1beta1.LabelInstanceBurstable:                    "false",
+			v1beta1.LabelInstanceUEFISupported:                "true",
+			v1beta1.LabelInstanceENASupport:                   "required",
+			v1beta1.LabelInstanceEBSNVMeSupport:               "required",
 			// Deprecated Labels
 			v1.LabelFailureDomainBetaRegion: fake.DefaultRegion,
 			v1.LabelFailureDomainBetaZone:   "test-zone-1a",
@@ -361,6 +383,8 @@ var _ = Describe("InstanceTypeProvider", func() {
 			v1beta1.LabelInstanceGPUManufacturer,
 			v1beta1.LabelInstanceGPUMemory,
 			v1beta1.LabelInstanceLocalNVME,
+			v1beta1.LabelCapacityReservationID,
+			v1beta1.LabelPlacementGroupPartition,
 			v1.LabelWindowsBuild,
 		)).UnsortedList(), lo.Keys(corev1beta1.NormalizedLabels)...)
 		Expect(lo.Keys(nodeSelector)).To(ContainElements(expectedLabels))
@@ -795,6 +819,80 @@ var _ = Describe("InstanceTypeProvider", func() {
 		Expect(node.Labels[v1.LabelInstanceTypeStable]).To(Equal("m6idn.32xlarge"))
 		Expect(*node.Status.Capacity.StorageEphemeral()).To(Equal(resource.MustParse("7600G")))
 	})
+	It("should not use instance storage for ephemeral storage when disks are only used by containerd", func() {
+		nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0Containerd)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pod := coretest.UnschedulablePod(coretest.PodOptions{
+			ResourceRequirements: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceEphemeralStorage: resource.MustParse("5000Gi")},
+			},
+		})
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		ExpectNotScheduled(ctx, env.Client, pod)
+	})
+	It("should advertise instance-store disks as an extended resource with the ExtendedResource policy", func() {
+		nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyExtendedResource)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pod := coretest.UnschedulablePod(coretest.PodOptions{
+			ResourceRequirements: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1beta1.ResourceInstanceStoreDisks: resource.MustParse("3")},
+				Limits:   v1.ResourceList{v1beta1.ResourceInstanceStoreDisks: resource.MustParse("3")},
+			},
+		})
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		node := ExpectScheduled(ctx, env.Client, pod)
+		Expect(node.Labels[v1.LabelInstanceTypeStable]).To(Or(Equal("m6idn.32xlarge"), Equal("dl1.24xlarge")))
+		Expect(node.Status.Capacity).To(HaveKeyWithValue(v1beta1.ResourceInstanceStoreDisks, resource.MustParse("4")))
+	})
+	It("should not advertise instance-store disks without the ExtendedResource policy", func() {
+		nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+		pod := coretest.UnschedulablePod(coretest.PodOptions{
+			ResourceRequirements: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1beta1.ResourceInstanceStoreDisks: resource.MustParse("1")},
+				Limits:   v1.ResourceList{v1beta1.ResourceInstanceStoreDisks: resource.MustParse("1")},
+			},
+		})
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		ExpectNotScheduled(ctx, env.Client, pod)
+	})
+	It("should size ephemeral storage with the root volume's size policy", func() {
+		nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+			{
+				DeviceName: aws.String("/dev/xvda"),
+				EBS: &v1beta1.BlockDevice{
+					VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+						Base:    lo.ToPtr(resource.MustParse("20Gi")),
+						PerVCPU: lo.ToPtr(resource.MustParse("2Gi")),
+						Max:     lo.ToPtr(resource.MustParse("100Gi")),
+					},
+				},
+				RootVolume: true,
+			},
+		}
+		instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
+		Expect(err).To(BeNil())
+		for _, info := range instanceInfo.InstanceTypes {
+			amiFamily := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{})
+			it := instancetype.NewInstanceType(ctx,
+				info,
+				fake.DefaultRegion,
+				nodeClass.Spec.BlockDeviceMappings,
+				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
+				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
+				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
+				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
+				nodePool.Spec.Template.Spec.Kubelet.SystemReserved,
+				nodePool.Spec.Template.Spec.Kubelet.EvictionHard,
+				nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
+				amiFamily,
+				nil,
+			)
+			expected := resource.MustParse(fmt.Sprintf("%dGi", lo.Min([]int64{20 + 2*aws.Int64Value(info.VCpuInfo.DefaultVCpus), 100})))
+			Expect(it.Capacity.StorageEphemeral().Value()).To(Equal(expected.Value()), aws.StringValue(info.InstanceType))
+		}
+	})
 	It("should not set pods to 110 if using ENI-based pod density", func() {
 		instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
 		Expect(err).To(BeNil())
@@ -805,6 +903,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				fake.DefaultRegion,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -828,6 +927,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				fake.DefaultRegion,
 				windowsNodeClass.Spec.BlockDeviceMappings,
 				windowsNodeClass.Spec.InstanceStorePolicy,
+				windowsNodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -924,6 +1024,29 @@ var _ = Describe("InstanceTypeProvider", func() {
 		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
 		ExpectScheduled(ctx, env.Client, pod)
 	})
+	It("should not offer zones whose pod subnets are exhausted", func() {
+		awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+			{SubnetId: aws.String("subnet-pod1"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(0)},
+			{SubnetId: aws.String("subnet-pod2"), AvailabilityZone: aws.String("test-zone-1b"), AvailableIpAddressCount: aws.Int64(100)},
+		}})
+		nodeClass.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{{ID: "subnet-pod1"}, {ID: "subnet-pod2"}}
+		nodeClass.Status.PodSubnets = []v1beta1.Subnet{
+			{ID: "subnet-pod2", Zone: "test-zone-1b"},
+			{ID: "subnet-pod1", Zone: "test-zone-1a"},
+		}
+		_, err := awsEnv.SubnetProvider.ListPodSubnets(ctx, nodeClass) // Hydrate the available IP address counts
+		Expect(err).To(BeNil())
+		instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, nodePool.Spec.Template.Spec.Kubelet, nodeClass)
+		Expect(err).To(BeNil())
+		offerings := lo.FlatMap(instanceTypes, func(it *corecloudprovider.InstanceType, _ int) []corecloudprovider.Offering {
+			return it.Offerings.Available()
+		})
+		Expect(offerings).ToNot(BeEmpty())
+		// test-zone-1c has node subnets but no pod subnets
+		for _, offering := range offerings {
+			Expect(offering.Zone).To(Equal("test-zone-1b"))
+		}
+	})
 	Context("Overhead", func() {
 		var info *ec2.InstanceTypeInfo
 		BeforeEach(func() {
@@ -947,6 +1070,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -974,6 +1098,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -997,6 +1122,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1029,6 +1155,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1068,6 +1195,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1097,6 +1225,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1126,6 +1255,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1155,6 +1285,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1186,6 +1317,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1218,6 +1350,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1247,6 +1380,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1280,6 +1414,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1300,6 +1435,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1334,6 +1470,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1366,6 +1503,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1398,6 +1536,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1421,6 +1560,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1439,6 +1579,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1465,6 +1606,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1490,6 +1632,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1519,6 +1662,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				fake.DefaultRegion,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1553,6 +1697,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				fake.DefaultRegion,
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1571,6 +1716,42 @@ var _ = Describe("InstanceTypeProvider", func() {
 			maxPods := 0
 			Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", maxPods))
 		})
+		DescribeTable("should calculate max-pods in /28 prefixes when prefix delegation is enabled",
+			func(instanceType string, maxPods int64) {
+				ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+					PrefixDelegation: lo.ToPtr(true),
+				}))
+				instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
+				Expect(err).To(BeNil())
+				info, ok := lo.Find(instanceInfo.InstanceTypes, func(info *ec2.InstanceTypeInfo) bool {
+					return *info.InstanceType == instanceType
+				})
+				Expect(ok).To(Equal(true))
+				amiFamily := amifamily.GetAMIFamily(nodeClass.Spec.AMIFamily, &amifamily.Options{})
+				it := instancetype.NewInstanceType(ctx,
+					info,
+					fake.DefaultRegion,
+					nodeClass.Spec.BlockDeviceMappings,
+					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
+					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
+					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
+					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
+					nodePool.Spec.Template.Spec.Kubelet.SystemReserved,
+					nodePool.Spec.Template.Spec.Kubelet.EvictionHard,
+					nodePool.Spec.Template.Spec.Kubelet.EvictionSoft,
+					amiFamily,
+					nil,
+				)
+				Expect(it.Capacity.Pods().Value()).To(BeNumerically("==", maxPods))
+			},
+			// 3 * (12 - 1) * 16 + 2 = 530, capped at 110 for instance types with fewer than 30 vCPUs
+			Entry("nitro instance type with fewer than 30 vCPUs", "t3.large", int64(110)),
+			// 15 * (50 - 1) * 16 + 2 = 11762, capped at 250
+			Entry("bare metal instance type", "m5.metal", int64(250)),
+			// prefixes can't be assigned to xen instances, so 8 * (30 - 1) + 2 = 234
+			Entry("xen instance type", "p3.8xlarge", int64(234)),
+		)
 		It("should override pods-per-core value", func() {
 			instanceInfo, err := awsEnv.EC2API.DescribeInstanceTypesWithContext(ctx, &ec2.DescribeInstanceTypesInput{})
 			Expect(err).To(BeNil())
@@ -1584,6 +1765,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1610,6 +1792,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1636,6 +1819,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 					fake.DefaultRegion,
 					nodeClass.Spec.BlockDeviceMappings,
 					nodeClass.Spec.InstanceStorePolicy,
+					nodeClass.Spec.CPUOptions,
 					nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 					nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 					nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1663,6 +1847,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1681,6 +1866,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 						fake.DefaultRegion,
 						nodeClass.Spec.BlockDeviceMappings,
 						nodeClass.Spec.InstanceStorePolicy,
+						nodeClass.Spec.CPUOptions,
 						nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 						nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 						nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -2067,6 +2253,609 @@ var _ = Describe("InstanceTypeProvider", func() {
 			node := ExpectScheduled(ctx, env.Client, pod)
 			Expect(node.Labels).To(HaveKeyWithValue(corev1beta1.NodePoolLabelKey, nodePool.Name))
 		})
+		It("should create reserved offerings for instance types with an available capacity reservation", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{ID: "cr-test1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 2},
+				{ID: "cr-test2", InstanceType: "m5.large", Zone: "test-zone-1b", OwnerID: "012345678901", AvailableInstanceCount: 0},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				reserved := lo.Filter(it.Offerings, func(o corecloudprovider.Offering, _ int) bool { return o.CapacityType == v1beta1.CapacityTypeReserved })
+				if it.Name != "m5.large" {
+					Expect(reserved).To(BeEmpty())
+					continue
+				}
+				Expect(reserved).To(HaveLen(2))
+				for _, o := range reserved {
+					Expect(o.Price).To(BeNumerically("==", 0))
+					Expect(o.Available).To(Equal(o.Zone == "test-zone-1a"))
+				}
+			}
+		})
+		It("should add the capacity reservation IDs to the requirements of instance types with reserved offerings", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{ID: "cr-test1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 2},
+				{ID: "cr-test2", InstanceType: "m5.large", Zone: "test-zone-1b", OwnerID: "012345678901", AvailableInstanceCount: 0},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				requirement := it.Requirements.Get(v1beta1.LabelCapacityReservationID)
+				if it.Name != "m5.large" {
+					Expect(requirement.Operator()).To(Equal(v1.NodeSelectorOpDoesNotExist))
+					continue
+				}
+				Expect(requirement.Operator()).To(Equal(v1.NodeSelectorOpIn))
+				Expect(requirement.Values()).To(ConsistOf("cr-test1"))
+			}
+		})
+		It("should not launch non-reserved capacity when a capacity reservation ID is required", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{ID: "cr-test1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 0},
+			}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{v1beta1.CapacityTypeReserved, corev1beta1.CapacityTypeOnDemand}}},
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelCapacityReservationID, Operator: v1.NodeSelectorOpIn, Values: []string{"cr-test1"}}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(0))
+		})
+		It("should launch reserved capacity into the capacity reservation when it is allowed", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{ID: "cr-test1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 2},
+			}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{v1beta1.CapacityTypeReserved, corev1beta1.CapacityTypeSpot, corev1beta1.CapacityTypeOnDemand}}}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1beta1.CapacityTypeLabelKey, v1beta1.CapacityTypeReserved))
+			Expect(node.Labels).To(HaveKeyWithValue(v1beta1.LabelCapacityReservationID, "cr-test1"))
+			Expect(node.Labels).To(HaveKeyWithValue(v1.LabelTopologyZone, "test-zone-1a"))
+			Expect(node.Labels).To(HaveKeyWithValue(v1.LabelInstanceTypeStable, "m5.large"))
+
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType)).To(Equal(corev1beta1.CapacityTypeOnDemand))
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(1))
+			ltInput := awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Pop()
+			Expect(aws.StringValue(ltInput.LaunchTemplateData.CapacityReservationSpecification.CapacityReservationTarget.CapacityReservationId)).To(Equal("cr-test1"))
+		})
+		It("should launch into the capacity reservation selected by the capacity reservation ID requirement", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{ID: "cr-test1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 2},
+				{ID: "cr-test2", InstanceType: "m5.large", Zone: "test-zone-1b", OwnerID: "012345678901", AvailableInstanceCount: 2},
+			}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{v1beta1.CapacityTypeReserved}}},
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelCapacityReservationID, Operator: v1.NodeSelectorOpIn, Values: []string{"cr-test2"}}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1beta1.LabelCapacityReservationID, "cr-test2"))
+			Expect(node.Labels).To(HaveKeyWithValue(v1.LabelTopologyZone, "test-zone-1b"))
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(1))
+			ltInput := awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Pop()
+			Expect(aws.StringValue(ltInput.LaunchTemplateData.CapacityReservationSpecification.CapacityReservationTarget.CapacityReservationId)).To(Equal("cr-test2"))
+		})
+		It("should not launch reserved capacity when the capacity reservation is exhausted", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{ID: "cr-test1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 0},
+			}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{v1beta1.CapacityTypeReserved, corev1beta1.CapacityTypeOnDemand}}}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1beta1.CapacityTypeLabelKey, corev1beta1.CapacityTypeOnDemand))
+			Expect(node.Labels).ToNot(HaveKey(v1beta1.LabelCapacityReservationID))
+		})
+		It("should only offer capacity blocks between their start time and drain time", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{
+					ID: "cr-block1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 1,
+					ReservationType: v1beta1.CapacityReservationTypeCapacityBlock,
+					StartTime:       &metav1.Time{Time: time.Now().Add(time.Hour)},
+					EndTime:         &metav1.Time{Time: time.Now().Add(2 * time.Hour)},
+				},
+				{
+					ID: "cr-block2", InstanceType: "m5.large", Zone: "test-zone-1b", OwnerID: "012345678901", AvailableInstanceCount: 1,
+					ReservationType: v1beta1.CapacityReservationTypeCapacityBlock,
+					StartTime:       &metav1.Time{Time: time.Now().Add(-time.Hour)},
+					EndTime:         &metav1.Time{Time: time.Now().Add(10 * time.Minute)},
+				},
+				{
+					ID: "cr-block3", InstanceType: "m5.large", Zone: "test-zone-1c", OwnerID: "012345678901", AvailableInstanceCount: 1,
+					ReservationType: v1beta1.CapacityReservationTypeCapacityBlock,
+					StartTime:       &metav1.Time{Time: time.Now().Add(-time.Hour)},
+					EndTime:         &metav1.Time{Time: time.Now().Add(time.Hour)},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			it, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == "m5.large" })
+			Expect(ok).To(BeTrue())
+			reserved := lo.Filter(it.Offerings, func(o corecloudprovider.Offering, _ int) bool { return o.CapacityType == v1beta1.CapacityTypeReserved })
+			Expect(reserved).To(HaveLen(3))
+			Expect(lo.FilterMap(reserved, func(o corecloudprovider.Offering, _ int) (string, bool) { return o.Zone, o.Available })).To(ConsistOf("test-zone-1c"))
+		})
+		It("should offer scheduled capacity blocks once they become active", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{
+					ID: "cr-block1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 1,
+					ReservationType: v1beta1.CapacityReservationTypeCapacityBlock,
+					StartTime:       &metav1.Time{Time: awsEnv.Clock.Now().Add(time.Hour)},
+					EndTime:         &metav1.Time{Time: awsEnv.Clock.Now().Add(3 * time.Hour)},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			reservedAvailable := func() bool {
+				instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+				Expect(err).To(BeNil())
+				it, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == "m5.large" })
+				Expect(ok).To(BeTrue())
+				return lo.ContainsBy(it.Offerings, func(o corecloudprovider.Offering) bool {
+					return o.CapacityType == v1beta1.CapacityTypeReserved && o.Available
+				})
+			}
+			Expect(reservedAvailable()).To(BeFalse())
+			awsEnv.Clock.Step(90 * time.Minute)
+			Expect(reservedAvailable()).To(BeTrue())
+			// Capacity blocks stop being offered once Karpenter begins draining them ahead of their end time
+			awsEnv.Clock.Step(time.Hour)
+			Expect(reservedAvailable()).To(BeFalse())
+		})
+		It("should launch into a capacity block with the capacity-block market type", func() {
+			nodeClass.Status.CapacityReservations = []v1beta1.CapacityReservation{
+				{
+					ID: "cr-block1", InstanceType: "m5.large", Zone: "test-zone-1a", OwnerID: "012345678901", AvailableInstanceCount: 1,
+					ReservationType: v1beta1.CapacityReservationTypeCapacityBlock,
+					StartTime:       &metav1.Time{Time: time.Now().Add(-time.Hour)},
+					EndTime:         &metav1.Time{Time: time.Now().Add(time.Hour)},
+				},
+			}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{v1beta1.CapacityTypeReserved}}}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1beta1.CapacityTypeLabelKey, v1beta1.CapacityTypeReserved))
+			Expect(node.Labels).To(HaveKeyWithValue(v1beta1.LabelCapacityReservationID, "cr-block1"))
+
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(createFleetInput.TargetCapacitySpecification.DefaultTargetCapacityType)).To(Equal("capacity-block"))
+			Expect(createFleetInput.OnDemandOptions).To(BeNil())
+			ltInput := awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Pop()
+			Expect(aws.StringValue(ltInput.LaunchTemplateData.InstanceMarketOptions.MarketType)).To(Equal("capacity-block"))
+			Expect(aws.StringValue(ltInput.LaunchTemplateData.CapacityReservationSpecification.CapacityReservationTarget.CapacityReservationId)).To(Equal("cr-block1"))
+		})
+	})
+	Context("Allocation Strategy", func() {
+		It("should default to the lowest-price on-demand allocation strategy", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(call.OnDemandOptions.AllocationStrategy)).To(Equal(ec2.FleetOnDemandAllocationStrategyLowestPrice))
+		})
+		It("should default to the price-capacity-optimized spot allocation strategy", func() {
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.CapacityTypeSpot}}}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(call.SpotOptions.AllocationStrategy)).To(Equal(ec2.SpotAllocationStrategyPriceCapacityOptimized))
+		})
+		It("should use the spot allocation strategy configured on the EC2NodeClass", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{Spot: aws.String(ec2.SpotAllocationStrategyDiversified)}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.CapacityTypeSpot}}}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(call.SpotOptions.AllocationStrategy)).To(Equal(ec2.SpotAllocationStrategyDiversified))
+		})
+		It("should set instance type priorities on the overrides for prioritized allocation strategies", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				OnDemand:               aws.String(ec2.FleetOnDemandAllocationStrategyPrioritized),
+				InstanceTypePriorities: map[string]int32{"m5.large": 0, "m5.xlarge": 1},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			Expect(aws.StringValue(call.OnDemandOptions.AllocationStrategy)).To(Equal(ec2.FleetOnDemandAllocationStrategyPrioritized))
+			for _, ltc := range call.LaunchTemplateConfigs {
+				for _, override := range ltc.Overrides {
+					switch aws.StringValue(override.InstanceType) {
+					case "m5.large":
+						Expect(aws.Float64Value(override.Priority)).To(BeNumerically("==", 0))
+						Expect(override.Priority).ToNot(BeNil())
+					case "m5.xlarge":
+						Expect(aws.Float64Value(override.Priority)).To(BeNumerically("==", 1))
+					default:
+						Expect(override.Priority).To(BeNil())
+					}
+				}
+			}
+		})
+		It("should keep prioritized instance types when truncating the instance types passed to EC2 Fleet", func() {
+			instances := fake.MakeInstances()
+			awsEnv.EC2API.DescribeInstanceTypesOutput.Set(&ec2.DescribeInstanceTypesOutput{InstanceTypes: instances})
+			awsEnv.EC2API.DescribeInstanceTypeOfferingsOutput.Set(&ec2.DescribeInstanceTypeOfferingsOutput{
+				InstanceTypeOfferings: fake.MakeInstanceOfferings(instances),
+			})
+			Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypes(ctx)).To(Succeed())
+			Expect(awsEnv.InstanceTypesProvider.UpdateInstanceTypeOfferings(ctx)).To(Succeed())
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			its, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			reqs := scheduling.NewNodeSelectorRequirementsWithMinValues(nodePool.Spec.Template.Spec.Requirements...)
+			its = corecloudprovider.InstanceTypes(its).OrderByPrice(reqs)
+			// Prioritize the most expensive generic instance type, which would otherwise be truncated
+			prioritized, ok := lo.Find(lo.Reverse(its), func(it *corecloudprovider.InstanceType) bool {
+				return !strings.Contains(it.Name, "metal") && it.Capacity.Name(v1beta1.ResourceNVIDIAGPU, resource.DecimalSI).IsZero() &&
+					it.Capacity.Name(v1beta1.ResourceAWSNeuron, resource.DecimalSI).IsZero() && it.Capacity.Name(v1beta1.ResourceAMDGPU, resource.DecimalSI).IsZero() &&
+					it.Capacity.Name(v1beta1.ResourceHabanaGaudi, resource.DecimalSI).IsZero()
+			})
+			Expect(ok).To(BeTrue())
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{
+				OnDemand:               aws.String(ec2.FleetOnDemandAllocationStrategyPrioritized),
+				InstanceTypePriorities: map[string]int32{prioritized.Name: 0},
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			overrides := lo.FlatMap(call.LaunchTemplateConfigs, func(ltc *ec2.FleetLaunchTemplateConfigRequest, _ int) []*ec2.FleetLaunchTemplateOverridesRequest {
+				return ltc.Overrides
+			})
+			Expect(lo.Uniq(lo.Map(overrides, func(o *ec2.FleetLaunchTemplateOverridesRequest, _ int) string { return aws.StringValue(o.InstanceType) }))).To(HaveLen(60))
+			Expect(lo.ContainsBy(overrides, func(o *ec2.FleetLaunchTemplateOverridesRequest) bool {
+				return aws.StringValue(o.InstanceType) == prioritized.Name
+			})).To(BeTrue())
+		})
+		It("should pass instance types as instance requirements with attribute-based instance type selection", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{InstanceTypeSelection: aws.String(v1beta1.InstanceTypeSelectionAttributeBased)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			allowedInstanceTypes := sets.New[string]()
+			for _, ltc := range call.LaunchTemplateConfigs {
+				zones := sets.New[string]()
+				for _, override := range ltc.Overrides {
+					Expect(override.InstanceType).To(BeNil())
+					Expect(override.InstanceRequirements).ToNot(BeNil())
+					Expect(override.InstanceRequirements.VCpuCount.Min).ToNot(BeNil())
+					Expect(override.InstanceRequirements.MemoryMiB.Min).ToNot(BeNil())
+					Expect(aws.StringValue(override.InstanceRequirements.BurstablePerformance)).To(Equal(ec2.BurstablePerformanceIncluded))
+					// a single override is created per zone
+					Expect(zones.Has(aws.StringValue(override.AvailabilityZone))).To(BeFalse())
+					zones.Insert(aws.StringValue(override.AvailabilityZone))
+					allowedInstanceTypes.Insert(aws.StringValueSlice(override.InstanceRequirements.AllowedInstanceTypes)...)
+				}
+			}
+			Expect(allowedInstanceTypes.Len()).To(BeNumerically(">", 1))
+			Expect(allowedInstanceTypes.Has(node.Labels[v1.LabelInstanceTypeStable])).To(BeTrue())
+		})
+		It("should translate the vCPU and memory requirements into instance requirements", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{InstanceTypeSelection: aws.String(v1beta1.InstanceTypeSelectionAttributeBased)}
+			nodePool.Spec.Template.Spec.Requirements = append(nodePool.Spec.Template.Spec.Requirements,
+				corev1beta1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelInstanceCPU, Operator: v1.NodeSelectorOpGt, Values: []string{"1"}}},
+				corev1beta1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelInstanceCPU, Operator: v1.NodeSelectorOpLt, Values: []string{"9"}}},
+				corev1beta1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelInstanceMemory, Operator: v1.NodeSelectorOpGt, Values: []string{"2047"}}},
+				corev1beta1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelInstanceMemory, Operator: v1.NodeSelectorOpLt, Values: []string{"65537"}}},
+			)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			for _, ltc := range call.LaunchTemplateConfigs {
+				for _, override := range ltc.Overrides {
+					Expect(aws.Int64Value(override.InstanceRequirements.VCpuCount.Min)).To(BeNumerically(">=", 2))
+					Expect(aws.Int64Value(override.InstanceRequirements.VCpuCount.Max)).To(BeNumerically("==", 8))
+					Expect(aws.Int64Value(override.InstanceRequirements.MemoryMiB.Min)).To(BeNumerically(">=", 2048))
+					Expect(aws.Int64Value(override.InstanceRequirements.MemoryMiB.Max)).To(BeNumerically("==", 65536))
+				}
+			}
+		})
+		It("should not allow instance types that are excluded by the instance type requirements", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{InstanceTypeSelection: aws.String(v1beta1.InstanceTypeSelectionAttributeBased)}
+			nodePool.Spec.Template.Spec.Requirements = append(nodePool.Spec.Template.Spec.Requirements,
+				corev1beta1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelInstanceTypeStable, Operator: v1.NodeSelectorOpNotIn, Values: []string{"m5.large", "m5.xlarge"}}},
+			)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			allowedInstanceTypes := sets.New[string]()
+			for _, ltc := range call.LaunchTemplateConfigs {
+				for _, override := range ltc.Overrides {
+					// EC2 doesn't allow excluded instance types alongside allowed instance types
+					Expect(override.InstanceRequirements.ExcludedInstanceTypes).To(BeEmpty())
+					allowedInstanceTypes.Insert(aws.StringValueSlice(override.InstanceRequirements.AllowedInstanceTypes)...)
+				}
+			}
+			Expect(allowedInstanceTypes.Len()).To(BeNumerically(">", 1))
+			Expect(allowedInstanceTypes.HasAny("m5.large", "m5.xlarge")).To(BeFalse())
+		})
+		It("should set the price protection threshold to the most expensive allowed offering", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{InstanceTypeSelection: aws.String(v1beta1.InstanceTypeSelectionAttributeBased)}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: corev1beta1.CapacityTypeLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{corev1beta1.CapacityTypeOnDemand}}},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			for _, ltc := range call.LaunchTemplateConfigs {
+				for _, override := range ltc.Overrides {
+					prices := lo.FilterMap(aws.StringValueSlice(override.InstanceRequirements.AllowedInstanceTypes), func(name string, _ int) (float64, bool) {
+						return awsEnv.PricingProvider.OnDemandPrice(name)
+					})
+					Expect(override.InstanceRequirements.SpotMaxPricePercentageOverLowestPrice).To(BeNil())
+					Expect(aws.Int64Value(override.InstanceRequirements.OnDemandMaxPricePercentageOverLowestPrice)).To(BeNumerically("==", math.Ceil((lo.Max(prices)/lo.Min(prices)-1)*100)))
+				}
+			}
+		})
+		It("should not use instance requirements for explicit instance type selection", func() {
+			nodeClass.Spec.AllocationStrategy = &v1beta1.AllocationStrategy{InstanceTypeSelection: aws.String(v1beta1.InstanceTypeSelectionExplicit)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			call := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			for _, ltc := range call.LaunchTemplateConfigs {
+				for _, override := range ltc.Overrides {
+					Expect(override.InstanceType).ToNot(BeNil())
+					Expect(override.InstanceRequirements).To(BeNil())
+				}
+			}
+		})
+	})
+	Context("Placement Group", func() {
+		BeforeEach(func() {
+			nodeClass.Spec.PlacementGroupSelector = &v1beta1.PlacementGroupSelectorTerm{Name: "test-pg"}
+		})
+		It("should only offer the zone of a cluster placement group", func() {
+			nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{ID: "pg-test1", Name: "test-pg", Strategy: ec2.PlacementStrategyCluster, Zone: "test-zone-1b"}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				for _, o := range it.Offerings.Available() {
+					Expect(o.Zone).To(Equal("test-zone-1b"))
+				}
+			}
+		})
+		It("should scope insufficient capacity errors to the placement group", func() {
+			nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{ID: "pg-test1", Name: "test-pg", Strategy: ec2.PlacementStrategySpread}
+			awsEnv.EC2API.InsufficientCapacityPools.Set([]fake.CapacityPool{{CapacityType: corev1beta1.CapacityTypeOnDemand, InstanceType: "m5.large", Zone: "test-zone-1a"}})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1.LabelInstanceTypeStable: "m5.large", v1.LabelTopologyZone: "test-zone-1a"},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.UnavailableOfferingsCache.IsUnavailableForPlacementGroup("pg-test1", "m5.large", "test-zone-1a", corev1beta1.CapacityTypeOnDemand)).To(BeTrue())
+			Expect(awsEnv.UnavailableOfferingsCache.IsUnavailable("m5.large", "test-zone-1a", corev1beta1.CapacityTypeOnDemand)).To(BeFalse())
+		})
+		It("should launch into the requested partition of a partition placement group", func() {
+			nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{ID: "pg-test1", Name: "test-pg", Strategy: ec2.PlacementStrategyPartition, PartitionCount: 3}
+			nodePool.Spec.Template.Spec.Requirements = append(nodePool.Spec.Template.Spec.Requirements, corev1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1beta1.LabelPlacementGroupPartition, Operator: v1.NodeSelectorOpIn, Values: []string{"2"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1beta1.LabelPlacementGroupPartition, "2"))
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.Placement).ToNot(BeNil())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.Placement.GroupId)).To(Equal("pg-test1"))
+				Expect(aws.Int64Value(ltInput.LaunchTemplateData.Placement.PartitionNumber)).To(BeNumerically("==", 2))
+			})
+		})
+	})
+	Context("CPU Options", func() {
+		It("should reduce the cpu capacity and label of instance types when disabling multithreading", func() {
+			nodeClass.Spec.CPUOptions = &v1beta1.CPUOptions{ThreadsPerCore: aws.Int64(1)}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			m5XLarge, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == "m5.xlarge" })
+			Expect(ok).To(BeTrue())
+			Expect(m5XLarge.Capacity.Cpu().Value()).To(BeNumerically("==", 2))
+			Expect(m5XLarge.Requirements.Get(v1beta1.LabelInstanceCPU).Values()).To(ConsistOf("2"))
+			// Graviton instance types only have a single thread per core
+			c6gLarge, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == "c6g.large" })
+			Expect(ok).To(BeTrue())
+			Expect(c6gLarge.Capacity.Cpu().Value()).To(BeNumerically("==", 2))
+		})
+		It("should exclude instance types with fewer cores than the configured core count", func() {
+			nodeClass.Spec.CPUOptions = &v1beta1.CPUOptions{CoreCount: aws.Int64(4)}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			Expect(lo.Map(instanceTypes, func(it *corecloudprovider.InstanceType, _ int) string { return it.Name })).ToNot(ContainElements("m5.large", "m5.xlarge"))
+			g4dn, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == "g4dn.8xlarge" })
+			Expect(ok).To(BeTrue())
+			Expect(g4dn.Capacity.Cpu().Value()).To(BeNumerically("==", 8))
+		})
+		It("should exclude instance types that don't support AMD SEV-SNP when it is enabled", func() {
+			nodeClass.Spec.CPUOptions = &v1beta1.CPUOptions{AMDSEVSNP: aws.String(ec2.AmdSevSnpSpecificationEnabled)}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			Expect(instanceTypes).To(BeEmpty())
+		})
+		It("should set the cpu options on the launch template", func() {
+			nodeClass.Spec.CPUOptions = &v1beta1.CPUOptions{ThreadsPerCore: aws.Int64(1)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.CpuOptions).ToNot(BeNil())
+				Expect(aws.Int64Value(ltInput.LaunchTemplateData.CpuOptions.ThreadsPerCore)).To(BeNumerically("==", 1))
+				Expect(ltInput.LaunchTemplateData.CpuOptions.CoreCount).To(BeNil())
+			})
+		})
+	})
+	Context("Credit Specification", func() {
+		It("should label burstable performance instance types", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				Expect(it.Requirements.Get(v1beta1.LabelInstanceBurstable).Values()).To(ConsistOf(fmt.Sprint(lo.Contains([]string{"t3.large", "t4g.small", "t4g.medium", "t4g.xlarge"}, it.Name))))
+			}
+		})
+		It("should include the estimated surplus credit charges in the price of burstable instance types", func() {
+			nodeClass.Spec.CreditSpecification = &v1beta1.CreditSpecification{
+				CPUCredits:                  v1beta1.CPUCreditsUnlimited,
+				EstimatedSurplusUtilization: lo.ToPtr[int32](50),
+			}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, name := range []string{"t3.large", "m5.large"} {
+				it, ok := lo.Find(instanceTypes, func(it *corecloudprovider.InstanceType) bool { return it.Name == name })
+				Expect(ok).To(BeTrue())
+				price, ok := awsEnv.PricingProvider.OnDemandPrice(name)
+				Expect(ok).To(BeTrue())
+				// t3.large has 2 vCPUs that are estimated to burst half of the time
+				expected := lo.Ternary(name == "t3.large", price+2*0.5*awsEnv.PricingProvider.SurplusCreditPrice(name), price)
+				for _, o := range it.Offerings.Available().Compatible(scheduling.NewRequirements(scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, corev1beta1.CapacityTypeOnDemand))) {
+					Expect(o.Price).To(BeNumerically("~", expected))
+				}
+			}
+		})
+		It("should set the credit specification on launch templates for burstable instance types", func() {
+			nodeClass.Spec.CreditSpecification = &v1beta1.CreditSpecification{CPUCredits: v1beta1.CPUCreditsStandard}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1.LabelInstanceTypeStable: "t3.large"},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.CreditSpecification).ToNot(BeNil())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.CreditSpecification.CpuCredits)).To(Equal(v1beta1.CPUCreditsStandard))
+			})
+		})
+		It("should not set the credit specification on launch templates for other instance types", func() {
+			nodeClass.Spec.CreditSpecification = &v1beta1.CreditSpecification{CPUCredits: v1beta1.CPUCreditsStandard}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1beta1.LabelInstanceBurstable: "false"},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.CreditSpecification).To(BeNil())
+			})
+		})
+	})
+	Context("Tenancy", func() {
+		It("should label instance types with default tenancy when tenancy is unset", func() {
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				Expect(it.Requirements.Get(v1beta1.LabelTenancy).Values()).To(ConsistOf(v1beta1.TenancyDefault))
+			}
+		})
+		It("should not offer spot capacity with dedicated tenancy", func() {
+			nodeClass.Spec.Tenancy = &v1beta1.Tenancy{Type: v1beta1.TenancyDedicated}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).To(BeNil())
+			for _, it := range instanceTypes {
+				Expect(it.Requirements.Get(v1beta1.LabelTenancy).Values()).To(ConsistOf(v1beta1.TenancyDedicated))
+				for _, o := range it.Offerings {
+					Expect(o.CapacityType).ToNot(Equal(corev1beta1.CapacityTypeSpot))
+				}
+			}
+		})
+		It("should launch dedicated instances and label the node with its tenancy", func() {
+			nodeClass.Spec.Tenancy = &v1beta1.Tenancy{Type: v1beta1.TenancyDedicated}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1beta1.LabelTenancy: v1beta1.TenancyDedicated},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1beta1.LabelTenancy, v1beta1.TenancyDedicated))
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.Placement).ToNot(BeNil())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.Placement.Tenancy)).To(Equal(ec2.TenancyDedicated))
+			})
+		})
+		It("should launch onto Dedicated Hosts in a host resource group", func() {
+			nodeClass.Spec.Tenancy = &v1beta1.Tenancy{
+				Type:                 v1beta1.TenancyHost,
+				HostResourceGroupARN: aws.String("arn:aws:resource-groups:us-west-2:123456789012:group/test-hrg"),
+				HostAffinity:         aws.String(ec2.AffinityHost),
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">", 0))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.Placement).ToNot(BeNil())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.Placement.Tenancy)).To(Equal(ec2.TenancyHost))
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.Placement.HostResourceGroupArn)).To(Equal("arn:aws:resource-groups:us-west-2:123456789012:group/test-hrg"))
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.Placement.Affinity)).To(Equal(ec2.AffinityHost))
+			})
+		})
+		It("should not schedule pods that select dedicated tenancy to an EC2NodeClass with default tenancy", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{
+				NodeSelector: map[string]string{v1beta1.LabelTenancy: v1beta1.TenancyDedicated},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
 	})
 	Context("Ephemeral Storage", func() {
 		BeforeEach(func() {
@@ -2255,6 +3044,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 			// nodeClass.blockDeviceMapping.rootVolume
 			// nodeClass.blockDeviceMapping.volumeSize
 			// nodeClass.blockDeviceMapping.deviceName
+			// nodeClass.kubelet
 			nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
 				{
 					DeviceName: lo.ToPtr("/dev/xvda"),
@@ -2266,6 +3056,7 @@ var _ = Describe("InstanceTypeProvider", func() {
 				{}, // Testing the base case black EC2NodeClass
 				{Spec: v1beta1.EC2NodeClassSpec{InstanceStorePolicy: lo.ToPtr(v1beta1.InstanceStorePolicyRAID0)}},
 				{Spec: v1beta1.EC2NodeClassSpec{AMIFamily: &v1beta1.AMIFamilyUbuntu}},
+				{Spec: v1beta1.EC2NodeClassSpec{Kubelet: &corev1beta1.KubeletConfiguration{MaxPods: aws.Int32(20)}}},
 				{
 					Spec: v1beta1.EC2NodeClassSpec{BlockDeviceMappings: []*v1beta1.BlockDeviceMapping{
 						{
@@ -33,6 +33,7 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
@@ -49,19 +50,19 @@ var (
 )
 
 func NewInstanceType(ctx context.Context, info *ec2.InstanceTypeInfo, region string,
-	blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy, maxPods *int32, podsPerCore *int32,
-	kubeReserved map[string]string, systemReserved map[string]string, evictionHard map[string]string, evictionSoft map[string]string,
-	amiFamily amifamily.AMIFamily, offerings cloudprovider.Offerings) *cloudprovider.InstanceType {
+	blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy, cpuOptions *v1beta1.CPUOptions,
+	maxPods *int32, podsPerCore *int32, kubeReserved map[string]string, systemReserved map[string]string, evictionHard map[string]string,
+	evictionSoft map[string]string, amiFamily amifamily.AMIFamily, offerings cloudprovider.Offerings) *cloudprovider.InstanceType {
 
 	it := &cloudprovider.InstanceType{
 		Name:         aws.StringValue(info.InstanceType),
-		Requirements: computeRequirements(info, offerings, region, amiFamily),
+		Requirements: computeRequirements(info, cpuOptions, offerings, region, amiFamily),
 		Offerings:    offerings,
-		Capacity:     computeCapacity(ctx, info, amiFamily, blockDeviceMappings, instanceStorePolicy, maxPods, podsPerCore),
+		Capacity:     computeCapacity(ctx, info, amiFamily, blockDeviceMappings, instanceStorePolicy, cpuOptions, maxPods, podsPerCore),
 		Overhead: &cloudprovider.InstanceTypeOverhead{
-			KubeReserved:      kubeReservedResources(cpu(info), pods(ctx, info, amiFamily, maxPods, podsPerCore), ENILimitedPods(ctx, info), amiFamily, kubeReserved),
+			KubeReserved:      kubeReservedResources(cpu(info, cpuOptions), pods(ctx, info, cpuOptions, amiFamily, maxPods, podsPerCore), ENILimitedPods(ctx, info), amiFamily, kubeReserved),
 			SystemReserved:    systemReservedResources(systemReserved),
-			EvictionThreshold: evictionThreshold(memory(ctx, info), ephemeralStorage(info, amiFamily, blockDeviceMappings, instanceStorePolicy), amiFamily, evictionHard, evictionSoft),
+			EvictionThreshold: evictionThreshold(memory(ctx, info), ephemeralStorage(ctx, info, amiFamily, blockDeviceMappings, instanceStorePolicy, cpuOptions, maxPods, podsPerCore), amiFamily, evictionHard, evictionSoft),
 		},
 	}
 	if it.Requirements.Compatible(scheduling.NewRequirements(scheduling.NewRequirement(v1.LabelOSStable, v1.NodeSelectorOpIn, string(v1.Windows)))) == nil {
@@ -71,7 +72,7 @@ func NewInstanceType(ctx context.Context, info *ec2.InstanceTypeInfo, region str
 }
 
 //nolint:gocyclo
-func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Offerings, region string, amiFamily amifamily.AMIFamily) scheduling.Requirements {
+func computeRequirements(info *ec2.InstanceTypeInfo, cpuOptions *v1beta1.CPUOptions, offerings cloudprovider.Offerings, region string, amiFamily amifamily.AMIFamily) scheduling.Requirements {
 	requirements := scheduling.NewRequirements(
 		// Well Known Upstream
 		scheduling.NewRequirement(v1.LabelInstanceTypeStable, v1.NodeSelectorOpIn, aws.StringValue(info.InstanceType)),
@@ -83,7 +84,7 @@ func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Off
 		// Well Known to Karpenter
 		scheduling.NewRequirement(corev1beta1.CapacityTypeLabelKey, v1.NodeSelectorOpIn, lo.Map(offerings.Available(), func(o cloudprovider.Offering, _ int) string { return o.CapacityType })...),
 		// Well Known to AWS
-		scheduling.NewRequirement(v1beta1.LabelInstanceCPU, v1.NodeSelectorOpIn, fmt.Sprint(aws.Int64Value(info.VCpuInfo.DefaultVCpus))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceCPU, v1.NodeSelectorOpIn, fmt.Sprint(vcpus(info, cpuOptions))),
 		scheduling.NewRequirement(v1beta1.LabelInstanceCPUManufacturer, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceMemory, v1.NodeSelectorOpIn, fmt.Sprint(aws.Int64Value(info.MemoryInfo.SizeInMiB))),
 		scheduling.NewRequirement(v1beta1.LabelInstanceEBSBandwidth, v1.NodeSelectorOpDoesNotExist),
@@ -102,6 +103,10 @@ func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Off
 		scheduling.NewRequirement(v1beta1.LabelInstanceAcceleratorCount, v1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(v1beta1.LabelInstanceHypervisor, v1.NodeSelectorOpIn, aws.StringValue(info.Hypervisor)),
 		scheduling.NewRequirement(v1beta1.LabelInstanceEncryptionInTransitSupported, v1.NodeSelectorOpIn, fmt.Sprint(aws.BoolValue(info.NetworkInfo.EncryptionInTransitSupported))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceBurstable, v1.NodeSelectorOpIn, fmt.Sprint(aws.BoolValue(info.BurstablePerformanceSupported))),
+		scheduling.NewRequirement(v1beta1.LabelInstanceUEFISupported, v1.NodeSelectorOpDoesNotExist),
+		scheduling.NewRequirement(v1beta1.LabelInstanceENASupport, v1.NodeSelectorOpDoesNotExist),
+		scheduling.NewRequirement(v1beta1.LabelInstanceEBSNVMeSupport, v1.NodeSelectorOpDoesNotExist),
 	)
 	// Instance Type Labels
 	instanceFamilyParts := instanceTypeScheme.FindStringSubmatch(aws.StringValue(info.InstanceType))
@@ -156,6 +161,16 @@ func computeRequirements(info *ec2.InstanceTypeInfo, offerings cloudprovider.Off
 	if info.EbsInfo != nil && aws.StringValue(info.EbsInfo.EbsOptimizedSupport) == ec2.EbsOptimizedSupportDefault {
 		requirements.Get(v1beta1.LabelInstanceEBSBandwidth).Insert(fmt.Sprint(aws.Int64Value(info.EbsInfo.EbsOptimizedInfo.MaximumBandwidthInMbps)))
 	}
+	// AMI Compatibility, these are matched against the requirements of AMIs that were derived from their attributes
+	if len(info.SupportedBootModes) > 0 {
+		requirements.Get(v1beta1.LabelInstanceUEFISupported).Insert(fmt.Sprint(lo.Contains(aws.StringValueSlice(info.SupportedBootModes), ec2.BootModeTypeUefi)))
+	}
+	if info.NetworkInfo != nil && info.NetworkInfo.EnaSupport != nil {
+		requirements.Get(v1beta1.LabelInstanceENASupport).Insert(aws.StringValue(info.NetworkInfo.EnaSupport))
+	}
+	if info.EbsInfo != nil && info.EbsInfo.NvmeSupport != nil {
+		requirements.Get(v1beta1.LabelInstanceEBSNVMeSupport).Insert(aws.StringValue(info.EbsInfo.NvmeSupport))
+	}
 	return requirements
 }
 
@@ -179,26 +194,81 @@ func getArchitecture(info *ec2.InstanceTypeInfo) string {
 }
 
 func computeCapacity(ctx context.Context, info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily,
-	blockDeviceMapping []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy,
+	blockDeviceMapping []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy, cpuOptions *v1beta1.CPUOptions,
 	maxPods *int32, podsPerCore *int32) v1.ResourceList {
 
 	resourceList := v1.ResourceList{
-		v1.ResourceCPU:              *cpu(info),
-		v1.ResourceMemory:           *memory(ctx, info),
-		v1.ResourceEphemeralStorage: *ephemeralStorage(info, amiFamily, blockDeviceMapping, instanceStorePolicy),
-		v1.ResourcePods:             *pods(ctx, info, amiFamily, maxPods, podsPerCore),
-		v1beta1.ResourceAWSPodENI:   *awsPodENI(aws.StringValue(info.InstanceType)),
-		v1beta1.ResourceNVIDIAGPU:   *nvidiaGPUs(info),
-		v1beta1.ResourceAMDGPU:      *amdGPUs(info),
-		v1beta1.ResourceAWSNeuron:   *awsNeurons(info),
-		v1beta1.ResourceHabanaGaudi: *habanaGaudis(info),
-		v1beta1.ResourceEFA:         *efas(info),
+		v1.ResourceCPU:                     *cpu(info, cpuOptions),
+		v1.ResourceMemory:                  *memory(ctx, info),
+		v1.ResourceEphemeralStorage:        *ephemeralStorage(ctx, info, amiFamily, blockDeviceMapping, instanceStorePolicy, cpuOptions, maxPods, podsPerCore),
+		v1.ResourcePods:                    *pods(ctx, info, cpuOptions, amiFamily, maxPods, podsPerCore),
+		v1beta1.ResourceAWSPodENI:          *awsPodENI(aws.StringValue(info.InstanceType)),
+		v1beta1.ResourceNVIDIAGPU:          *nvidiaGPUs(info),
+		v1beta1.ResourceAMDGPU:             *amdGPUs(info),
+		v1beta1.ResourceAWSNeuron:          *awsNeurons(info),
+		v1beta1.ResourceHabanaGaudi:        *habanaGaudis(info),
+		v1beta1.ResourceEFA:                *efas(info),
+		v1beta1.ResourceInstanceStoreDisks: *instanceStoreDisks(info, instanceStorePolicy),
 	}
 	return resourceList
 }
 
-func cpu(info *ec2.InstanceTypeInfo) *resource.Quantity {
-	return resources.Quantity(fmt.Sprint(*info.VCpuInfo.DefaultVCpus))
+func cpu(info *ec2.InstanceTypeInfo, cpuOptions *v1beta1.CPUOptions) *resource.Quantity {
+	return resources.Quantity(fmt.Sprint(vcpus(info, cpuOptions)))
+}
+
+// vcpus returns the number of vCPUs that are enabled on the instance type once the CPU options have been applied
+func vcpus(info *ec2.InstanceTypeInfo, cpuOptions *v1beta1.CPUOptions) int64 {
+	if cpuOptions == nil || (cpuOptions.CoreCount == nil && cpuOptions.ThreadsPerCore == nil) {
+		return aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+	}
+	return lo.FromPtrOr(cpuOptions.CoreCount, defaultCores(info)) * lo.FromPtrOr(cpuOptions.ThreadsPerCore, defaultThreadsPerCore(info))
+}
+
+func defaultCores(info *ec2.InstanceTypeInfo) int64 {
+	if info.VCpuInfo.DefaultCores == nil {
+		return aws.Int64Value(info.VCpuInfo.DefaultVCpus)
+	}
+	return aws.Int64Value(info.VCpuInfo.DefaultCores)
+}
+
+func defaultThreadsPerCore(info *ec2.InstanceTypeInfo) int64 {
+	if info.VCpuInfo.DefaultThreadsPerCore != nil {
+		return aws.Int64Value(info.VCpuInfo.DefaultThreadsPerCore)
+	}
+	if cores := defaultCores(info); cores > 0 {
+		return aws.Int64Value(info.VCpuInfo.DefaultVCpus) / cores
+	}
+	return 1
+}
+
+// supportsCPUOptions returns whether the instance type can be launched with the CPU options. Cores and threads can
+// only be disabled, so counts above the instance type's defaults are never supported. Instance types that don't
+// report their valid core and thread counts are assumed to support any count up to their defaults.
+func supportsCPUOptions(info *ec2.InstanceTypeInfo, cpuOptions *v1beta1.CPUOptions) bool {
+	if cpuOptions == nil {
+		return true
+	}
+	if cpuOptions.CoreCount != nil {
+		if *cpuOptions.CoreCount > defaultCores(info) {
+			return false
+		}
+		if len(info.VCpuInfo.ValidCores) > 0 && !lo.Contains(aws.Int64ValueSlice(info.VCpuInfo.ValidCores), *cpuOptions.CoreCount) {
+			return false
+		}
+	}
+	if cpuOptions.ThreadsPerCore != nil {
+		if *cpuOptions.ThreadsPerCore > defaultThreadsPerCore(info) {
+			return false
+		}
+		if len(info.VCpuInfo.ValidThreadsPerCore) > 0 && !lo.Contains(aws.Int64ValueSlice(info.VCpuInfo.ValidThreadsPerCore), *cpuOptions.ThreadsPerCore) {
+			return false
+		}
+	}
+	if aws.StringValue(cpuOptions.AMDSEVSNP) == ec2.AmdSevSnpSpecificationEnabled {
+		return info.ProcessorInfo != nil && lo.Contains(aws.StringValueSlice(info.ProcessorInfo.SupportedFeatures), ec2.SupportedAdditionalProcessorFeatureAmdSevSnp)
+	}
+	return true
 }
 
 func memory(ctx context.Context, info *ec2.InstanceTypeInfo) *resource.Quantity {
@@ -214,31 +284,37 @@ func memory(ctx context.Context, info *ec2.InstanceTypeInfo) *resource.Quantity
 }
 
 // Setting ephemeral-storage to be either the default value, what is defined in blockDeviceMappings, or the combined size of local store volumes.
-func ephemeralStorage(info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy) *resource.Quantity {
-	// If local store disks have been configured for node ephemeral-storage, use the total size of the disks.
+func ephemeralStorage(ctx context.Context, info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily, blockDeviceMappings []*v1beta1.BlockDeviceMapping, instanceStorePolicy *v1beta1.InstanceStorePolicy,
+	cpuOptions *v1beta1.CPUOptions, maxPods *int32, podsPerCore *int32) *resource.Quantity {
+	// If local store disks have been configured for node ephemeral-storage, use the total size of the disks. The other
+	// policies leave the kubelet root directory on the root volume, so it determines the node's ephemeral-storage.
 	if lo.FromPtr(instanceStorePolicy) == v1beta1.InstanceStorePolicyRAID0 {
 		if info.InstanceStorageInfo != nil && info.InstanceStorageInfo.TotalSizeInGB != nil {
 			return resources.Quantity(fmt.Sprintf("%dG", *info.InstanceStorageInfo.TotalSizeInGB))
 		}
 	}
 	if len(blockDeviceMappings) != 0 {
+		// Volumes with a size policy are sized for the instance type in the same way as they are at launch
+		volumeSize := func(bdm *v1beta1.BlockDeviceMapping) *resource.Quantity {
+			return amifamily.VolumeSize(bdm.EBS, vcpus(info, cpuOptions), pods(ctx, info, cpuOptions, amiFamily, maxPods, podsPerCore).Value())
+		}
 		// First check if there's a root volume configured in blockDeviceMappings.
 		if blockDeviceMapping, ok := lo.Find(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
 			return bdm.RootVolume
-		}); ok && blockDeviceMapping.EBS.VolumeSize != nil {
-			return blockDeviceMapping.EBS.VolumeSize
+		}); ok && volumeSize(blockDeviceMapping) != nil {
+			return volumeSize(blockDeviceMapping)
 		}
 		switch amiFamily.(type) {
 		case *amifamily.Custom:
 			// We can't know if a custom AMI is going to have a volume size.
-			volumeSize := blockDeviceMappings[len(blockDeviceMappings)-1].EBS.VolumeSize
+			volumeSize := volumeSize(blockDeviceMappings[len(blockDeviceMappings)-1])
 			return lo.Ternary(volumeSize != nil, volumeSize, amifamily.DefaultEBS.VolumeSize)
 		default:
 			// If a block device mapping exists in the provider for the root volume, use the volume size specified in the provider. If not, use the default
 			if blockDeviceMapping, ok := lo.Find(blockDeviceMappings, func(bdm *v1beta1.BlockDeviceMapping) bool {
 				return *bdm.DeviceName == *amiFamily.EphemeralBlockDevice()
-			}); ok && blockDeviceMapping.EBS.VolumeSize != nil {
-				return blockDeviceMapping.EBS.VolumeSize
+			}); ok && volumeSize(blockDeviceMapping) != nil {
+				return volumeSize(blockDeviceMapping)
 			}
 		}
 	}
@@ -322,6 +398,16 @@ func efas(info *ec2.InstanceTypeInfo) *resource.Quantity {
 	return resources.Quantity(fmt.Sprint(count))
 }
 
+// instanceStoreDisks returns the number of instance-store disks that are advertised as an extended resource. Disks are
+// only advertised when they're left untouched for a device plugin to manage.
+func instanceStoreDisks(info *ec2.InstanceTypeInfo, instanceStorePolicy *v1beta1.InstanceStorePolicy) *resource.Quantity {
+	count := int64(0)
+	if lo.FromPtr(instanceStorePolicy) == v1beta1.InstanceStorePolicyExtendedResource && info.InstanceStorageInfo != nil {
+		count = lo.SumBy(info.InstanceStorageInfo.Disks, func(disk *ec2.DiskInfo) int64 { return lo.FromPtr(disk.Count) })
+	}
+	return resources.Quantity(fmt.Sprint(count))
+}
+
 func ENILimitedPods(ctx context.Context, info *ec2.InstanceTypeInfo) *resource.Quantity {
 	// The number of pods per node is calculated using the formula:
 	// max number of ENIs * (IPv4 Addresses per ENI -1) + 2
@@ -335,6 +421,13 @@ func ENILimitedPods(ctx context.Context, info *ec2.InstanceTypeInfo) *resource.Q
 		return resource.NewQuantity(0, resource.DecimalSI)
 	}
 	addressesPerInterface := *info.NetworkInfo.Ipv4AddressesPerInterface
+	// With prefix delegation, each secondary address slot holds a /28 prefix rather than a single address. Prefixes
+	// can only be assigned to nitro and bare metal instances, and the resulting density is capped in the same way as
+	// the EKS AMI's max-pods-calculator.sh.
+	if options.FromContext(ctx).PrefixDelegation && (aws.StringValue(info.Hypervisor) == ec2.InstanceTypeHypervisorNitro || aws.BoolValue(info.BareMetal)) {
+		pods := usableNetworkInterfaces*(addressesPerInterface-1)*subnet.IPv4PrefixSize + 2
+		return resources.Quantity(fmt.Sprint(lo.Min([]int64{pods, lo.Ternary[int64](aws.Int64Value(info.VCpuInfo.DefaultVCpus) < 30, 110, 250)})))
+	}
 	return resources.Quantity(fmt.Sprint(usableNetworkInterfaces*(addressesPerInterface-1) + 2))
 }
 
@@ -414,7 +507,7 @@ func evictionThreshold(memory *resource.Quantity, storage *resource.Quantity, am
 	return lo.Assign(overhead, override)
 }
 
-func pods(ctx context.Context, info *ec2.InstanceTypeInfo, amiFamily amifamily.AMIFamily, maxPods *int32, podsPerCore *int32) *resource.Quantity {
+func pods(ctx context.Context, info *ec2.InstanceTypeInfo, cpuOptions *v1beta1.CPUOptions, amiFamily amifamily.AMIFamily, maxPods *int32, podsPerCore *int32) *resource.Quantity {
 	var count int64
 	switch {
 	case maxPods != nil:
@@ -426,7 +519,7 @@ func pods(ctx context.Context, info *ec2.InstanceTypeInfo, amiFamily amifamily.A
 
 	}
 	if lo.FromPtr(podsPerCore) > 0 && amiFamily.FeatureFlags().PodsPerCoreEnabled {
-		count = lo.Min([]int64{int64(lo.FromPtr(podsPerCore)) * lo.FromPtr(info.VCpuInfo.DefaultVCpus), count})
+		count = lo.Min([]int64{int64(lo.FromPtr(podsPerCore)) * vcpus(info, cpuOptions), count})
 	}
 	return resources.Quantity(fmt.Sprint(count))
 }
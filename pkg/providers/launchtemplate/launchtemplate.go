@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math"
 	"net"
 	"strings"
@@ -49,6 +50,7 @@ import (
 	"github.com/aws/karpenter-provider-aws/pkg/utils"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
 )
 
@@ -61,9 +63,14 @@ type Provider interface {
 }
 
 type LaunchTemplate struct {
-	Name          string
-	InstanceTypes []*cloudprovider.InstanceType
-	ImageID       string
+	Name                    string
+	InstanceTypes           []*cloudprovider.InstanceType
+	ImageID                 string
+	CapacityReservationID   string
+	CapacityReservationType string
+	PlacementGroupID        string
+	PlacementGroupPartition int64
+	Tenancy                 string
 }
 
 type DefaultProvider struct {
@@ -119,6 +126,17 @@ func (p *DefaultProvider) EnsureAll(ctx context.Context, nodeClass *v1beta1.EC2N
 	if err != nil {
 		return nil, err
 	}
+	if nodeClass.Status.PlacementGroup != nil {
+		partition, err := getPlacementGroupPartition(nodeClass.Status.PlacementGroup, nodeClaim)
+		if err != nil {
+			return nil, err
+		}
+		options.PlacementGroupID = nodeClass.Status.PlacementGroup.ID
+		options.PlacementGroupPartition = partition
+		if partition != 0 {
+			options.Labels[v1beta1.LabelPlacementGroupPartition] = fmt.Sprint(partition)
+		}
+	}
 	resolvedLaunchTemplates, err := p.amiFamily.Resolve(nodeClass, nodeClaim, instanceTypes, capacityType, options)
 	if err != nil {
 		return nil, err
@@ -130,7 +148,16 @@ func (p *DefaultProvider) EnsureAll(ctx context.Context, nodeClass *v1beta1.EC2N
 		if err != nil {
 			return nil, err
 		}
-		launchTemplates = append(launchTemplates, &LaunchTemplate{Name: *ec2LaunchTemplate.LaunchTemplateName, InstanceTypes: resolvedLaunchTemplate.InstanceTypes, ImageID: resolvedLaunchTemplate.AMIID})
+		launchTemplates = append(launchTemplates, &LaunchTemplate{
+			Name:                    *ec2LaunchTemplate.LaunchTemplateName,
+			InstanceTypes:           resolvedLaunchTemplate.InstanceTypes,
+			ImageID:                 resolvedLaunchTemplate.AMIID,
+			CapacityReservationID:   resolvedLaunchTemplate.CapacityReservationID,
+			CapacityReservationType: resolvedLaunchTemplate.CapacityReservationType,
+			PlacementGroupID:        resolvedLaunchTemplate.PlacementGroupID,
+			PlacementGroupPartition: resolvedLaunchTemplate.PlacementGroupPartition,
+			Tenancy:                 nodeClass.TenancyType(),
+		})
 	}
 	return launchTemplates, nil
 }
@@ -150,6 +177,26 @@ func LaunchTemplateName(options *amifamily.LaunchTemplate) string {
 	return fmt.Sprintf("%s/%d", v1beta1.Group, lo.Must(hashstructure.Hash(options, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})))
 }
 
+// getPlacementGroupPartition selects the partition to launch into for partition placement groups. The partition is
+// chosen from the partitions that are compatible with the NodeClaim's requirements by hashing the NodeClaim's name, so
+// that instances are spread across partitions while retried launches of a NodeClaim reuse the same launch templates.
+// Zero is returned for other placement group strategies.
+func getPlacementGroupPartition(placementGroup *v1beta1.PlacementGroup, nodeClaim *corev1beta1.NodeClaim) (int64, error) {
+	if placementGroup.Strategy != ec2.PlacementStrategyPartition {
+		return 0, nil
+	}
+	requirement := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaim.Spec.Requirements...).Get(v1beta1.LabelPlacementGroupPartition)
+	partitions := lo.Filter(lo.RangeFrom(int64(1), int(placementGroup.PartitionCount)), func(partition int64, _ int) bool {
+		return requirement.Has(fmt.Sprint(partition))
+	})
+	if len(partitions) == 0 {
+		return 0, fmt.Errorf("no partitions of placement group %s satisfy requirement %s", placementGroup.ID, requirement)
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(nodeClaim.Name))
+	return partitions[hash.Sum32()%uint32(len(partitions))], nil
+}
+
 func (p *DefaultProvider) createAMIOptions(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, labels, tags map[string]string) (*amifamily.Options, error) {
 	// Remove any labels passed into userData that are prefixed with "node-restriction.kubernetes.io" or "kops.k8s.io" since the kubelet can't
 	// register the node with any labels from this domain: https://kubernetes.io/docs/reference/access-authn-authz/admission-controllers/#noderestriction
@@ -163,28 +210,27 @@ func (p *DefaultProvider) createAMIOptions(ctx context.Context, nodeClass *v1bet
 	if err != nil {
 		return nil, err
 	}
-	// Relying on the status rather than an API call means that Karpenter is subject to a race
-	// condition where EC2NodeClass spec changes haven't propagated to the status once a node
-	// has launched.
-	// If a user changes their EC2NodeClass and shortly after Karpenter launches a node,
-	// in the worst case, the node could be drifted and re-created.
-	// TODO @aengeda: add status generation fields to gate node creation until the status is updated from a spec change
+	// Relying on the status rather than an API call is safe since the CloudProvider doesn't launch nodes until
+	// each resolved status field has been observed for the current generation of the EC2NodeClass.
 	// Get constrained security groups
 	if len(nodeClass.Status.SecurityGroups) == 0 {
 		return nil, fmt.Errorf("no security groups are present in the status")
 	}
 	options := &amifamily.Options{
-		ClusterName:         options.FromContext(ctx).ClusterName,
-		ClusterEndpoint:     p.ClusterEndpoint,
-		ClusterCIDR:         p.ClusterCIDR.Load(),
-		InstanceProfile:     instanceProfile,
-		InstanceStorePolicy: nodeClass.Spec.InstanceStorePolicy,
-		SecurityGroups:      nodeClass.Status.SecurityGroups,
-		Tags:                tags,
-		Labels:              labels,
-		CABundle:            p.CABundle,
-		KubeDNSIP:           p.KubeDNSIP,
-		NodeClassName:       nodeClass.Name,
+		ClusterName:            options.FromContext(ctx).ClusterName,
+		ClusterEndpoint:        p.ClusterEndpoint,
+		ClusterCIDR:            p.ClusterCIDR.Load(),
+		InstanceProfile:        instanceProfile,
+		InstanceStorePolicy:    nodeClass.Spec.InstanceStorePolicy,
+		InstanceStoreMountPath: nodeClass.Spec.InstanceStoreMountPath,
+		SecurityGroups:         nodeClass.Status.SecurityGroups,
+		Tags:                   tags,
+		Labels:                 labels,
+		CABundle:               p.CABundle,
+		KubeDNSIP:              p.KubeDNSIP,
+		NodeClassName:          nodeClass.Name,
+		Tenancy:                nodeClass.Spec.Tenancy,
+		NetworkOptions:         nodeClass.Spec.NetworkOptions,
 	}
 	if nodeClass.Spec.AssociatePublicIPAddress != nil {
 		options.AssociatePublicIPAddress = nodeClass.Spec.AssociatePublicIPAddress
@@ -245,6 +291,54 @@ func (p *DefaultProvider) createLaunchTemplate(ctx context.Context, options *ami
 		launchTemplateDataTags = append(launchTemplateDataTags, &ec2.LaunchTemplateTagSpecificationRequest{ResourceType: aws.String(ec2.ResourceTypeSpotInstancesRequest), Tags: utils.MergeTags(options.Tags)})
 	}
 	networkInterfaces := p.generateNetworkInterfaces(options)
+	var capacityReservationSpecification *ec2.LaunchTemplateCapacityReservationSpecificationRequest
+	if options.CapacityReservationID != "" {
+		capacityReservationSpecification = &ec2.LaunchTemplateCapacityReservationSpecificationRequest{
+			CapacityReservationTarget: &ec2.CapacityReservationTarget{
+				CapacityReservationId: aws.String(options.CapacityReservationID),
+			},
+		}
+	}
+	var placement *ec2.LaunchTemplatePlacementRequest
+	if options.PlacementGroupID != "" || options.Tenancy != nil {
+		placement = &ec2.LaunchTemplatePlacementRequest{}
+	}
+	if options.PlacementGroupID != "" {
+		placement.GroupId = aws.String(options.PlacementGroupID)
+		placement.PartitionNumber = lo.Ternary(options.PlacementGroupPartition != 0, aws.Int64(options.PlacementGroupPartition), nil)
+	}
+	if options.Tenancy != nil {
+		placement.Tenancy = aws.String(options.Tenancy.Type)
+		placement.HostResourceGroupArn = options.Tenancy.HostResourceGroupARN
+		placement.Affinity = options.Tenancy.HostAffinity
+	}
+	var instanceMarketOptions *ec2.LaunchTemplateInstanceMarketOptionsRequest
+	// Instances can only be launched into a capacity block with the capacity-block market type
+	if options.CapacityReservationType == v1beta1.CapacityReservationTypeCapacityBlock {
+		instanceMarketOptions = &ec2.LaunchTemplateInstanceMarketOptionsRequest{
+			MarketType: aws.String("capacity-block"),
+		}
+	}
+	var cpuOptions *ec2.LaunchTemplateCpuOptionsRequest
+	if options.CPUOptions != nil {
+		cpuOptions = &ec2.LaunchTemplateCpuOptionsRequest{
+			CoreCount:      options.CPUOptions.CoreCount,
+			ThreadsPerCore: options.CPUOptions.ThreadsPerCore,
+			AmdSevSnp:      options.CPUOptions.AMDSEVSNP,
+		}
+	}
+	var creditSpecification *ec2.CreditSpecificationRequest
+	if options.CPUCredits != "" {
+		creditSpecification = &ec2.CreditSpecificationRequest{CpuCredits: aws.String(options.CPUCredits)}
+	}
+	var privateDNSNameOptions *ec2.LaunchTemplatePrivateDnsNameOptionsRequest
+	// IPv6-only instances don't have an IPv4 address to derive an IP-based hostname from
+	if options.IPv6Only() {
+		privateDNSNameOptions = &ec2.LaunchTemplatePrivateDnsNameOptionsRequest{
+			HostnameType:                    aws.String(ec2.HostnameTypeResourceName),
+			EnableResourceNameDnsAAAARecord: aws.Bool(true),
+		}
+	}
 	output, err := p.ec2api.CreateLaunchTemplateWithContext(ctx, &ec2.CreateLaunchTemplateInput{
 		LaunchTemplateName: aws.String(LaunchTemplateName(options)),
 		LaunchTemplateData: &ec2.RequestLaunchTemplateData{
@@ -265,8 +359,14 @@ func (p *DefaultProvider) createLaunchTemplate(ctx context.Context, options *ami
 				HttpPutResponseHopLimit: options.MetadataOptions.HTTPPutResponseHopLimit,
 				HttpTokens:              options.MetadataOptions.HTTPTokens,
 			},
-			NetworkInterfaces: networkInterfaces,
-			TagSpecifications: launchTemplateDataTags,
+			NetworkInterfaces:                networkInterfaces,
+			TagSpecifications:                launchTemplateDataTags,
+			CapacityReservationSpecification: capacityReservationSpecification,
+			InstanceMarketOptions:            instanceMarketOptions,
+			Placement:                        placement,
+			CpuOptions:                       cpuOptions,
+			CreditSpecification:              creditSpecification,
+			PrivateDnsNameOptions:            privateDNSNameOptions,
 		},
 		TagSpecifications: []*ec2.TagSpecification{
 			{
@@ -286,7 +386,7 @@ func (p *DefaultProvider) createLaunchTemplate(ctx context.Context, options *ami
 func (p *DefaultProvider) generateNetworkInterfaces(options *amifamily.LaunchTemplate) []*ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest {
 	if options.EFACount != 0 {
 		return lo.Times(options.EFACount, func(i int) *ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest {
-			return &ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
+			networkInterface := &ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
 				NetworkCardIndex: lo.ToPtr(int64(i)),
 				// Some networking magic to ensure that one network card has higher priority than all the others (important if an instance needs a public IP w/o adding an EIP to every network card)
 				DeviceIndex:   lo.ToPtr(lo.Ternary[int64](i == 0, 0, 1)),
@@ -296,21 +396,38 @@ func (p *DefaultProvider) generateNetworkInterfaces(options *amifamily.LaunchTem
 				// with a single EFA network interface, and we should support those use cases. Launch failures with multiple enis should be considered user misconfiguration.
 				AssociatePublicIpAddress: options.AssociatePublicIPAddress,
 			}
+			if i == 0 {
+				assignIPv6(networkInterface, options.NetworkOptions)
+			}
+			return networkInterface
 		})
 	}
 
-	if options.AssociatePublicIPAddress != nil {
-		return []*ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
-			{
-				AssociatePublicIpAddress: options.AssociatePublicIPAddress,
-				DeviceIndex:              aws.Int64(0),
-				Groups:                   lo.Map(options.SecurityGroups, func(s v1beta1.SecurityGroup, _ int) *string { return aws.String(s.ID) }),
-			},
+	if options.AssociatePublicIPAddress != nil || options.NetworkOptions != nil {
+		networkInterface := &ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
+			AssociatePublicIpAddress: options.AssociatePublicIPAddress,
+			DeviceIndex:              aws.Int64(0),
+			Groups:                   lo.Map(options.SecurityGroups, func(s v1beta1.SecurityGroup, _ int) *string { return aws.String(s.ID) }),
 		}
+		assignIPv6(networkInterface, options.NetworkOptions)
+		return []*ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{networkInterface}
 	}
 	return nil
 }
 
+// assignIPv6 requests the IPv6 prefixes and addresses of the network options for the primary network interface
+func assignIPv6(networkInterface *ec2.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest, networkOptions *v1beta1.NetworkOptions) {
+	if networkOptions == nil {
+		return
+	}
+	networkInterface.Ipv6PrefixCount = networkOptions.IPv6PrefixCount
+	// IPv6-only instances use their primary IPv6 address as the node IP
+	if lo.FromPtr(networkOptions.IPv6Only) {
+		networkInterface.Ipv6AddressCount = aws.Int64(1)
+		networkInterface.PrimaryIpv6 = aws.Bool(true)
+	}
+}
+
 func (p *DefaultProvider) blockDeviceMappings(blockDeviceMappings []*v1beta1.BlockDeviceMapping) []*ec2.LaunchTemplateBlockDeviceMappingRequest {
 	if len(blockDeviceMappings) == 0 {
 		// The EC2 API fails with empty slices and expects nil.
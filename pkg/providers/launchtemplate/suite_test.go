@@ -314,6 +314,24 @@ var _ = Describe("LaunchTemplate Provider", func() {
 		})
 	})
 	Context("Cache", func() {
+		It("should select the same placement group partition for repeated launches of a NodeClaim", func() {
+			nodeClass.Status.PlacementGroup = &v1beta1.PlacementGroup{ID: "pg-test1", Name: "test-pg", Strategy: ec2.PlacementStrategyPartition, PartitionCount: 7}
+			ExpectApplied(ctx, env.Client, nodeClass)
+			instanceTypes, err := awsEnv.InstanceTypesProvider.List(ctx, &corev1beta1.KubeletConfiguration{}, nodeClass)
+			Expect(err).To(BeNil())
+			instanceTypes = lo.Filter(instanceTypes, func(it *corecloudprovider.InstanceType, _ int) bool { return it.Name == "m5.large" })
+			nodeClaim := coretest.NodeClaim(corev1beta1.NodeClaim{Spec: corev1beta1.NodeClaimSpec{NodeClassRef: &corev1beta1.NodeClassReference{Name: nodeClass.Name}}})
+			launchTemplateNames := func() []string {
+				launchTemplates, err := awsEnv.LaunchTemplateProvider.EnsureAll(ctx, nodeClass, nodeClaim, instanceTypes, corev1beta1.CapacityTypeOnDemand, nil)
+				Expect(err).To(BeNil())
+				return lo.Map(launchTemplates, func(lt *launchtemplate.LaunchTemplate, _ int) string { return lt.Name })
+			}
+			names := launchTemplateNames()
+			for i := 0; i < 5; i++ {
+				Expect(launchTemplateNames()).To(Equal(names))
+			}
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(1))
+		})
 		It("should use same launch template for equivalent constraints", func() {
 			t1 := v1.Toleration{
 				Key:      "Abacus",
@@ -563,6 +581,52 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				ExpectTags(i.LaunchTemplateData.TagSpecifications[1].Tags, nodeClass.Spec.Tags)
 			})
 		})
+		It("should render templated tags with the values that are known at launch", func() {
+			nodeClass.Spec.Tags = map[string]string{
+				"nodepool":      "{{ .NodePool }}",
+				"capacity-type": "{{ .CapacityType }}",
+				"team":          "{{ .Labels.team }}",
+				"zone":          "{{ .Zone }}",
+			}
+			nodePool.Spec.Template.Labels = map[string]string{"team": "a"}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: v1.NodeSelectorRequirement{
+						Key:      corev1beta1.CapacityTypeLabelKey,
+						Operator: v1.NodeSelectorOpIn,
+						Values:   []string{corev1beta1.CapacityTypeSpot},
+					},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			expected := map[string]string{"nodepool": nodePool.Name, "capacity-type": corev1beta1.CapacityTypeSpot, "team": "a"}
+
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			for _, tagSpecification := range createFleetInput.TagSpecifications {
+				ExpectTags(tagSpecification.Tags, expected)
+				// The zone is chosen by EC2 Fleet, so the tag is applied once the instance has launched
+				Expect(lo.ContainsBy(tagSpecification.Tags, func(t *ec2.Tag) bool { return aws.StringValue(t.Key) == "zone" })).To(BeFalse())
+			}
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(i *ec2.CreateLaunchTemplateInput) {
+				for _, tagSpecification := range i.LaunchTemplateData.TagSpecifications {
+					ExpectTags(tagSpecification.Tags, expected)
+				}
+			})
+		})
+		It("should render templated tags with the zone when it's constrained to a single value", func() {
+			nodeClass.Spec.Tags = map[string]string{"zone": "{{ .Zone }}"}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod(coretest.PodOptions{NodeSelector: map[string]string{v1.LabelTopologyZone: "test-zone-1a"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Len()).To(Equal(1))
+			createFleetInput := awsEnv.EC2API.CreateFleetBehavior.CalledWithInput.Pop()
+			ExpectTags(createFleetInput.TagSpecifications[0].Tags, map[string]string{"zone": "test-zone-1a"})
+		})
 		It("should override default tag names", func() {
 			// these tags are defaulted, so ensure users can override them
 			nodeClass.Spec.Tags = map[string]string{
@@ -705,6 +769,71 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				Expect(aws.Int64Value(ltInput.LaunchTemplateData.BlockDeviceMappings[1].Ebs.VolumeSize)).To(BeNumerically("==", 2))
 			})
 		})
+		It("should size volumes with a volume size policy for each instance type", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+				{
+					DeviceName: aws.String("/dev/xvda"),
+					EBS: &v1beta1.BlockDevice{
+						VolumeSizePolicy: &v1beta1.VolumeSizePolicy{
+							Base:    lo.ToPtr(resource.MustParse("20Gi")),
+							PerVCPU: lo.ToPtr(resource.MustParse("1Gi")),
+						},
+					},
+					RootVolume: true,
+				},
+			}
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: v1.NodeSelectorRequirement{
+						Key:      v1.LabelInstanceTypeStable,
+						Operator: v1.NodeSelectorOpIn,
+						Values:   []string{"m5.large", "m5.xlarge"},
+					},
+				},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			// m5.large and m5.xlarge have 2 and 4 vCPUs, so they're launched with separate launch templates
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(2))
+			volumeSizes := sets.New[int64]()
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+				volumeSizes.Insert(aws.Int64Value(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize))
+			})
+			Expect(sets.List(volumeSizes)).To(Equal([]int64{22, 24}))
+		})
+		It("should create volumes from the snapshot resolved for snapshot selector terms", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.BlockDeviceMappings = []*v1beta1.BlockDeviceMapping{
+				{
+					DeviceName: aws.String("/dev/xvda"),
+					EBS: &v1beta1.BlockDevice{
+						VolumeSize: resource.NewScaledQuantity(20, resource.Giga),
+					},
+					RootVolume: true,
+				},
+				{
+					DeviceName: aws.String("/dev/xvdb"),
+					EBS: &v1beta1.BlockDevice{
+						SnapshotSelectorTerms: []v1beta1.SnapshotSelectorTerm{{Tags: map[string]string{"purpose": "image-cache"}}},
+					},
+				},
+			}
+			nodeClass.Status.Snapshots = []v1beta1.Snapshot{{DeviceName: "/dev/xvdb", ID: "snap-test1"}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings).To(HaveLen(2))
+				Expect(ltInput.LaunchTemplateData.BlockDeviceMappings[0].Ebs.SnapshotId).To(BeNil())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.BlockDeviceMappings[1].Ebs.SnapshotId)).To(Equal("snap-test1"))
+			})
+		})
 		It("should default bottlerocket second volume with root volume size", func() {
 			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
 			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
@@ -1010,6 +1139,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				"",
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1063,6 +1193,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				"",
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1089,6 +1220,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				"",
 				nodeClass.Spec.BlockDeviceMappings,
 				nodeClass.Spec.InstanceStorePolicy,
+				nodeClass.Spec.CPUOptions,
 				nodePool.Spec.Template.Spec.Kubelet.MaxPods,
 				nodePool.Spec.Template.Spec.Kubelet.PodsPerCore,
 				nodePool.Spec.Template.Spec.Kubelet.KubeReserved,
@@ -1118,6 +1250,44 @@ var _ = Describe("LaunchTemplate Provider", func() {
 			ExpectScheduled(ctx, env.Client, pod)
 			ExpectLaunchTemplatesCreatedWithUserDataContaining("--use-max-pods false", "--max-pods=10")
 		})
+		It("should specify --max-pods calculated in /28 prefixes when prefix delegation is enabled", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{
+				PrefixDelegation: lo.ToPtr(true),
+			}))
+			nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{Key: v1.LabelInstanceTypeStable, Operator: v1.NodeSelectorOpIn, Values: []string{"t3.large"}},
+			}}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			// t3.large supports 3 * (12 - 1) prefixes, so max-pods is capped at 110 rather than the ENI limit of 35
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--use-max-pods false", "--max-pods=110")
+		})
+		It("should specify --max-pods from the kubelet configuration in the EC2NodeClass", func() {
+			nodeClass.Spec.Kubelet = &corev1beta1.KubeletConfiguration{MaxPods: aws.Int32(20)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--use-max-pods false", "--max-pods=20")
+		})
+		It("should prefer the kubelet configuration in the NodePool over the EC2NodeClass", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+				MaxPods:        aws.Int32(10),
+				SystemReserved: map[string]string{string(v1.ResourceCPU): "500m"},
+			}
+			nodeClass.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
+				MaxPods:        aws.Int32(20),
+				SystemReserved: map[string]string{string(v1.ResourceCPU): "1", string(v1.ResourceMemory): "1Gi"},
+			}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--max-pods=10", "cpu=500m", "memory=1Gi")
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--max-pods=20", "cpu=1,")
+		})
 		It("should specify --system-reserved when overriding system reserved values", func() {
 			nodePool.Spec.Template.Spec.Kubelet = &corev1beta1.KubeletConfiguration{
 				SystemReserved: map[string]string{
@@ -1361,6 +1531,36 @@ var _ = Describe("LaunchTemplate Provider", func() {
 			ExpectScheduled(ctx, env.Client, pod)
 			ExpectLaunchTemplatesCreatedWithUserDataContaining("--local-disks raid0")
 		})
+		It("should mount instance-store disks under the instanceStoreMountPath on AL2", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+			nodeClass.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("setup-local-disks --dir '/mnt/local-disks' mount")
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--local-disks raid0")
+		})
+		It("should use instance-store disks only for containerd on AL2", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0Containerd)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("mdadm --create", "mount --bind /mnt/instance-store/containerd /var/lib/containerd")
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--local-disks raid0", "/var/lib/kubelet")
+		})
+		It("should leave instance-store disks untouched with the ExtendedResource policy on AL2", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyExtendedResource)
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataNotContaining("--local-disks", "setup-local-disks", "mdadm")
+		})
 		Context("Bottlerocket", func() {
 			BeforeEach(func() {
 				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
@@ -1404,6 +1604,59 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				// This will not be scheduled since we were pointed to a non-existent EC2NodeClass resource.
 				ExpectNotScheduled(ctx, env.Client, pod)
 			})
+			DescribeTable(
+				"should configure ephemeral storage with bootstrap commands",
+				func(policy v1beta1.InstanceStorePolicy, dirs []string) {
+					nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+					ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+					pod := coretest.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+						config := &bootstrap.BottlerocketConfig{}
+						Expect(config.UnmarshalTOML([]byte(userData))).To(Succeed())
+						Expect(config.SettingsRaw).To(HaveKey("bootstrap-commands"))
+						commands := config.SettingsRaw["bootstrap-commands"].(map[string]interface{})
+						Expect(commands).To(HaveKey("000-karpenter-ephemeral-storage"))
+						command := commands["000-karpenter-ephemeral-storage"].(map[string]interface{})
+						Expect(command["essential"]).To(BeTrue())
+						Expect(command["commands"]).To(Equal([]interface{}{
+							[]interface{}{"apiclient", "ephemeral-storage", "init"},
+							lo.Map(append([]string{"apiclient", "ephemeral-storage", "bind", "--dirs"}, dirs...), func(s string, _ int) interface{} { return s }),
+						}))
+					}
+				},
+				Entry("RAID0", v1beta1.InstanceStorePolicyRAID0, []string{"/var/lib/containerd", "/var/lib/kubelet", "/var/log/pods"}),
+				Entry("RAID0Containerd", v1beta1.InstanceStorePolicyRAID0Containerd, []string{"/var/lib/containerd"}),
+				Entry("Mount", v1beta1.InstanceStorePolicyMount, []string{"/mnt/k8s-disks"}),
+			)
+			It("should bind instance-store disks to the instanceStoreMountPath with the Mount policy", func() {
+				nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyMount)
+				nodeClass.Spec.InstanceStoreMountPath = lo.ToPtr("/mnt/local-disks")
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML([]byte(userData))).To(Succeed())
+					commands := config.SettingsRaw["bootstrap-commands"].(map[string]interface{})
+					command := commands["000-karpenter-ephemeral-storage"].(map[string]interface{})
+					Expect(command["commands"]).To(ContainElement([]interface{}{"apiclient", "ephemeral-storage", "bind", "--dirs", "/mnt/local-disks"}))
+				}
+			})
+			It("should not configure ephemeral storage with the ExtendedResource policy", func() {
+				nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyExtendedResource)
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML([]byte(userData))).To(Succeed())
+					Expect(config.SettingsRaw).ToNot(HaveKey("bootstrap-commands"))
+				}
+			})
 			It("should not bootstrap on invalid toml user data", func() {
 				nodeClass.Spec.UserData = aws.String("#/bin/bash\n ./not-toml.sh")
 				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
@@ -1791,8 +2044,30 @@ var _ = Describe("LaunchTemplate Provider", func() {
 					}),
 				)
 			})
-			It("should set LocalDiskStrategy to Raid0 when specified by the InstanceStorePolicy", func() {
-				nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0)
+			DescribeTable(
+				"should set the LocalStorage strategy from the InstanceStorePolicy",
+				func(policy v1beta1.InstanceStorePolicy, strategy admv1alpha1.LocalStorageStrategy) {
+					nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(policy)
+					ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+					pod := coretest.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+					ExpectScheduled(ctx, env.Client, pod)
+					for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+						configs := ExpectUserDataCreatedWithNodeConfigs(userData)
+						Expect(len(configs)).To(Equal(1))
+						Expect(configs[0].Spec.Instance.LocalStorage.Strategy).To(Equal(strategy))
+						archive, err := mime.NewArchive(userData)
+						Expect(err).To(BeNil())
+						Expect(lo.ContainsBy([]mime.Entry(archive), func(entry mime.Entry) bool {
+							return entry.ContentType == mime.ContentTypeShellScript
+						})).To(BeFalse())
+					}
+				},
+				Entry("RAID0", v1beta1.InstanceStorePolicyRAID0, admv1alpha1.LocalStorageRAID0),
+				Entry("Mount", v1beta1.InstanceStorePolicyMount, admv1alpha1.LocalStorageMount),
+			)
+			It("should use instance-store disks only for containerd with a shell script", func() {
+				nodeClass.Spec.InstanceStorePolicy = lo.ToPtr(v1beta1.InstanceStorePolicyRAID0Containerd)
 				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
 				pod := coretest.UnschedulablePod()
 				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
@@ -1800,7 +2075,14 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
 					configs := ExpectUserDataCreatedWithNodeConfigs(userData)
 					Expect(len(configs)).To(Equal(1))
-					Expect(configs[0].Spec.Instance.LocalStorage.Strategy).To(Equal(admv1alpha1.LocalStorageRAID0))
+					Expect(configs[0].Spec.Instance.LocalStorage.Strategy).To(BeEmpty())
+					archive, err := mime.NewArchive(userData)
+					Expect(err).To(BeNil())
+					scripts := lo.Filter([]mime.Entry(archive), func(entry mime.Entry, _ int) bool {
+						return entry.ContentType == mime.ContentTypeShellScript
+					})
+					Expect(scripts).To(HaveLen(1))
+					Expect(scripts[0].Content).To(ContainSubstring("mount --bind /mnt/instance-store/containerd /var/lib/containerd"))
 				}
 			})
 			DescribeTable(
@@ -1834,6 +2116,19 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				ExpectNotScheduled(ctx, env.Client, pod)
 				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(0))
 			})
+			It("should fail to create launch templates for ipv6-only instances in an ipv4 cluster", func() {
+				nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+				nodeClass.Status.Subnets = lo.Map(nodeClass.Status.Subnets, func(subnet v1beta1.Subnet, i int) v1beta1.Subnet {
+					subnet.IPv6CIDRs = []string{fmt.Sprintf("2600:1f14:c2e:%d::/64", i)}
+					subnet.IPv6Native = true
+					return subnet
+				})
+				ExpectApplied(ctx, env.Client, nodeClass, nodePool)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectNotScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(0))
+			})
 		})
 		Context("Custom AMI Selector", func() {
 			It("should use ami selector specified in EC2NodeClass", func() {
@@ -1959,7 +2254,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 				}})
 				nodeClass.Spec.AMISelectorTerms = []v1beta1.AMISelectorTerm{{Tags: map[string]string{"*": "*"}}}
 				ExpectApplied(ctx, env.Client, nodeClass)
-				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+				controller := status.NewController(env.Client, awsEnv.Clock, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider, awsEnv.PlacementGroupProvider, awsEnv.SnapshotProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceProvider, awsEnv.LaunchTemplateProvider)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				nodePool.Spec.Template.Spec.Requirements = []corev1beta1.NodeSelectorRequirementWithMinValues{
 					{
@@ -2037,7 +2332,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 					{Tags: map[string]string{"Name": "test-subnet-3"}},
 				}
 				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
-				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+				controller := status.NewController(env.Client, awsEnv.Clock, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider, awsEnv.PlacementGroupProvider, awsEnv.SnapshotProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceProvider, awsEnv.LaunchTemplateProvider)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				pod := coretest.UnschedulablePod()
 				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
@@ -2050,7 +2345,7 @@ var _ = Describe("LaunchTemplate Provider", func() {
 					{Tags: map[string]string{"Name": "test-subnet-2"}},
 				}
 				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
-				controller := status.NewController(env.Client, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.LaunchTemplateProvider)
+				controller := status.NewController(env.Client, awsEnv.Clock, awsEnv.SubnetProvider, awsEnv.SecurityGroupProvider, awsEnv.CapacityReservationProvider, awsEnv.PlacementGroupProvider, awsEnv.SnapshotProvider, awsEnv.AMIProvider, awsEnv.InstanceProfileProvider, awsEnv.InstanceProvider, awsEnv.LaunchTemplateProvider)
 				ExpectObjectReconciled(ctx, env.Client, controller, nodeClass)
 				pod := coretest.UnschedulablePod()
 				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
@@ -2156,6 +2451,102 @@ var _ = Describe("LaunchTemplate Provider", func() {
 			})
 		})
 	})
+	Context("Network Options", func() {
+		BeforeEach(func() {
+			nodeClass.Status.Subnets = lo.Map(nodeClass.Status.Subnets, func(subnet v1beta1.Subnet, i int) v1beta1.Subnet {
+				subnet.IPv6CIDRs = []string{fmt.Sprintf("2600:1f14:c2e:%d::/64", i)}
+				subnet.IPv6Native = true
+				return subnet
+			})
+		})
+		It("should assign ipv6 prefixes to the primary network interface", func() {
+			nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6PrefixCount: aws.Int64(2)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.NetworkInterfaces).To(HaveLen(1))
+				Expect(aws.Int64Value(ltInput.LaunchTemplateData.NetworkInterfaces[0].Ipv6PrefixCount)).To(BeNumerically("==", 2))
+				Expect(ltInput.LaunchTemplateData.NetworkInterfaces[0].Ipv6AddressCount).To(BeNil())
+				Expect(ltInput.LaunchTemplateData.PrivateDnsNameOptions).To(BeNil())
+			})
+		})
+		It("should assign a primary ipv6 address and resource-based hostname to ipv6-only instances", func() {
+			nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(BeNumerically(">=", 1))
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(ltInput.LaunchTemplateData.NetworkInterfaces).To(HaveLen(1))
+				Expect(aws.Int64Value(ltInput.LaunchTemplateData.NetworkInterfaces[0].Ipv6AddressCount)).To(BeNumerically("==", 1))
+				Expect(aws.BoolValue(ltInput.LaunchTemplateData.NetworkInterfaces[0].PrimaryIpv6)).To(BeTrue())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.PrivateDnsNameOptions.HostnameType)).To(Equal(ec2.HostnameTypeResourceName))
+				Expect(aws.BoolValue(ltInput.LaunchTemplateData.PrivateDnsNameOptions.EnableResourceNameDnsAAAARecord)).To(BeTrue())
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.MetadataOptions.HttpProtocolIpv6)).To(Equal("enabled"))
+			})
+		})
+		It("should specify --ip-family ipv6 for ipv6-only instances", func() {
+			nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyAL2
+			nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectLaunchTemplatesCreatedWithUserDataContaining("--ip-family ipv6")
+		})
+		It("should not launch ipv6-only instances into dual-stack subnets", func() {
+			nodeClass.Status.Subnets = lo.Map(nodeClass.Status.Subnets, func(subnet v1beta1.Subnet, _ int) v1beta1.Subnet {
+				subnet.IPv6Native = false
+				return subnet
+			})
+			nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should keep an explicitly disabled httpProtocolIPv6 for ipv6 prefixes", func() {
+			nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6PrefixCount: aws.Int64(1)}
+			nodeClass.Spec.MetadataOptions = &v1beta1.MetadataOptions{HTTPProtocolIPv6: aws.String("disabled")}
+			awsEnv.LaunchTemplateProvider.KubeDNSIP = net.ParseIP("fd4b:121b:812b::a")
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+			pod := coretest.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.ForEach(func(ltInput *ec2.CreateLaunchTemplateInput) {
+				Expect(aws.StringValue(ltInput.LaunchTemplateData.MetadataOptions.HttpProtocolIpv6)).To(Equal("disabled"))
+			})
+		})
+		Context("Bottlerocket", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.AMIFamily = &v1beta1.AMIFamilyBottlerocket
+				nodeClass.Spec.NetworkOptions = &v1beta1.NetworkOptions{IPv6Only: aws.Bool(true)}
+			})
+			It("should bootstrap ipv6-only instances with an ipv6 cluster DNS IP", func() {
+				awsEnv.LaunchTemplateProvider.KubeDNSIP = net.ParseIP("fd4b:121b:812b::a")
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectScheduled(ctx, env.Client, pod)
+				for _, userData := range ExpectUserDataExistsFromCreatedLaunchTemplates() {
+					config := &bootstrap.BottlerocketConfig{}
+					Expect(config.UnmarshalTOML([]byte(userData))).To(Succeed())
+					Expect(aws.StringValue(config.Settings.Kubernetes.ClusterDNSIP)).To(Equal("fd4b:121b:812b::a"))
+				}
+			})
+			It("should fail to create launch templates for ipv6-only instances in an ipv4 cluster", func() {
+				ExpectApplied(ctx, env.Client, nodePool, nodeClass)
+				pod := coretest.UnschedulablePod()
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectNotScheduled(ctx, env.Client, pod)
+				Expect(awsEnv.EC2API.CalledWithCreateLaunchTemplateInput.Len()).To(Equal(0))
+			})
+		})
+	})
 })
 
 // ExpectTags verifies that the expected tags are a subset of the tags found
@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+type Provider interface {
+	Get(context.Context, *v1beta1.EC2NodeClass) (*ec2.PlacementGroup, error)
+	GetZone(context.Context, *ec2.PlacementGroup) (string, error)
+}
+
+type DefaultProvider struct {
+	sync.Mutex
+	ec2api ec2iface.EC2API
+	cache  *cache.Cache
+	cm     *pretty.ChangeMonitor
+}
+
+func NewDefaultProvider(ec2api ec2iface.EC2API, cache *cache.Cache) *DefaultProvider {
+	return &DefaultProvider{
+		ec2api: ec2api,
+		cm:     pretty.NewChangeMonitor(),
+		cache:  cache,
+	}
+}
+
+// Get returns the placement group selected by the EC2NodeClass, or nil if the EC2NodeClass doesn't select one
+func (p *DefaultProvider) Get(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (*ec2.PlacementGroup, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if nodeClass.Spec.PlacementGroupSelector == nil {
+		return nil, nil
+	}
+	input := getInput(nodeClass.Spec.PlacementGroupSelector)
+	hash, err := hashstructure.Hash(input, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	if err != nil {
+		return nil, err
+	}
+	if pg, ok := p.cache.Get(fmt.Sprint(hash)); ok {
+		return pg.(*ec2.PlacementGroup), nil
+	}
+	output, err := p.ec2api.DescribePlacementGroupsWithContext(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("describing placement groups %+v, %w", input, err)
+	}
+	if len(output.PlacementGroups) > 1 {
+		return nil, fmt.Errorf("expected a single placement group, selector matched %v", lo.Map(output.PlacementGroups, func(pg *ec2.PlacementGroup, _ int) string {
+			return aws.StringValue(pg.GroupId)
+		}))
+	}
+	var placementGroup *ec2.PlacementGroup
+	if len(output.PlacementGroups) == 1 {
+		placementGroup = output.PlacementGroups[0]
+		if p.cm.HasChanged(fmt.Sprintf("placement-group/%s", nodeClass.Name), aws.StringValue(placementGroup.GroupId)) {
+			log.FromContext(ctx).
+				WithValues("placement-group", aws.StringValue(placementGroup.GroupId), "strategy", aws.StringValue(placementGroup.Strategy)).
+				V(1).Info("discovered placement group")
+		}
+	}
+	p.cache.SetDefault(fmt.Sprint(hash), placementGroup)
+	return placementGroup, nil
+}
+
+// GetZone returns the zone that a cluster placement group's instances are running in. Cluster placement groups can't
+// span zones, and the zone is fixed by the first instance launched into the group. An empty zone is returned if no
+// instances are running in the group. Empty zones aren't cached, so that the zone is discovered as soon as the first
+// instance is launched into the group.
+func (p *DefaultProvider) GetZone(ctx context.Context, placementGroup *ec2.PlacementGroup) (string, error) {
+	if aws.StringValue(placementGroup.Strategy) != ec2.PlacementStrategyCluster {
+		return "", nil
+	}
+	p.Lock()
+	defer p.Unlock()
+
+	key := fmt.Sprintf("zone/%s", aws.StringValue(placementGroup.GroupId))
+	if zone, ok := p.cache.Get(key); ok {
+		return zone.(string), nil
+	}
+	var zone string
+	if err := p.ec2api.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("placement-group-name"), Values: []*string{placementGroup.GroupName}},
+			{Name: aws.String("instance-state-name"), Values: aws.StringSlice([]string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning})},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, _ bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.Placement != nil && aws.StringValue(instance.Placement.AvailabilityZone) != "" {
+					zone = aws.StringValue(instance.Placement.AvailabilityZone)
+					return false
+				}
+			}
+		}
+		return true
+	}); err != nil {
+		return "", fmt.Errorf("describing instances in placement group %s, %w", aws.StringValue(placementGroup.GroupName), err)
+	}
+	if zone != "" {
+		p.cache.SetDefault(key, zone)
+	}
+	return zone, nil
+}
+
+func getInput(term *v1beta1.PlacementGroupSelectorTerm) *ec2.DescribePlacementGroupsInput {
+	filters := []*ec2.Filter{{Name: aws.String("state"), Values: aws.StringSlice([]string{ec2.PlacementGroupStateAvailable})}}
+	switch {
+	case term.ID != "":
+		return &ec2.DescribePlacementGroupsInput{GroupIds: aws.StringSlice([]string{term.ID}), Filters: filters}
+	case term.Name != "":
+		return &ec2.DescribePlacementGroupsInput{GroupNames: aws.StringSlice([]string{term.Name}), Filters: filters}
+	}
+	for k, v := range term.Tags {
+		if v == "*" {
+			filters = append(filters, &ec2.Filter{
+				Name:   aws.String("tag-key"),
+				Values: []*string{aws.String(k)},
+			})
+		} else {
+			filters = append(filters, &ec2.Filter{
+				Name:   aws.String(fmt.Sprintf("tag:%s", k)),
+				Values: []*string{aws.String(v)},
+			})
+		}
+	}
+	return &ec2.DescribePlacementGroupsInput{Filters: filters}
+}
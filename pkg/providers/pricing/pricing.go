@@ -46,6 +46,8 @@ type Provider interface {
 	LivenessProbe(*http.Request) error
 	InstanceTypes() []string
 	OnDemandPrice(string) (float64, bool)
+	DedicatedOnDemandPrice(string) (float64, bool)
+	SurplusCreditPrice(string) float64
 	SpotPrice(string, string) (float64, bool)
 	UpdateOnDemandPricing(context.Context) error
 	UpdateSpotPricing(context.Context) error
@@ -63,8 +65,10 @@ type DefaultProvider struct {
 	region  string
 	cm      *pretty.ChangeMonitor
 
-	muOnDemand     sync.RWMutex
-	onDemandPrices map[string]float64
+	muOnDemand              sync.RWMutex
+	onDemandPrices          map[string]float64
+	dedicatedOnDemandPrices map[string]float64
+	surplusCreditPrices     map[string]float64
 
 	muSpot             sync.RWMutex
 	spotPrices         map[string]zonal
@@ -139,6 +143,22 @@ func (p *DefaultProvider) OnDemandPrice(instanceType string) (float64, bool) {
 	return price, true
 }
 
+// DedicatedOnDemandPrice returns the last known on-demand price for a given instance type when launched with dedicated
+// tenancy. The static price list doesn't include dedicated pricing, so the shared tenancy price is returned until
+// dedicated pricing has been retrieved from the pricing API.
+func (p *DefaultProvider) DedicatedOnDemandPrice(instanceType string) (float64, bool) {
+	p.muOnDemand.RLock()
+	defer p.muOnDemand.RUnlock()
+	if price, ok := p.dedicatedOnDemandPrices[instanceType]; ok {
+		return price, true
+	}
+	price, ok := p.onDemandPrices[instanceType]
+	if !ok {
+		return 0.0, false
+	}
+	return price, true
+}
+
 // SpotPrice returns the last known spot price for a given instance type and zone, returning an error
 // if there is no known spot pricing for that instance type or zone
 func (p *DefaultProvider) SpotPrice(instanceType string, zone string) (float64, bool) {
@@ -156,11 +176,28 @@ func (p *DefaultProvider) SpotPrice(instanceType string, zone string) (float64,
 	return 0.0, false
 }
 
+// SurplusCreditPrice returns the last known price per vCPU-hour of the surplus credits that burstable performance
+// instances accrue when bursting above their baseline in unlimited mode. Until surplus credit pricing has been
+// retrieved from the pricing API for the region, the us-east-1 rates are returned.
+func (p *DefaultProvider) SurplusCreditPrice(instanceType string) float64 {
+	family := strings.Split(instanceType, ".")[0]
+	p.muOnDemand.RLock()
+	defer p.muOnDemand.RUnlock()
+	if price, ok := p.surplusCreditPrices[family]; ok {
+		return price
+	}
+	// Graviton based burstable instances are charged at a lower rate
+	if family == "t4g" {
+		return 0.04
+	}
+	return 0.05
+}
+
 func (p *DefaultProvider) UpdateOnDemandPricing(ctx context.Context) error {
 	// standard on-demand instances
 	var wg sync.WaitGroup
-	var onDemandPrices, onDemandMetalPrices map[string]float64
-	var onDemandErr, onDemandMetalErr error
+	var onDemandPrices, onDemandMetalPrices, onDemandDedicatedPrices, surplusCreditPrices map[string]float64
+	var onDemandErr, onDemandMetalErr, onDemandDedicatedErr, surplusCreditErr error
 
 	// if we are in isolated vpc, skip updating on demand pricing
 	// as pricing api may not be available
@@ -207,9 +244,33 @@ func (p *DefaultProvider) UpdateOnDemandPricing(ctx context.Context) error {
 			})
 	}()
 
+	// dedicated tenancy on-demand prices
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		onDemandDedicatedPrices, onDemandDedicatedErr = p.fetchOnDemandPricing(ctx,
+			&pricing.Filter{
+				Field: aws.String("tenancy"),
+				Type:  aws.String("TERM_MATCH"),
+				Value: aws.String("Dedicated"),
+			},
+			&pricing.Filter{
+				Field: aws.String("productFamily"),
+				Type:  aws.String("TERM_MATCH"),
+				Value: aws.String("Compute Instance"),
+			})
+	}()
+
+	// surplus credit prices for burstable performance instances in unlimited mode
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		surplusCreditPrices, surplusCreditErr = p.fetchSurplusCreditPricing(ctx)
+	}()
+
 	wg.Wait()
 
-	err := multierr.Append(onDemandErr, onDemandMetalErr)
+	err := multierr.Combine(onDemandErr, onDemandMetalErr, onDemandDedicatedErr, surplusCreditErr)
 	if err != nil {
 		return fmt.Errorf("retreiving on-demand pricing data, %w", err)
 	}
@@ -222,6 +283,18 @@ func (p *DefaultProvider) UpdateOnDemandPricing(ctx context.Context) error {
 	if p.cm.HasChanged("on-demand-prices", p.onDemandPrices) {
 		log.FromContext(ctx).WithValues("instance-type-count", len(p.onDemandPrices)).V(1).Info("updated on-demand pricing")
 	}
+	// bare metal instances are always priced with dedicated tenancy
+	p.dedicatedOnDemandPrices = lo.Assign(onDemandDedicatedPrices, onDemandMetalPrices)
+	if p.cm.HasChanged("dedicated-on-demand-prices", p.dedicatedOnDemandPrices) {
+		log.FromContext(ctx).WithValues("instance-type-count", len(p.dedicatedOnDemandPrices)).V(1).Info("updated dedicated on-demand pricing")
+	}
+	// not every region publishes surplus credit pricing, so retain the previous prices if none were found
+	if len(surplusCreditPrices) != 0 {
+		p.surplusCreditPrices = surplusCreditPrices
+		if p.cm.HasChanged("surplus-credit-prices", p.surplusCreditPrices) {
+			log.FromContext(ctx).WithValues("instance-family-count", len(p.surplusCreditPrices)).V(1).Info("updated surplus credit pricing")
+		}
+	}
 	return nil
 }
 
@@ -266,7 +339,51 @@ func (p *DefaultProvider) fetchOnDemandPricing(ctx context.Context, additionalFi
 			Filters:     filters,
 			ServiceCode: aws.String("AmazonEC2"),
 		},
-		p.onDemandPage(ctx, prices),
+		p.onDemandPage(ctx, prices, func(instanceType, _ string) string { return instanceType }),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// fetchSurplusCreditPricing retrieves the per vCPU-hour price of surplus CPU credits, keyed by the burstable
+// performance instance family that the credits are charged for
+func (p *DefaultProvider) fetchSurplusCreditPricing(ctx context.Context) (map[string]float64, error) {
+	prices := map[string]float64{}
+	err := p.pricing.GetProductsPagesWithContext(
+		ctx,
+		&pricing.GetProductsInput{
+			Filters: []*pricing.Filter{
+				{
+					Field: aws.String("regionCode"),
+					Type:  aws.String("TERM_MATCH"),
+					Value: aws.String(p.region),
+				},
+				{
+					Field: aws.String("serviceCode"),
+					Type:  aws.String("TERM_MATCH"),
+					Value: aws.String("AmazonEC2"),
+				},
+				{
+					Field: aws.String("operatingSystem"),
+					Type:  aws.String("TERM_MATCH"),
+					Value: aws.String("Linux"),
+				},
+				{
+					Field: aws.String("productFamily"),
+					Type:  aws.String("TERM_MATCH"),
+					Value: aws.String("CPU Credits"),
+				},
+			},
+			ServiceCode: aws.String("AmazonEC2"),
+		},
+		// the usage type of CPU credits is prefixed with the region, e.g. USE2-CPUCredits:t3
+		p.onDemandPage(ctx, prices, func(_, usageType string) string {
+			_, family, _ := strings.Cut(usageType, "CPUCredits:")
+			return family
+		}),
 	)
 	if err != nil {
 		return nil, err
@@ -303,12 +420,13 @@ func (p *DefaultProvider) spotPage(ctx context.Context, prices map[string]map[st
 // turning off cyclo here, it measures as a 12 due to all of the type checks of the pricing data which returns a deeply
 // nested map[string]interface{}
 // nolint: gocyclo
-func (p *DefaultProvider) onDemandPage(ctx context.Context, prices map[string]float64) func(output *pricing.GetProductsOutput, b bool) bool {
+func (p *DefaultProvider) onDemandPage(ctx context.Context, prices map[string]float64, key func(instanceType, usageType string) string) func(output *pricing.GetProductsOutput, b bool) bool {
 	// this isn't the full pricing struct, just the portions we care about
 	type priceItem struct {
 		Product struct {
 			Attributes struct {
 				InstanceType string
+				UsageType    string `json:"usagetype"`
 			}
 		}
 		Terms struct {
@@ -336,7 +454,8 @@ func (p *DefaultProvider) onDemandPage(ctx context.Context, prices map[string]fl
 			if err := dec.Decode(&pItem); err != nil {
 				log.FromContext(ctx).Error(err, "failed decoding pricing data")
 			}
-			if pItem.Product.Attributes.InstanceType == "" {
+			name := key(pItem.Product.Attributes.InstanceType, pItem.Product.Attributes.UsageType)
+			if name == "" {
 				continue
 			}
 			for _, term := range pItem.Terms.OnDemand {
@@ -345,7 +464,7 @@ func (p *DefaultProvider) onDemandPage(ctx context.Context, prices map[string]fl
 					if err != nil || price == 0 {
 						continue
 					}
-					prices[pItem.Product.Attributes.InstanceType] = price
+					prices[name] = price
 				}
 			}
 		}
@@ -426,6 +545,8 @@ func (p *DefaultProvider) Reset() {
 	}
 
 	p.onDemandPrices = staticPricing
+	p.dedicatedOnDemandPrices = map[string]float64{}
+	p.surplusCreditPrices = map[string]float64{}
 	// default our spot pricing to the same as the on-demand pricing until a price update
 	p.spotPrices = populateInitialSpotPricing(staticPricing)
 	p.spotPricingUpdated = false
@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+)
+
+type Provider interface {
+	List(context.Context, *v1beta1.EC2NodeClass) (map[string]*ec2.Snapshot, error)
+}
+
+type DefaultProvider struct {
+	sync.Mutex
+	ec2api ec2iface.EC2API
+	cache  *cache.Cache
+	cm     *pretty.ChangeMonitor
+}
+
+func NewDefaultProvider(ec2api ec2iface.EC2API, cache *cache.Cache) *DefaultProvider {
+	return &DefaultProvider{
+		ec2api: ec2api,
+		cm:     pretty.NewChangeMonitor(),
+		cache:  cache,
+	}
+}
+
+// List returns the most recently created snapshot that's selected by each block device mapping with snapshot
+// selector terms, keyed by device name. Block device mappings that don't select any snapshot are omitted.
+func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) (map[string]*ec2.Snapshot, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	snapshots := map[string]*ec2.Snapshot{}
+	for _, blockDeviceMapping := range nodeClass.Spec.BlockDeviceMappings {
+		if blockDeviceMapping.EBS == nil || len(blockDeviceMapping.EBS.SnapshotSelectorTerms) == 0 {
+			continue
+		}
+		candidates, err := p.getSnapshots(ctx, getQueries(blockDeviceMapping.EBS.SnapshotSelectorTerms))
+		if err != nil {
+			return nil, err
+		}
+		if snapshot := newest(candidates); snapshot != nil {
+			snapshots[aws.StringValue(blockDeviceMapping.DeviceName)] = snapshot
+		}
+	}
+	if len(snapshots) > 0 && p.cm.HasChanged(fmt.Sprintf("snapshots/%s", nodeClass.Name), lo.MapValues(snapshots, func(s *ec2.Snapshot, _ string) string {
+		return aws.StringValue(s.SnapshotId)
+	})) {
+		log.FromContext(ctx).
+			WithValues("snapshots", lo.MapValues(snapshots, func(s *ec2.Snapshot, _ string) string {
+				return aws.StringValue(s.SnapshotId)
+			})).
+			V(1).Info("discovered snapshots")
+	}
+	return snapshots, nil
+}
+
+func (p *DefaultProvider) getSnapshots(ctx context.Context, queries []*ec2.DescribeSnapshotsInput) ([]*ec2.Snapshot, error) {
+	hash, err := hashstructure.Hash(queries, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true})
+	if err != nil {
+		return nil, err
+	}
+	if snapshots, ok := p.cache.Get(fmt.Sprint(hash)); ok {
+		// Ensure what's returned from this function is a shallow-copy of the slice (not a deep-copy of the data itself)
+		// so that modifications to the ordering of the data don't affect the original
+		return append([]*ec2.Snapshot{}, snapshots.([]*ec2.Snapshot)...), nil
+	}
+	snapshots := map[string]*ec2.Snapshot{}
+	for _, query := range queries {
+		if err := p.ec2api.DescribeSnapshotsPagesWithContext(ctx, query, func(page *ec2.DescribeSnapshotsOutput, _ bool) bool {
+			for i := range page.Snapshots {
+				snapshots[lo.FromPtr(page.Snapshots[i].SnapshotId)] = page.Snapshots[i]
+			}
+			return true
+		}); err != nil {
+			return nil, fmt.Errorf("describing snapshots %+v, %w", queries, err)
+		}
+	}
+	p.cache.SetDefault(fmt.Sprint(hash), lo.Values(snapshots))
+	return lo.Values(snapshots), nil
+}
+
+// newest returns the most recently created snapshot, breaking ties by id so that the result is deterministic
+func newest(snapshots []*ec2.Snapshot) *ec2.Snapshot {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return lo.MaxBy(snapshots, func(a, b *ec2.Snapshot) bool {
+		if !aws.TimeValue(a.StartTime).Equal(aws.TimeValue(b.StartTime)) {
+			return aws.TimeValue(a.StartTime).After(aws.TimeValue(b.StartTime))
+		}
+		return aws.StringValue(a.SnapshotId) > aws.StringValue(b.SnapshotId)
+	})
+}
+
+// getQueries returns the set of DescribeSnapshots requests needed to resolve the selector terms. Only completed
+// snapshots are selected, since volumes can't be created from snapshots that are still pending. The owner defaults to
+// self, so that Karpenter only discovers public or shared snapshots if the user specifically allows it.
+func getQueries(terms []v1beta1.SnapshotSelectorTerm) (res []*ec2.DescribeSnapshotsInput) {
+	for _, term := range terms {
+		query := &ec2.DescribeSnapshotsInput{
+			Filters:  []*ec2.Filter{{Name: aws.String("status"), Values: aws.StringSlice([]string{ec2.SnapshotStateCompleted})}},
+			OwnerIds: aws.StringSlice([]string{lo.Ternary(term.Owner != "", term.Owner, "self")}),
+		}
+		if term.Name != "" {
+			query.Filters = append(query.Filters, &ec2.Filter{
+				Name:   aws.String("tag:Name"),
+				Values: []*string{aws.String(term.Name)},
+			})
+		}
+		for k, v := range term.Tags {
+			if v == "*" {
+				query.Filters = append(query.Filters, &ec2.Filter{
+					Name:   aws.String("tag-key"),
+					Values: []*string{aws.String(k)},
+				})
+			} else {
+				query.Filters = append(query.Filters, &ec2.Filter{
+					Name:   aws.String(fmt.Sprintf("tag:%s", k)),
+					Values: []*string{aws.String(v)},
+				})
+			}
+		}
+		res = append(res, query)
+	}
+	return res
+}
@@ -26,9 +26,11 @@ import (
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
+	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
@@ -37,11 +39,17 @@ import (
 type Provider interface {
 	LivenessProbe(*http.Request) error
 	List(context.Context, *v1beta1.EC2NodeClass) ([]*ec2.Subnet, error)
+	ListPodSubnets(context.Context, *v1beta1.EC2NodeClass) ([]*ec2.Subnet, error)
+	PodSubnetZones(context.Context, *v1beta1.EC2NodeClass) sets.Set[string]
 	AssociatePublicIPAddressValue(*v1beta1.EC2NodeClass) *bool
 	ZonalSubnetsForLaunch(context.Context, *v1beta1.EC2NodeClass, []*cloudprovider.InstanceType, string) (map[string]*Subnet, error)
-	UpdateInflightIPs(*ec2.CreateFleetInput, *ec2.CreateFleetOutput, []*cloudprovider.InstanceType, []*Subnet, string)
+	UpdateInflightIPs(context.Context, *ec2.CreateFleetInput, *ec2.CreateFleetOutput, []*cloudprovider.InstanceType, []*Subnet, string)
 }
 
+// IPv4PrefixSize is the number of IPv4 addresses in each /28 prefix that the VPC CNI assigns to a network interface
+// when prefix delegation is enabled
+const IPv4PrefixSize = 16
+
 type DefaultProvider struct {
 	sync.Mutex
 	ec2api                        ec2iface.EC2API
@@ -56,6 +64,9 @@ type Subnet struct {
 	ID                      string
 	Zone                    string
 	AvailableIPAddressCount int64
+	// PodSubnet is the subnet in the same zone that pods are assigned IP addresses from, when the EC2NodeClass selects
+	// pod subnets
+	PodSubnet *Subnet
 }
 
 func NewDefaultProvider(ec2api ec2iface.EC2API, cache *cache.Cache, availableIPAddressCache *cache.Cache, associatePublicIPAddressCache *cache.Cache) *DefaultProvider {
@@ -73,9 +84,18 @@ func NewDefaultProvider(ec2api ec2iface.EC2API, cache *cache.Cache, availableIPA
 }
 
 func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) ([]*ec2.Subnet, error) {
+	return p.list(ctx, "subnets", nodeClass.Name, nodeClass.Spec.SubnetSelectorTerms)
+}
+
+// ListPodSubnets returns the subnets selected by the pod subnet selector terms of the EC2NodeClass
+func (p *DefaultProvider) ListPodSubnets(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) ([]*ec2.Subnet, error) {
+	return p.list(ctx, "pod-subnets", nodeClass.Name, nodeClass.Spec.PodSubnetSelectorTerms)
+}
+
+func (p *DefaultProvider) list(ctx context.Context, kind string, nodeClassName string, terms []v1beta1.SubnetSelectorTerm) ([]*ec2.Subnet, error) {
 	p.Lock()
 	defer p.Unlock()
-	filterSets := getFilterSets(nodeClass.Spec.SubnetSelectorTerms)
+	filterSets := getFilterSets(terms)
 	if len(filterSets) == 0 {
 		return []*ec2.Subnet{}, nil
 	}
@@ -106,9 +126,9 @@ func (p *DefaultProvider) List(ctx context.Context, nodeClass *v1beta1.EC2NodeCl
 		}
 	}
 	p.cache.SetDefault(fmt.Sprint(hash), lo.Values(subnets))
-	if p.cm.HasChanged(fmt.Sprintf("subnets/%s", nodeClass.Name), subnets) {
+	if p.cm.HasChanged(fmt.Sprintf("%s/%s", kind, nodeClassName), subnets) {
 		log.FromContext(ctx).
-			WithValues("subnets", lo.Map(lo.Values(subnets), func(s *ec2.Subnet, _ int) string {
+			WithValues(kind, lo.Map(lo.Values(subnets), func(s *ec2.Subnet, _ int) string {
 				return fmt.Sprintf("%s (%s)", aws.StringValue(s.SubnetId), aws.StringValue(s.AvailabilityZone))
 			})).
 			V(1).Info("discovered subnets")
@@ -129,54 +149,54 @@ func (p *DefaultProvider) AssociatePublicIPAddressValue(nodeClass *v1beta1.EC2No
 	return lo.ToPtr(false)
 }
 
-// ZonalSubnetsForLaunch returns a mapping of zone to the subnet with the most available IP addresses and deducts the passed ips from the available count
+// ZonalSubnetsForLaunch returns a mapping of zone to the subnet with the most available IP addresses and deducts the passed ips from the available count.
+// When the EC2NodeClass selects pod subnets, the IPs for pods are deducted from the pod subnet with the most available IP addresses in each zone
+// instead, and zones whose pod subnets can't fit the pods are left out.
 func (p *DefaultProvider) ZonalSubnetsForLaunch(ctx context.Context, nodeClass *v1beta1.EC2NodeClass, instanceTypes []*cloudprovider.InstanceType, capacityType string) (map[string]*Subnet, error) {
-	if len(nodeClass.Status.Subnets) == 0 {
+	if len(nodeClass.LaunchSubnets()) == 0 {
 		return nil, fmt.Errorf("no subnets matched selector %v", nodeClass.Spec.SubnetSelectorTerms)
 	}
 
 	p.Lock()
 	defer p.Unlock()
 
-	zonalSubnets := map[string]*Subnet{}
-	availableIPAddressCount := map[string]int64{}
-	for _, subnet := range nodeClass.Status.Subnets {
-		if subnetAvailableIP, ok := p.availableIPAddressCache.Get(subnet.ID); ok {
-			availableIPAddressCount[subnet.ID] = subnetAvailableIP.(int64)
-		}
-	}
-
-	for _, subnet := range nodeClass.Status.Subnets {
-		if v, ok := zonalSubnets[subnet.Zone]; ok {
-			currentZonalSubnetIPAddressCount := v.AvailableIPAddressCount
-			newZonalSubnetIPAddressCount := availableIPAddressCount[subnet.ID]
-			if ips, ok := p.inflightIPs[v.ID]; ok {
-				currentZonalSubnetIPAddressCount = ips
-			}
-			if ips, ok := p.inflightIPs[subnet.ID]; ok {
-				newZonalSubnetIPAddressCount = ips
-			}
-
-			if currentZonalSubnetIPAddressCount >= newZonalSubnetIPAddressCount {
+	zonalSubnets := p.zonalSubnets(nodeClass.LaunchSubnets())
+	zonalPodSubnets := p.zonalSubnets(nodeClass.Status.PodSubnets)
+	for zone, subnet := range zonalSubnets {
+		predictedIPsUsed := p.predictedIPsUsed(ctx, instanceTypes, subnet.Zone, capacityType)
+		if len(nodeClass.Status.PodSubnets) > 0 {
+			podSubnet, ok := zonalPodSubnets[zone]
+			if !ok || p.availableIPs(podSubnet) < predictedIPsUsed {
+				delete(zonalSubnets, zone)
 				continue
 			}
+			subnet.PodSubnet = podSubnet
+			p.inflightIPs[podSubnet.ID] = p.availableIPs(podSubnet) - predictedIPsUsed
+			// Only the primary network interface is assigned an IP address from the node subnet
+			predictedIPsUsed = 1
 		}
-		zonalSubnets[subnet.Zone] = &Subnet{ID: subnet.ID, Zone: subnet.Zone, AvailableIPAddressCount: availableIPAddressCount[subnet.ID]}
+		p.inflightIPs[subnet.ID] = p.availableIPs(subnet) - predictedIPsUsed
 	}
-
-	for _, subnet := range zonalSubnets {
-		predictedIPsUsed := p.minPods(instanceTypes, subnet.Zone, capacityType)
-		prevIPs := subnet.AvailableIPAddressCount
-		if trackedIPs, ok := p.inflightIPs[subnet.ID]; ok {
-			prevIPs = trackedIPs
-		}
-		p.inflightIPs[subnet.ID] = prevIPs - predictedIPsUsed
+	if len(zonalSubnets) == 0 {
+		return nil, fmt.Errorf("no pod subnets with available IP addresses matched selector %v", nodeClass.Spec.PodSubnetSelectorTerms)
 	}
 	return zonalSubnets, nil
 }
 
+// PodSubnetZones returns the zones that have a pod subnet with IP addresses available for pods
+func (p *DefaultProvider) PodSubnetZones(ctx context.Context, nodeClass *v1beta1.EC2NodeClass) sets.Set[string] {
+	p.Lock()
+	defer p.Unlock()
+
+	// With prefix delegation, the VPC CNI can't assign an IP address to a pod unless a whole /28 prefix is available
+	minIPs := lo.Ternary[int64](options.FromContext(ctx).PrefixDelegation, IPv4PrefixSize, 1)
+	return sets.New(lo.FilterMap(lo.Values(p.zonalSubnets(nodeClass.Status.PodSubnets)), func(subnet *Subnet, _ int) (string, bool) {
+		return subnet.Zone, p.availableIPs(subnet) >= minIPs
+	})...)
+}
+
 // UpdateInflightIPs is used to refresh the in-memory IP usage by adding back unused IPs after a CreateFleet response is returned
-func (p *DefaultProvider) UpdateInflightIPs(createFleetInput *ec2.CreateFleetInput, createFleetOutput *ec2.CreateFleetOutput, instanceTypes []*cloudprovider.InstanceType,
+func (p *DefaultProvider) UpdateInflightIPs(ctx context.Context, createFleetInput *ec2.CreateFleetInput, createFleetOutput *ec2.CreateFleetOutput, instanceTypes []*cloudprovider.InstanceType,
 	subnets []*Subnet, capacityType string) {
 	p.Lock()
 	defer p.Unlock()
@@ -225,10 +245,16 @@ func (p *DefaultProvider) UpdateInflightIPs(createFleetInput *ec2.CreateFleetInp
 		// If the cached subnet IP address count hasn't changed from the original subnet used to
 		// launch the instance, then we need to update the tracked IPs
 		if originalSubnet.AvailableIPAddressCount == cachedIPAddressCount {
+			predictedIPsUsed := p.predictedIPsUsed(ctx, instanceTypes, originalSubnet.Zone, capacityType)
+			if podSubnet := originalSubnet.PodSubnet; podSubnet != nil {
+				if ips, ok := p.inflightIPs[podSubnet.ID]; ok && podSubnet.AvailableIPAddressCount == cachedAvailableIPAddressMap[podSubnet.ID] {
+					p.inflightIPs[podSubnet.ID] = ips + predictedIPsUsed
+				}
+				predictedIPsUsed = 1
+			}
 			// other IPs deducted were opportunistic and need to be readded since Fleet didn't pick those subnets to launch into
 			if ips, ok := p.inflightIPs[originalSubnet.ID]; ok {
-				minPods := p.minPods(instanceTypes, originalSubnet.Zone, capacityType)
-				p.inflightIPs[originalSubnet.ID] = ips + minPods
+				p.inflightIPs[originalSubnet.ID] = ips + predictedIPsUsed
 			}
 		}
 	}
@@ -241,6 +267,54 @@ func (p *DefaultProvider) LivenessProbe(_ *http.Request) error {
 	return nil
 }
 
+// zonalSubnets returns a mapping of zone to the subnet with the most available IP addresses, taking inflight IPs into account
+func (p *DefaultProvider) zonalSubnets(subnets []v1beta1.Subnet) map[string]*Subnet {
+	zonalSubnets := map[string]*Subnet{}
+	availableIPAddressCount := map[string]int64{}
+	for _, subnet := range subnets {
+		if subnetAvailableIP, ok := p.availableIPAddressCache.Get(subnet.ID); ok {
+			availableIPAddressCount[subnet.ID] = subnetAvailableIP.(int64)
+		}
+	}
+
+	for _, subnet := range subnets {
+		if v, ok := zonalSubnets[subnet.Zone]; ok {
+			currentZonalSubnetIPAddressCount := v.AvailableIPAddressCount
+			newZonalSubnetIPAddressCount := availableIPAddressCount[subnet.ID]
+			if ips, ok := p.inflightIPs[v.ID]; ok {
+				currentZonalSubnetIPAddressCount = ips
+			}
+			if ips, ok := p.inflightIPs[subnet.ID]; ok {
+				newZonalSubnetIPAddressCount = ips
+			}
+
+			if currentZonalSubnetIPAddressCount >= newZonalSubnetIPAddressCount {
+				continue
+			}
+		}
+		zonalSubnets[subnet.Zone] = &Subnet{ID: subnet.ID, Zone: subnet.Zone, AvailableIPAddressCount: availableIPAddressCount[subnet.ID]}
+	}
+	return zonalSubnets
+}
+
+// availableIPs returns the IP addresses that are left in the subnet after deducting inflight IPs
+func (p *DefaultProvider) availableIPs(subnet *Subnet) int64 {
+	if ips, ok := p.inflightIPs[subnet.ID]; ok {
+		return ips
+	}
+	return subnet.AvailableIPAddressCount
+}
+
+// predictedIPsUsed returns the number of subnet IP addresses that a launch is expected to consume. With prefix
+// delegation, the VPC CNI allocates addresses for pods in whole /28 prefixes, so the pod count is rounded up.
+func (p *DefaultProvider) predictedIPsUsed(ctx context.Context, instanceTypes []*cloudprovider.InstanceType, zone string, capacityType string) int64 {
+	pods := p.minPods(instanceTypes, zone, capacityType)
+	if !options.FromContext(ctx).PrefixDelegation {
+		return pods
+	}
+	return (pods + IPv4PrefixSize - 1) / IPv4PrefixSize * IPv4PrefixSize
+}
+
 func (p *DefaultProvider) minPods(instanceTypes []*cloudprovider.InstanceType, zone string, capacityType string) int64 {
 	// filter for instance types available in the zone and capacity type being requested
 	filteredInstanceTypes := lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
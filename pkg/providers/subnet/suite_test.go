@@ -23,12 +23,17 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/aws/karpenter-provider-aws/pkg/apis"
 	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/aws/karpenter-provider-aws/pkg/operator/options"
 	"github.com/aws/karpenter-provider-aws/pkg/test"
 
+	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	corefake "sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	coreoptions "sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
 	coretest "sigs.k8s.io/karpenter/pkg/test"
@@ -312,6 +317,78 @@ var _ = Describe("SubnetProvider", func() {
 			}
 		})
 	})
+	Context("ZonalSubnetsForLaunch", func() {
+		var instanceTypes []*cloudprovider.InstanceType
+		BeforeEach(func() {
+			awsEnv.EC2API.DescribeSubnetsOutput.Set(&ec2.DescribeSubnetsOutput{Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-test1"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(100)},
+				{SubnetId: aws.String("subnet-test2"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(75)},
+				{SubnetId: aws.String("subnet-test3"), AvailabilityZone: aws.String("test-zone-1a"), AvailableIpAddressCount: aws.Int64(100)},
+			}})
+			nodeClass.Status.Subnets = []v1beta1.Subnet{
+				{ID: "subnet-test1", Zone: "test-zone-1a"},
+				{ID: "subnet-test2", Zone: "test-zone-1a"},
+			}
+			instanceTypes = []*cloudprovider.InstanceType{corefake.NewInstanceType(corefake.InstanceTypeOptions{
+				Name: "test-instance-type",
+				Offerings: []cloudprovider.Offering{
+					{CapacityType: corev1beta1.CapacityTypeOnDemand, Zone: "test-zone-1a", Price: 1, Available: true},
+				},
+				Resources: v1.ResourceList{v1.ResourcePods: resource.MustParse("20")},
+			})}
+			// Call list to populate the available IP address counts of the subnets
+			_, err := awsEnv.SubnetProvider.List(ctx, nodeClass)
+			Expect(err).To(BeNil())
+		})
+		It("should deduct the pods of the smallest instance type from the inflight IPs of the chosen subnet", func() {
+			subnets, err := awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+			Expect(err).To(BeNil())
+			Expect(subnets["test-zone-1a"].ID).To(Equal("subnet-test1"))
+			// 100 - 20 = 80 IPs are left in subnet-test1, so it's still chosen over subnet-test2
+			subnets, err = awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+			Expect(err).To(BeNil())
+			Expect(subnets["test-zone-1a"].ID).To(Equal("subnet-test1"))
+		})
+		It("should deduct whole /28 prefixes from the inflight IPs of the chosen subnet with prefix delegation", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PrefixDelegation: lo.ToPtr(true)}))
+			subnets, err := awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+			Expect(err).To(BeNil())
+			Expect(subnets["test-zone-1a"].ID).To(Equal("subnet-test1"))
+			// 20 pods need two prefixes, so 100 - 32 = 68 IPs are left in subnet-test1 and subnet-test2 is chosen
+			subnets, err = awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+			Expect(err).To(BeNil())
+			Expect(subnets["test-zone-1a"].ID).To(Equal("subnet-test2"))
+		})
+		Context("Pod Subnets", func() {
+			BeforeEach(func() {
+				nodeClass.Spec.PodSubnetSelectorTerms = []v1beta1.SubnetSelectorTerm{{ID: "subnet-test3"}}
+				nodeClass.Status.PodSubnets = []v1beta1.Subnet{{ID: "subnet-test3", Zone: "test-zone-1a"}}
+				podSubnets, err := awsEnv.SubnetProvider.ListPodSubnets(ctx, nodeClass)
+				Expect(err).To(BeNil())
+				Expect(podSubnets).To(HaveLen(1))
+			})
+			It("should deduct the pods from the pod subnet and a single IP from the node subnet", func() {
+				// Without pod subnets, 100 - 20 - 20 = 60 IPs would be left in subnet-test1 and subnet-test2 would be chosen
+				for i := 0; i < 3; i++ {
+					subnets, err := awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+					Expect(err).To(BeNil())
+					Expect(subnets["test-zone-1a"].ID).To(Equal("subnet-test1"))
+					Expect(subnets["test-zone-1a"].PodSubnet.ID).To(Equal("subnet-test3"))
+				}
+				Expect(awsEnv.SubnetProvider.PodSubnetZones(ctx, nodeClass).UnsortedList()).To(ConsistOf("test-zone-1a"))
+			})
+			It("should not launch into zones whose pod subnets are exhausted", func() {
+				for i := 0; i < 5; i++ {
+					_, err := awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+					Expect(err).To(BeNil())
+				}
+				// 100 - 5 * 20 = 0 IPs are left in subnet-test3
+				Expect(awsEnv.SubnetProvider.PodSubnetZones(ctx, nodeClass).UnsortedList()).To(BeEmpty())
+				_, err := awsEnv.SubnetProvider.ZonalSubnetsForLaunch(ctx, nodeClass, instanceTypes, corev1beta1.CapacityTypeOnDemand)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
 	It("should not cause data races when calling List() simultaneously", func() {
 		wg := sync.WaitGroup{}
 		for i := 0; i < 10000; i++ {
@@ -17,10 +17,12 @@ package test
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	clock "k8s.io/utils/clock/testing"
 
 	corev1beta1 "sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 	"sigs.k8s.io/karpenter/pkg/operator/scheme"
@@ -29,12 +31,15 @@ import (
 	awscache "github.com/aws/karpenter-provider-aws/pkg/cache"
 	"github.com/aws/karpenter-provider-aws/pkg/fake"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/amifamily"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/capacityreservation"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instance"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instanceprofile"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/instancetype"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/launchtemplate"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/placementgroup"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/pricing"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/securitygroup"
+	"github.com/aws/karpenter-provider-aws/pkg/providers/snapshot"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/subnet"
 	"github.com/aws/karpenter-provider-aws/pkg/providers/version"
 
@@ -56,6 +61,8 @@ type Environment struct {
 	IAMAPI     *fake.IAMAPI
 	PricingAPI *fake.PricingAPI
 
+	Clock *clock.FakeClock
+
 	// Cache
 	EC2Cache                      *cache.Cache
 	KubernetesVersionCache        *cache.Cache
@@ -66,19 +73,25 @@ type Environment struct {
 	AvailableIPAdressCache        *cache.Cache
 	AssociatePublicIPAddressCache *cache.Cache
 	SecurityGroupCache            *cache.Cache
+	CapacityReservationCache      *cache.Cache
+	PlacementGroupCache           *cache.Cache
+	SnapshotCache                 *cache.Cache
 	InstanceProfileCache          *cache.Cache
 
 	// Providers
-	InstanceTypesProvider   *instancetype.DefaultProvider
-	InstanceProvider        *instance.DefaultProvider
-	SubnetProvider          *subnet.DefaultProvider
-	SecurityGroupProvider   *securitygroup.DefaultProvider
-	InstanceProfileProvider *instanceprofile.DefaultProvider
-	PricingProvider         *pricing.DefaultProvider
-	AMIProvider             *amifamily.DefaultProvider
-	AMIResolver             *amifamily.Resolver
-	VersionProvider         *version.DefaultProvider
-	LaunchTemplateProvider  *launchtemplate.DefaultProvider
+	InstanceTypesProvider       *instancetype.DefaultProvider
+	InstanceProvider            *instance.DefaultProvider
+	SubnetProvider              *subnet.DefaultProvider
+	SecurityGroupProvider       *securitygroup.DefaultProvider
+	CapacityReservationProvider *capacityreservation.DefaultProvider
+	PlacementGroupProvider      *placementgroup.DefaultProvider
+	SnapshotProvider            *snapshot.DefaultProvider
+	InstanceProfileProvider     *instanceprofile.DefaultProvider
+	PricingProvider             *pricing.DefaultProvider
+	AMIProvider                 *amifamily.DefaultProvider
+	AMIResolver                 *amifamily.Resolver
+	VersionProvider             *version.DefaultProvider
+	LaunchTemplateProvider      *launchtemplate.DefaultProvider
 }
 
 func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment {
@@ -98,18 +111,25 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 	availableIPAdressCache := cache.New(awscache.AvailableIPAddressTTL, awscache.DefaultCleanupInterval)
 	associatePublicIPAddressCache := cache.New(awscache.AssociatePublicIPAddressTTL, awscache.DefaultCleanupInterval)
 	securityGroupCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
+	capacityReservationCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
+	placementGroupCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
+	snapshotCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
 	instanceProfileCache := cache.New(awscache.DefaultTTL, awscache.DefaultCleanupInterval)
 	fakePricingAPI := &fake.PricingAPI{}
+	fakeClock := clock.NewFakeClock(time.Now())
 
 	// Providers
 	pricingProvider := pricing.NewDefaultProvider(ctx, fakePricingAPI, ec2api, fake.DefaultRegion)
 	subnetProvider := subnet.NewDefaultProvider(ec2api, subnetCache, availableIPAdressCache, associatePublicIPAddressCache)
 	securityGroupProvider := securitygroup.NewDefaultProvider(ec2api, securityGroupCache)
+	capacityReservationProvider := capacityreservation.NewDefaultProvider(ec2api, capacityReservationCache)
+	placementGroupProvider := placementgroup.NewDefaultProvider(ec2api, placementGroupCache)
+	snapshotProvider := snapshot.NewDefaultProvider(ec2api, snapshotCache)
 	versionProvider := version.NewDefaultProvider(env.KubernetesInterface, kubernetesVersionCache)
 	instanceProfileProvider := instanceprofile.NewDefaultProvider(fake.DefaultRegion, iamapi, instanceProfileCache)
-	amiProvider := amifamily.NewDefaultProvider(versionProvider, ssmapi, ec2api, ec2Cache)
-	amiResolver := amifamily.NewResolver(amiProvider)
-	instanceTypesProvider := instancetype.NewDefaultProvider(fake.DefaultRegion, instanceTypeCache, ec2api, subnetProvider, unavailableOfferingsCache, pricingProvider)
+	amiProvider := amifamily.NewDefaultProvider(versionProvider, ssmapi, ec2api, ec2Cache, fakeClock)
+	amiResolver := amifamily.NewResolver(amiProvider, fakeClock)
+	instanceTypesProvider := instancetype.NewDefaultProvider(fake.DefaultRegion, instanceTypeCache, ec2api, subnetProvider, unavailableOfferingsCache, pricingProvider, fakeClock)
 	launchTemplateProvider :=
 		launchtemplate.NewDefaultProvider(
 			ctx,
@@ -141,6 +161,8 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		IAMAPI:     iamapi,
 		PricingAPI: fakePricingAPI,
 
+		Clock: fakeClock,
+
 		EC2Cache:                      ec2Cache,
 		KubernetesVersionCache:        kubernetesVersionCache,
 		LaunchTemplateCache:           launchTemplateCache,
@@ -148,19 +170,25 @@ func NewEnvironment(ctx context.Context, env *coretest.Environment) *Environment
 		AvailableIPAdressCache:        availableIPAdressCache,
 		AssociatePublicIPAddressCache: associatePublicIPAddressCache,
 		SecurityGroupCache:            securityGroupCache,
+		CapacityReservationCache:      capacityReservationCache,
+		PlacementGroupCache:           placementGroupCache,
+		SnapshotCache:                 snapshotCache,
 		InstanceProfileCache:          instanceProfileCache,
 		UnavailableOfferingsCache:     unavailableOfferingsCache,
 
-		InstanceTypesProvider:   instanceTypesProvider,
-		InstanceProvider:        instanceProvider,
-		SubnetProvider:          subnetProvider,
-		SecurityGroupProvider:   securityGroupProvider,
-		LaunchTemplateProvider:  launchTemplateProvider,
-		InstanceProfileProvider: instanceProfileProvider,
-		PricingProvider:         pricingProvider,
-		AMIProvider:             amiProvider,
-		AMIResolver:             amiResolver,
-		VersionProvider:         versionProvider,
+		InstanceTypesProvider:       instanceTypesProvider,
+		InstanceProvider:            instanceProvider,
+		SubnetProvider:              subnetProvider,
+		SecurityGroupProvider:       securityGroupProvider,
+		CapacityReservationProvider: capacityReservationProvider,
+		PlacementGroupProvider:      placementGroupProvider,
+		SnapshotProvider:            snapshotProvider,
+		LaunchTemplateProvider:      launchTemplateProvider,
+		InstanceProfileProvider:     instanceProfileProvider,
+		PricingProvider:             pricingProvider,
+		AMIProvider:                 amiProvider,
+		AMIResolver:                 amiResolver,
+		VersionProvider:             versionProvider,
 	}
 }
 
@@ -170,6 +198,7 @@ func (env *Environment) Reset() {
 	env.SSMAPI.Reset()
 	env.IAMAPI.Reset()
 	env.PricingAPI.Reset()
+	env.Clock.SetTime(time.Now())
 	env.PricingProvider.Reset()
 	env.InstanceTypesProvider.Reset()
 
@@ -181,6 +210,9 @@ func (env *Environment) Reset() {
 	env.AssociatePublicIPAddressCache.Flush()
 	env.AvailableIPAdressCache.Flush()
 	env.SecurityGroupCache.Flush()
+	env.CapacityReservationCache.Flush()
+	env.PlacementGroupCache.Flush()
+	env.SnapshotCache.Flush()
 	env.InstanceProfileCache.Flush()
 
 	mfs, err := crmetrics.Registry.Gather()
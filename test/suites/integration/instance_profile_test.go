@@ -27,6 +27,7 @@ import (
 
 	coretest "sigs.k8s.io/karpenter/pkg/test"
 
+	"github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	awserrors "github.com/aws/karpenter-provider-aws/pkg/errors"
 
 	. "github.com/awslabs/operatorpkg/test/expectations"
@@ -91,6 +92,6 @@ var _ = Describe("InstanceProfile Generation", func() {
 	It("should have the EC2NodeClass status as not ready since Instance Profile was not resolved", func() {
 		nodeClass.Spec.Role = fmt.Sprintf("KarpenterNodeRole-%s", "invalidRole")
 		env.ExpectCreated(nodeClass)
-		ExpectStatusConditions(env, env.Client, 1*time.Minute, nodeClass, status.Condition{Type: status.ConditionReady, Status: metav1.ConditionFalse, Message: "Failed to resolve instance profile"})
+		ExpectStatusConditions(env, env.Client, 1*time.Minute, nodeClass, status.Condition{Type: v1beta1.ConditionTypeInstanceProfileReady, Status: metav1.ConditionFalse, Message: "Failed to resolve instance profile"})
 	})
 })
@@ -32,6 +32,7 @@ cat << EOF > controller-policy.json
                 "ec2:RunInstances",
                 "ec2:DescribeSubnets",
                 "ec2:DescribeSecurityGroups",
+                "ec2:DescribeSnapshots",
                 "ec2:DescribeLaunchTemplates",
                 "ec2:DescribeInstances",
                 "ec2:DescribeInstanceTypes",
@@ -42,6 +43,7 @@ cat << EOF > controller-policy.json
                 "ec2:CreateLaunchTemplate",
                 "ec2:CreateFleet",
                 "ec2:DescribeSpotPriceHistory",
+                "ec2:DescribeTags",
                 "pricing:GetProducts"
             ],
             "Effect": "Allow",
@@ -59,6 +61,25 @@ cat << EOF > controller-policy.json
             "Resource": "*",
             "Sid": "ConditionalEC2Termination"
         },
+        {
+            "Sid": "AllowScopedEC2NodeClassTagging",
+            "Effect": "Allow",
+            "Resource": [
+                "arn:${AWS_PARTITION}:ec2:${AWS_REGION}:*:instance/*",
+                "arn:${AWS_PARTITION}:ec2:${AWS_REGION}:*:volume/*",
+                "arn:${AWS_PARTITION}:ec2:${AWS_REGION}:*:network-interface/*",
+                "arn:${AWS_PARTITION}:ec2:${AWS_REGION}:*:spot-instances-request/*"
+            ],
+            "Action": [
+                "ec2:CreateTags",
+                "ec2:DeleteTags"
+            ],
+            "Condition": {
+                "StringEquals": {
+                    "aws:ResourceTag/kubernetes.io/cluster/${CLUSTER_NAME}": "owned"
+                }
+            }
+        },
         {
             "Effect": "Allow",
             "Action": "iam:PassRole",